@@ -39,6 +39,17 @@ func main() {
 	accountRepo := repository.NewAccountRepo(db)
 	txnRepo := repository.NewTransactionRepo(db)
 	asyncTxRepo := repository.NewAsyncTransactionRepo(db)
+	ledgerRepo := repository.NewLedgerRepo(db)
+	fxRateRepo := repository.NewFXRateRepo(db)
+	holdRepo := repository.NewHoldRepo(db)
+	feeRuleRepo := repository.NewFeeRuleRepo(db)
+	limitRepo := repository.NewLimitRepo(db)
+	standingOrderRepo := repository.NewStandingOrderRepo(db)
+	interestRepo := repository.NewInterestRepo(db)
+	snapshotRepo := repository.NewBalanceSnapshotRepo(db)
+	idempotencyRepo := repository.NewIdempotencyRepo(db)
+	reconciliationRepo := repository.NewReconciliationRepo(db)
+	escrowRepo := repository.NewEscrowRepo(db)
 
 	// infrastructure
 	txManager := repository.NewTxManager(db)
@@ -47,10 +58,16 @@ func main() {
 	kafkaConsumer := infrastructure.NewKafkaConsumer(cfg.Kafka.Brokers, log)
 	defer kafkaProducer.Close()
 
+	approvalThresholds, err := cfg.Approval.ThresholdsByCurrency()
+	if err != nil {
+		log.Fatal("invalid approval thresholds", "thresholds", cfg.Approval.Thresholds, "err", err)
+	}
+
 	// service
 	svc := application.NewTransferService(
-		accountRepo, txnRepo, asyncTxRepo,
-		txManager, lockManager, kafkaProducer, log,
+		accountRepo, txnRepo, asyncTxRepo, ledgerRepo, fxRateRepo, holdRepo, feeRuleRepo, limitRepo, standingOrderRepo,
+		interestRepo, snapshotRepo, idempotencyRepo, reconciliationRepo, escrowRepo, txManager, lockManager, kafkaProducer,
+		approvalThresholds, log,
 	)
 
 	ctx, cancel := context.WithCancel(context.Background())
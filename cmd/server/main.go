@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/maneeshsagar/tps/config"
@@ -9,6 +10,7 @@ import (
 	"github.com/maneeshsagar/tps/internal/application"
 	"github.com/maneeshsagar/tps/internal/infrastructure"
 	"github.com/maneeshsagar/tps/logger"
+	"github.com/maneeshsagar/tps/pkg/currency"
 )
 
 func main() {
@@ -37,6 +39,17 @@ func main() {
 	accountRepo := repository.NewAccountRepo(db)
 	txnRepo := repository.NewTransactionRepo(db)
 	asyncTxRepo := repository.NewAsyncTransactionRepo(db)
+	ledgerRepo := repository.NewLedgerRepo(db)
+	fxRateRepo := repository.NewFXRateRepo(db)
+	holdRepo := repository.NewHoldRepo(db)
+	feeRuleRepo := repository.NewFeeRuleRepo(db)
+	limitRepo := repository.NewLimitRepo(db)
+	standingOrderRepo := repository.NewStandingOrderRepo(db)
+	interestRepo := repository.NewInterestRepo(db)
+	snapshotRepo := repository.NewBalanceSnapshotRepo(db)
+	idempotencyRepo := repository.NewIdempotencyRepo(db)
+	reconciliationRepo := repository.NewReconciliationRepo(db)
+	escrowRepo := repository.NewEscrowRepo(db)
 
 	// infrastructure
 	txManager := repository.NewTxManager(db)
@@ -44,13 +57,55 @@ func main() {
 	kafkaProducer := infrastructure.NewKafkaProducer(cfg.Kafka.Brokers)
 	defer kafkaProducer.Close()
 
+	approvalThresholds, err := cfg.Approval.ThresholdsByCurrency()
+	if err != nil {
+		log.Fatal("invalid approval thresholds", "thresholds", cfg.Approval.Thresholds, "err", err)
+	}
+
 	// service (includes sync + async transfer)
 	svc := application.NewTransferService(
-		accountRepo, txnRepo, asyncTxRepo,
-		txManager, lockManager, kafkaProducer, log,
+		accountRepo, txnRepo, asyncTxRepo, ledgerRepo, fxRateRepo, holdRepo, feeRuleRepo, limitRepo, standingOrderRepo,
+		interestRepo, snapshotRepo, idempotencyRepo, reconciliationRepo, escrowRepo, txManager, lockManager, kafkaProducer,
+		approvalThresholds, log,
 	)
 
-	router := http.NewRouter(svc)
+	// seed the rate table from a local file, if configured
+	if cfg.FX.RatesFile != "" {
+		rates, err := infrastructure.LoadFXRatesFile(cfg.FX.RatesFile)
+		if err != nil {
+			log.Fatal("failed to load fx rates", "err", err)
+		}
+		for _, r := range rates {
+			if _, err := svc.SetFXRate(context.Background(), r.Base, r.Quote, r.Rate); err != nil {
+				log.Fatal("failed to save fx rate", "base", r.Base, "quote", r.Quote, "err", err)
+			}
+		}
+		log.Info("fx rates loaded", "file", cfg.FX.RatesFile, "count", len(rates))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// background jobs
+	go infrastructure.RunPeriodic(ctx, "hold-expiry", cfg.Jobs.HoldExpiryInterval(), log, svc.ExpireHolds)
+	go infrastructure.RunPeriodic(ctx, "escrow-expiry", cfg.Jobs.EscrowExpiryInterval(), log, svc.ExpireEscrows)
+	go infrastructure.RunPeriodic(ctx, "scheduled-transfers", cfg.Jobs.SchedulerInterval(), log, svc.DispatchScheduledTransfers)
+	go infrastructure.RunPeriodic(ctx, "standing-orders", cfg.Jobs.StandingOrdersInterval(), log, svc.RunStandingOrders)
+	go infrastructure.RunPeriodic(ctx, "balance-snapshots", cfg.Jobs.BalanceSnapshotInterval(), log, svc.TakeBalanceSnapshots)
+	go infrastructure.RunPeriodic(ctx, "idempotency-keys", cfg.Jobs.IdempotencyPurgeInterval(), log, svc.PurgeIdempotencyKeys)
+	go infrastructure.RunPeriodic(ctx, "reconciliation", cfg.Jobs.ReconciliationInterval(), log, svc.RunReconciliation)
+	if cfg.Interest.AnnualRate != "" {
+		rate, err := currency.ParsePercent(cfg.Interest.AnnualRate)
+		if err != nil {
+			log.Fatal("invalid interest rate", "rate", cfg.Interest.AnnualRate, "err", err)
+		}
+		expenseAccountID := cfg.Interest.ExpenseAccountID
+		go infrastructure.RunPeriodic(ctx, "interest", cfg.Jobs.InterestInterval(), log, func(ctx context.Context) error {
+			return svc.RunInterest(ctx, rate, expenseAccountID)
+		})
+	}
+
+	router := http.NewRouter(svc, cfg.Idempotency.KeyTTL())
 
 	addr := fmt.Sprintf(":%d", cfg.Server.Port)
 	log.Info("server starting", "addr", addr)
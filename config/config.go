@@ -1,18 +1,26 @@
 package config
 
 import (
+	"fmt"
 	"log"
 	"os"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/maneeshsagar/tps/pkg/currency"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Postgres PostgresConfig
-	Kafka    KafkaConfig
-	Log      LogConfig
+	Server      ServerConfig
+	Postgres    PostgresConfig
+	Kafka       KafkaConfig
+	Log         LogConfig
+	FX          FXConfig
+	Jobs        JobsConfig
+	Interest    InterestConfig
+	Idempotency IdempotencyConfig
+	Approval    ApprovalConfig
 }
 
 type ServerConfig struct {
@@ -40,6 +48,128 @@ type KafkaConfig struct {
 	Brokers []string
 }
 
+type FXConfig struct {
+	// RatesFile is an optional CSV or JSON file of rates loaded at startup
+	RatesFile string
+}
+
+// InterestConfig sets how savings accounts earn interest. No interest is paid
+// unless AnnualRate is set.
+type InterestConfig struct {
+	// AnnualRate is a percentage, e.g. "3.5"
+	AnnualRate string
+	// ExpenseAccountID is the system account interest is paid from
+	ExpenseAccountID int64
+}
+
+// IdempotencyConfig sets how long an Idempotency-Key and its stored response are kept
+type IdempotencyConfig struct {
+	KeyTTLHours int
+}
+
+func (i IdempotencyConfig) KeyTTL() time.Duration {
+	return time.Duration(i.KeyTTLHours) * time.Hour
+}
+
+// ApprovalConfig sets which transfers need maker-checker approval before they run
+type ApprovalConfig struct {
+	// Thresholds lists the largest amount per currency that runs without approval,
+	// e.g. "USD:10000,INR:500000". Currencies not listed never need approval.
+	Thresholds string
+}
+
+// ThresholdsByCurrency reads Thresholds into amounts in minor units by currency code
+func (a ApprovalConfig) ThresholdsByCurrency() (map[string]int64, error) {
+	thresholds := make(map[string]int64)
+	for _, item := range strings.Split(a.Thresholds, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		code, amount, ok := strings.Cut(item, ":")
+		if !ok {
+			return nil, fmt.Errorf("%q is not CURRENCY:AMOUNT", item)
+		}
+		cur, err := currency.Lookup(strings.TrimSpace(code))
+		if err != nil {
+			return nil, err
+		}
+		threshold, err := cur.Parse(strings.TrimSpace(amount))
+		if err != nil || threshold < 0 {
+			return nil, fmt.Errorf("invalid threshold %q for %s", amount, cur.Code)
+		}
+		thresholds[cur.Code] = threshold
+	}
+	return thresholds, nil
+}
+
+// JobsConfig holds the intervals of the background jobs run by the app server
+type JobsConfig struct {
+	HoldExpiryIntervalSeconds       int
+	SchedulerIntervalSeconds        int
+	StandingOrdersIntervalSeconds   int
+	InterestIntervalSeconds         int
+	BalanceSnapshotIntervalSeconds  int
+	IdempotencyPurgeIntervalSeconds int
+	ReconciliationIntervalSeconds   int
+	EscrowExpiryIntervalSeconds     int
+}
+
+// Validate checks that every job runs at a positive interval
+func (j JobsConfig) Validate() error {
+	intervals := []struct {
+		env     string
+		seconds int
+	}{
+		{"JOBS_HOLD_EXPIRY_INTERVAL_SECONDS", j.HoldExpiryIntervalSeconds},
+		{"JOBS_SCHEDULER_INTERVAL_SECONDS", j.SchedulerIntervalSeconds},
+		{"JOBS_STANDING_ORDERS_INTERVAL_SECONDS", j.StandingOrdersIntervalSeconds},
+		{"JOBS_INTEREST_INTERVAL_SECONDS", j.InterestIntervalSeconds},
+		{"JOBS_BALANCE_SNAPSHOT_INTERVAL_SECONDS", j.BalanceSnapshotIntervalSeconds},
+		{"JOBS_IDEMPOTENCY_PURGE_INTERVAL_SECONDS", j.IdempotencyPurgeIntervalSeconds},
+		{"JOBS_RECONCILIATION_INTERVAL_SECONDS", j.ReconciliationIntervalSeconds},
+		{"JOBS_ESCROW_EXPIRY_INTERVAL_SECONDS", j.EscrowExpiryIntervalSeconds},
+	}
+	for _, i := range intervals {
+		if i.seconds <= 0 {
+			return fmt.Errorf("%s must be positive, got %d", i.env, i.seconds)
+		}
+	}
+	return nil
+}
+
+func (j JobsConfig) HoldExpiryInterval() time.Duration {
+	return time.Duration(j.HoldExpiryIntervalSeconds) * time.Second
+}
+
+func (j JobsConfig) SchedulerInterval() time.Duration {
+	return time.Duration(j.SchedulerIntervalSeconds) * time.Second
+}
+
+func (j JobsConfig) StandingOrdersInterval() time.Duration {
+	return time.Duration(j.StandingOrdersIntervalSeconds) * time.Second
+}
+
+func (j JobsConfig) InterestInterval() time.Duration {
+	return time.Duration(j.InterestIntervalSeconds) * time.Second
+}
+
+func (j JobsConfig) BalanceSnapshotInterval() time.Duration {
+	return time.Duration(j.BalanceSnapshotIntervalSeconds) * time.Second
+}
+
+func (j JobsConfig) IdempotencyPurgeInterval() time.Duration {
+	return time.Duration(j.IdempotencyPurgeIntervalSeconds) * time.Second
+}
+
+func (j JobsConfig) ReconciliationInterval() time.Duration {
+	return time.Duration(j.ReconciliationIntervalSeconds) * time.Second
+}
+
+func (j JobsConfig) EscrowExpiryInterval() time.Duration {
+	return time.Duration(j.EscrowExpiryIntervalSeconds) * time.Second
+}
+
 func (p PostgresConfig) ConnMaxLifetime() time.Duration {
 	return time.Duration(p.ConnMaxLifetimeMinutes) * time.Minute
 }
@@ -67,6 +197,32 @@ func LoadConfig() (*Config, error) {
 		Kafka: KafkaConfig{
 			Brokers: strings.Split(getEnv("KAFKA_BROKERS", "localhost:9092"), ","),
 		},
+		FX: FXConfig{
+			RatesFile: getEnv("FX_RATES_FILE", ""),
+		},
+		Jobs: JobsConfig{
+			HoldExpiryIntervalSeconds:       getEnvInt("JOBS_HOLD_EXPIRY_INTERVAL_SECONDS", 60),
+			SchedulerIntervalSeconds:        getEnvInt("JOBS_SCHEDULER_INTERVAL_SECONDS", 10),
+			StandingOrdersIntervalSeconds:   getEnvInt("JOBS_STANDING_ORDERS_INTERVAL_SECONDS", 60),
+			InterestIntervalSeconds:         getEnvInt("JOBS_INTEREST_INTERVAL_SECONDS", 3600),
+			BalanceSnapshotIntervalSeconds:  getEnvInt("JOBS_BALANCE_SNAPSHOT_INTERVAL_SECONDS", 3600),
+			IdempotencyPurgeIntervalSeconds: getEnvInt("JOBS_IDEMPOTENCY_PURGE_INTERVAL_SECONDS", 3600),
+			ReconciliationIntervalSeconds:   getEnvInt("JOBS_RECONCILIATION_INTERVAL_SECONDS", 3600),
+			EscrowExpiryIntervalSeconds:     getEnvInt("JOBS_ESCROW_EXPIRY_INTERVAL_SECONDS", 60),
+		},
+		Idempotency: IdempotencyConfig{
+			KeyTTLHours: getEnvInt("IDEMPOTENCY_KEY_TTL_HOURS", 24),
+		},
+		Approval: ApprovalConfig{
+			Thresholds: getEnv("APPROVAL_THRESHOLDS", ""),
+		},
+		Interest: InterestConfig{
+			AnnualRate:       getEnv("INTEREST_ANNUAL_RATE", ""),
+			ExpenseAccountID: int64(getEnvInt("INTEREST_EXPENSE_ACCOUNT_ID", 0)),
+		},
+	}
+	if err := cfg.Jobs.Validate(); err != nil {
+		return nil, err
 	}
 	return cfg, nil
 }
@@ -0,0 +1,55 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maneeshsagar/tps/internal/adapters/http/dto"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+)
+
+func (h *Handler) ChangeAccountStatus(c *gin.Context) {
+	id, ok := parseAccountID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ChangeAccountStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid request"})
+		return
+	}
+
+	acc, err := h.svc.ChangeAccountStatus(c, id, domain.AccountStatus(req.Status), domain.FreezeScope(req.FreezeScope), req.Reason)
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toAccountResponse(acc))
+}
+
+func (h *Handler) ListAccountStatusChanges(c *gin.Context) {
+	id, ok := parseAccountID(c)
+	if !ok {
+		return
+	}
+
+	changes, err := h.svc.ListAccountStatusChanges(c, id)
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	resp := make([]dto.AccountStatusChangeResponse, 0, len(changes))
+	for _, ch := range changes {
+		resp = append(resp, dto.AccountStatusChangeResponse{
+			From:        string(ch.From),
+			To:          string(ch.To),
+			FreezeScope: string(ch.FreezeScope),
+			Reason:      ch.Reason,
+			CreatedAt:   ch.CreatedAt,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,104 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/maneeshsagar/tps/internal/adapters/http/dto"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"github.com/maneeshsagar/tps/pkg/currency"
+)
+
+// OperatorIDHeader identifies the operator behind a request. It is recorded as the maker
+// of a transfer held for approval and is required of the checker who decides it.
+const OperatorIDHeader = "X-Operator-ID"
+
+// requestApproval holds a transfer the service refused with domain.ErrApprovalRequired
+// until another operator approves it. Only a request with an operator id can be held.
+func (h *Handler) requestApproval(c *gin.Context, req dto.CreateTransactionRequest, amount int64, cur currency.Currency, executeAt time.Time) {
+	operator := strings.TrimSpace(c.GetHeader(OperatorIDHeader))
+	if operator == "" {
+		h.handleErr(c, domain.ErrOperatorRequired)
+		return
+	}
+
+	id, err := h.svc.RequestTransferApproval(c, req.SourceAccountID, req.DestinationAccountID, amount, cur.Code, executeAt, transferDetails(req), operator)
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	resp := dto.AsyncTransactionResponse{
+		TransactionID: id.String(),
+		Status:        string(domain.TxStatusPendingApproval),
+	}
+	if !executeAt.IsZero() {
+		resp.ExecuteAt = &executeAt
+	}
+	c.JSON(http.StatusAccepted, resp)
+}
+
+// ListPendingApprovals returns the transfers waiting for approval, oldest first
+func (h *Handler) ListPendingApprovals(c *gin.Context) {
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid limit"})
+			return
+		}
+		limit = n
+	}
+
+	txs, err := h.svc.ListPendingApprovals(c, limit)
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	resp := make([]dto.AsyncStatusResponse, 0, len(txs))
+	for _, tx := range txs {
+		resp = append(resp, toAsyncStatusResponse(tx))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) ApproveTransfer(c *gin.Context) {
+	h.decideTransfer(c, domain.ApprovalApproved)
+}
+
+func (h *Handler) RejectTransfer(c *gin.Context) {
+	h.decideTransfer(c, domain.ApprovalRejected)
+}
+
+func (h *Handler) decideTransfer(c *gin.Context, decision domain.ApprovalDecision) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid transaction id"})
+		return
+	}
+
+	var req dto.DecideApprovalRequest
+	if err := c.ShouldBindJSON(&req); err != nil && c.Request.ContentLength > 0 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid request"})
+		return
+	}
+
+	operator := c.GetHeader(OperatorIDHeader)
+	var tx *domain.AsyncTransaction
+	if decision == domain.ApprovalApproved {
+		tx, err = h.svc.ApproveTransfer(c, id, operator, req.Reason)
+	} else {
+		tx, err = h.svc.RejectTransfer(c, id, operator, req.Reason)
+	}
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toAsyncStatusResponse(tx))
+}
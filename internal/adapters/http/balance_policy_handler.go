@@ -0,0 +1,56 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maneeshsagar/tps/internal/adapters/http/dto"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+)
+
+func (h *Handler) SetBalancePolicy(c *gin.Context) {
+	id, ok := parseAccountID(c)
+	if !ok {
+		return
+	}
+
+	// the bounds are in the account's currency
+	cur, ok := h.sourceCurrency(c, id)
+	if !ok {
+		return
+	}
+	var req dto.SetBalancePolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid request"})
+		return
+	}
+
+	var policy domain.BalancePolicy
+	fields := []struct {
+		in  string
+		out *int64
+	}{
+		{req.OverdraftLimit, &policy.OverdraftLimit},
+		{req.MinimumBalance, &policy.MinimumBalance},
+		{req.MaximumBalance, &policy.MaximumBalance},
+	}
+	for _, f := range fields {
+		if f.in == "" {
+			continue
+		}
+		v, err := cur.Parse(f.in)
+		if err != nil || v < 0 {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid balance policy amount"})
+			return
+		}
+		*f.out = v
+	}
+
+	acc, err := h.svc.SetBalancePolicy(c, id, policy)
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toAccountResponse(acc))
+}
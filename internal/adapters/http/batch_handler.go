@@ -0,0 +1,74 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maneeshsagar/tps/internal/adapters/http/dto"
+	"github.com/maneeshsagar/tps/internal/application"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"github.com/maneeshsagar/tps/pkg/currency"
+)
+
+func (h *Handler) CreateBatchTransaction(c *gin.Context) {
+	var req dto.CreateBatchTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid request"})
+		return
+	}
+	if len(req.Legs) == 0 || len(req.Legs) > application.MaxBatchLegs {
+		h.handleErr(c, domain.ErrInvalidBatch)
+		return
+	}
+
+	// each leg is quoted in its own source account's currency
+	legs := make([]application.TransferLeg, 0, len(req.Legs))
+	curs := make([]currency.Currency, 0, len(req.Legs))
+	for i, l := range req.Legs {
+		if l.SourceAccountID == l.DestinationAccountID {
+			c.JSON(http.StatusBadRequest, dto.BatchErrorResponse{Error: "same account", FailedLeg: i})
+			return
+		}
+		acc, err := h.svc.GetAccount(c, l.SourceAccountID)
+		if err != nil {
+			status, msg := errorStatus(err)
+			c.JSON(status, dto.BatchErrorResponse{Error: msg, FailedLeg: i})
+			return
+		}
+		cur, err := currency.Lookup(acc.Currency)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.BatchErrorResponse{Error: "unsupported currency", FailedLeg: i})
+			return
+		}
+		amount, err := cur.Parse(l.Amount)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.BatchErrorResponse{Error: "invalid amount format", FailedLeg: i})
+			return
+		}
+		if amount <= 0 {
+			c.JSON(http.StatusBadRequest, dto.BatchErrorResponse{Error: "amount must be positive", FailedLeg: i})
+			return
+		}
+		legs = append(legs, application.TransferLeg{FromAccount: l.SourceAccountID, ToAccount: l.DestinationAccountID, Amount: amount, Details: transferDetails(l)})
+		curs = append(curs, cur)
+	}
+
+	results, err := h.svc.TransferBatch(c, legs)
+	if err != nil {
+		var legErr *application.BatchLegError
+		if errors.As(err, &legErr) {
+			status, msg := errorStatus(legErr.Err)
+			c.JSON(status, dto.BatchErrorResponse{Error: msg, FailedLeg: legErr.Leg})
+			return
+		}
+		h.handleErr(c, err)
+		return
+	}
+
+	resp := dto.BatchTransactionResponse{Transactions: make([]dto.TransactionResponse, 0, len(results))}
+	for i, result := range results {
+		resp.Transactions = append(resp.Transactions, transferResponse(req.Legs[i].SourceAccountID, req.Legs[i].DestinationAccountID, legs[i].Amount, curs[i], result))
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -1,12 +1,184 @@
 package dto
 
+import "time"
+
 type CreateAccountRequest struct {
-	AccountID      int64  `json:"account_id" `
+	AccountID int64 `json:"account_id" `
+	// InitialBalance is no longer supported: accounts open at zero and are funded by a deposit
 	InitialBalance string `json:"initial_balance" `
+	Currency       string `json:"currency"`
+	// Type is customer_wallet (the default), merchant, savings, system, suspense, escrow
+	// or treasury
+	Type        string            `json:"type"`
+	OwnerName   string            `json:"owner_name"`
+	CustomerRef string            `json:"customer_ref"`
+	Labels      map[string]string `json:"labels"`
+}
+
+// ChangeAccountStatusRequest moves an account to active, frozen or closed.
+// FreezeScope is debits (the default) or all, and only applies when freezing.
+type ChangeAccountStatusRequest struct {
+	Status      string `json:"status" binding:"required"`
+	FreezeScope string `json:"freeze_scope"`
+	Reason      string `json:"reason" binding:"required"`
+}
+
+// SetBalancePolicyRequest replaces the balance policy of an account; amounts are in
+// the account's currency and an empty or zero value leaves that bound off
+type SetBalancePolicyRequest struct {
+	OverdraftLimit string `json:"overdraft_limit"`
+	MinimumBalance string `json:"minimum_balance"`
+	MaximumBalance string `json:"maximum_balance"`
 }
 
 type CreateTransactionRequest struct {
 	SourceAccountID      int64  `json:"source_account_id"`
 	DestinationAccountID int64  `json:"destination_account_id"`
 	Amount               string `json:"amount" binding:"required"`
+	Description          string `json:"description"`
+	// ExternalRef is the client's own reference, unique per source account
+	ExternalRef string            `json:"external_ref"`
+	Metadata    map[string]string `json:"metadata"`
+}
+
+// FundingRequest is a deposit into or a withdrawal from an account, in its currency
+type FundingRequest struct {
+	Amount      string            `json:"amount" binding:"required"`
+	Description string            `json:"description"`
+	ExternalRef string            `json:"external_ref"`
+	Metadata    map[string]string `json:"metadata"`
+}
+
+type CreateBatchTransactionRequest struct {
+	Legs []CreateTransactionRequest `json:"legs" binding:"required,dive"`
+}
+
+// CreateSplitTransactionRequest fans one debit out to several destinations. Legs
+// either all carry an amount, whose sum is debited, or all carry a percentage of Amount.
+// The description and metadata are copied to every leg; ExternalRef is the parent's only.
+type CreateSplitTransactionRequest struct {
+	SourceAccountID int64             `json:"source_account_id"`
+	Amount          string            `json:"amount"`
+	Legs            []SplitLegRequest `json:"legs" binding:"required,dive"`
+	Description     string            `json:"description"`
+	ExternalRef     string            `json:"external_ref"`
+	Metadata        map[string]string `json:"metadata"`
+}
+
+type SplitLegRequest struct {
+	DestinationAccountID int64  `json:"destination_account_id"`
+	Amount               string `json:"amount"`
+	Percent              string `json:"percent"`
+}
+
+type CreateAsyncTransactionRequest struct {
+	CreateTransactionRequest
+	// ExecuteAt optionally schedules the transfer for a future time
+	ExecuteAt *time.Time `json:"execute_at"`
+}
+
+// DecideApprovalRequest approves or rejects a transfer pending approval; the operator
+// deciding it is taken from the X-Operator-ID header
+type DecideApprovalRequest struct {
+	Reason string `json:"reason"`
+}
+
+type SetFXRateRequest struct {
+	Rate string `json:"rate" binding:"required"`
+}
+
+// CreateFeeRuleRequest defines a fee. Amounts are in Currency and percentages
+// allow two decimal places. A flat rule needs Flat, a percentage rule Percent
+// (optionally bounded by Min and Max) and a tiered rule Tiers.
+type CreateFeeRuleRequest struct {
+	Name             string           `json:"name" binding:"required"`
+	Type             string           `json:"type" binding:"required"`
+	Currency         string           `json:"currency" binding:"required"`
+	AccountType      string           `json:"account_type"`
+	Flat             string           `json:"flat"`
+	Percent          string           `json:"percent"`
+	Min              string           `json:"min"`
+	Max              string           `json:"max"`
+	Tiers            []FeeTierRequest `json:"tiers"`
+	RevenueAccountID int64            `json:"revenue_account_id" binding:"required"`
+	Priority         int              `json:"priority"`
+}
+
+// FeeTierRequest applies to amounts up to and including UpTo; the last tier may omit it
+type FeeTierRequest struct {
+	UpTo    string `json:"up_to"`
+	Flat    string `json:"flat"`
+	Percent string `json:"percent"`
+}
+
+// SetLimitRequest sets transfer limits; amounts are in the account's or tier's
+// currency and an empty or zero value leaves that limit off
+type SetLimitRequest struct {
+	MaxPerTransaction string `json:"max_per_transaction"`
+	DailyOutgoing     string `json:"daily_outgoing"`
+	MonthlyOutgoing   string `json:"monthly_outgoing"`
+	HourlyTransfers   int64  `json:"hourly_transfers"`
+}
+
+type CreateHoldRequest struct {
+	SourceAccountID      int64  `json:"source_account_id"`
+	DestinationAccountID int64  `json:"destination_account_id"`
+	Amount               string `json:"amount" binding:"required"`
+	ExpiresInSeconds     int64  `json:"expires_in_seconds"`
+}
+
+type CaptureHoldRequest struct {
+	// Amount is optional, an empty amount captures the full hold
+	Amount string `json:"amount"`
+}
+
+// CreateEscrowRequest moves Amount from the buyer into the escrow account, in their shared currency.
+// BuyerParty, SellerParty and Arbiter are the X-Party-ID values allowed to act on the escrow.
+type CreateEscrowRequest struct {
+	BuyerAccountID   int64  `json:"buyer_account_id"`
+	SellerAccountID  int64  `json:"seller_account_id"`
+	EscrowAccountID  int64  `json:"escrow_account_id"`
+	BuyerParty       string `json:"buyer_party"`
+	SellerParty      string `json:"seller_party"`
+	Arbiter          string `json:"arbiter"`
+	Amount           string `json:"amount" binding:"required"`
+	Description      string `json:"description"`
+	ExpiresInSeconds int64  `json:"expires_in_seconds"`
+}
+
+type DisputeEscrowRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type SettleEscrowRequest struct {
+	// Amount is optional, an empty amount settles whatever remains in escrow
+	Amount string `json:"amount"`
+}
+
+type CreateReversalRequest struct {
+	// Amount is optional, an empty amount reverses whatever has not been reversed yet
+	Amount string `json:"amount"`
+}
+
+type CreateStandingOrderRequest struct {
+	SourceAccountID      int64  `json:"source_account_id"`
+	DestinationAccountID int64  `json:"destination_account_id"`
+	Amount               string `json:"amount" binding:"required"`
+	// Frequency is daily, weekly, monthly or cron; cron needs a 5-field Cron expression in UTC
+	Frequency           string     `json:"frequency" binding:"required"`
+	Cron                string     `json:"cron"`
+	StartAt             *time.Time `json:"start_at"`
+	EndAt               *time.Time `json:"end_at"`
+	MaxRuns             int        `json:"max_runs"`
+	OnInsufficientFunds string     `json:"on_insufficient_funds"`
+}
+
+// UpdateStandingOrderRequest only changes the fields that are present
+type UpdateStandingOrderRequest struct {
+	Amount              *string    `json:"amount"`
+	EndAt               *time.Time `json:"end_at"`
+	MaxRuns             *int       `json:"max_runs"`
+	OnInsufficientFunds *string    `json:"on_insufficient_funds"`
+	// Status is suspended to pause the order or active to resume it
+	Status *string `json:"status"`
 }
@@ -1,29 +1,245 @@
 package dto
 
+import "time"
+
+// AccountResponse is an account. AvailableToSpend is left out for account types that
+// may go negative without bound.
 type AccountResponse struct {
-	AccountID int64  `json:"account_id"`
-	Balance   string `json:"balance"`
+	AccountID        int64             `json:"account_id"`
+	Currency         string            `json:"currency"`
+	Balance          string            `json:"balance"`
+	HeldBalance      string            `json:"held_balance"`
+	AvailableBalance string            `json:"available_balance"`
+	AvailableToSpend string            `json:"available_to_spend,omitempty"`
+	OverdraftLimit   string            `json:"overdraft_limit,omitempty"`
+	MinimumBalance   string            `json:"minimum_balance,omitempty"`
+	MaximumBalance   string            `json:"maximum_balance,omitempty"`
+	Type             string            `json:"type"`
+	OwnerName        string            `json:"owner_name,omitempty"`
+	CustomerRef      string            `json:"customer_ref,omitempty"`
+	Labels           map[string]string `json:"labels,omitempty"`
+	Status           string            `json:"status"`
+	FreezeScope      string            `json:"freeze_scope,omitempty"`
+	StatusReason     string            `json:"status_reason,omitempty"`
+	CreatedAt        time.Time         `json:"created_at"`
+}
+
+// BalanceAsOfResponse is the ledger balance of an account at a past point in time
+type BalanceAsOfResponse struct {
+	AccountID int64     `json:"account_id"`
+	Currency  string    `json:"currency"`
+	Balance   string    `json:"balance"`
+	AsOf      time.Time `json:"as_of"`
+}
+
+type AccountStatusChangeResponse struct {
+	From        string    `json:"from"`
+	To          string    `json:"to"`
+	FreezeScope string    `json:"freeze_scope,omitempty"`
+	Reason      string    `json:"reason"`
+	CreatedAt   time.Time `json:"created_at"`
 }
 
 type TransactionResponse struct {
-	TransactionID        string `json:"transaction_id"`
-	SourceAccountID      int64  `json:"source_account_id"`
-	DestinationAccountID int64  `json:"destination_account_id"`
-	Amount               string `json:"amount"`
+	TransactionID        string     `json:"transaction_id"`
+	Kind                 string     `json:"kind"`
+	ReversalOf           string     `json:"reversal_of,omitempty"`
+	ParentID             string     `json:"parent_id,omitempty"`
+	SourceAccountID      int64      `json:"source_account_id"`
+	DestinationAccountID int64      `json:"destination_account_id,omitempty"`
+	Amount               string     `json:"amount"`
+	Currency             string     `json:"currency"`
+	ConvertedAmount      string     `json:"converted_amount,omitempty"`
+	DestinationCurrency  string     `json:"destination_currency,omitempty"`
+	FXRate               string     `json:"fx_rate,omitempty"`
+	FXRateAt             *time.Time `json:"fx_rate_at,omitempty"`
+	// Fee is charged to the source on top of Amount, in the source currency
+	Fee         string            `json:"fee,omitempty"`
+	Description string            `json:"description,omitempty"`
+	ExternalRef string            `json:"external_ref,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+// TransactionDetailResponse is a transaction with its reversals and, for async or
+// scheduled transfers, the submission it came from
+type TransactionDetailResponse struct {
+	TransactionResponse
+	Reversals       []TransactionResponse `json:"reversals"`
+	AsyncSubmission *AsyncStatusResponse  `json:"async_submission,omitempty"`
+}
+
+// TransactionListResponse is one page of transactions; pass NextCursor as cursor to
+// get the next one
+type TransactionListResponse struct {
+	Transactions []TransactionResponse `json:"transactions"`
+	NextCursor   string                `json:"next_cursor,omitempty"`
+}
+
+type BatchTransactionResponse struct {
+	Transactions []TransactionResponse `json:"transactions"`
+}
+
+// BatchErrorResponse is returned when a batch is rolled back, FailedLeg is the
+// zero-based index of the leg that failed
+type BatchErrorResponse struct {
+	Error     string `json:"error"`
+	FailedLeg int    `json:"failed_leg"`
+}
+
+// SplitTransactionResponse is the parent of a split payment with its legs
+type SplitTransactionResponse struct {
+	Transaction TransactionResponse   `json:"transaction"`
+	Legs        []TransactionResponse `json:"legs"`
+}
+
+type FeeRuleResponse struct {
+	FeeRuleID        string            `json:"fee_rule_id"`
+	Name             string            `json:"name"`
+	Type             string            `json:"type"`
+	Currency         string            `json:"currency"`
+	AccountType      string            `json:"account_type,omitempty"`
+	Flat             string            `json:"flat,omitempty"`
+	Percent          string            `json:"percent,omitempty"`
+	Min              string            `json:"min,omitempty"`
+	Max              string            `json:"max,omitempty"`
+	Tiers            []FeeTierResponse `json:"tiers,omitempty"`
+	RevenueAccountID int64             `json:"revenue_account_id"`
+	Priority         int               `json:"priority"`
+	Active           bool              `json:"active"`
+	CreatedAt        time.Time         `json:"created_at"`
+}
+
+type FeeTierResponse struct {
+	UpTo    string `json:"up_to,omitempty"`
+	Flat    string `json:"flat,omitempty"`
+	Percent string `json:"percent,omitempty"`
+}
+
+type FXRateResponse struct {
+	Base      string    `json:"base"`
+	Quote     string    `json:"quote"`
+	Rate      string    `json:"rate"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 type AsyncTransactionResponse struct {
-	TransactionID string `json:"transaction_id"`
-	Status        string `json:"status"`
+	TransactionID string     `json:"transaction_id"`
+	Status        string     `json:"status"`
+	ExecuteAt     *time.Time `json:"execute_at,omitempty"`
 }
 
 type AsyncStatusResponse struct {
-	TransactionID string `json:"transaction_id"`
-	FromAccount   int64  `json:"from_account"`
-	ToAccount     int64  `json:"to_account"`
-	Amount        string `json:"amount"`
-	Status        string `json:"status"`
-	Error         string `json:"error,omitempty"`
+	TransactionID string     `json:"transaction_id"`
+	FromAccount   int64      `json:"from_account"`
+	ToAccount     int64      `json:"to_account"`
+	Amount        string     `json:"amount"`
+	Currency      string     `json:"currency"`
+	Status        string     `json:"status"`
+	Error         string     `json:"error,omitempty"`
+	ExecuteAt     *time.Time `json:"execute_at,omitempty"`
+	// CompletedTransactionID is the transaction the transfer produced, once completed
+	CompletedTransactionID string            `json:"completed_transaction_id,omitempty"`
+	Description            string            `json:"description,omitempty"`
+	ExternalRef            string            `json:"external_ref,omitempty"`
+	Metadata               map[string]string `json:"metadata,omitempty"`
+	Approval               *ApprovalResponse `json:"approval,omitempty"`
+}
+
+// ApprovalResponse is the maker-checker trail of a transfer held for approval
+type ApprovalResponse struct {
+	RequestedBy string     `json:"requested_by,omitempty"`
+	RequestedAt time.Time  `json:"requested_at"`
+	Decision    string     `json:"decision,omitempty"`
+	DecidedBy   string     `json:"decided_by,omitempty"`
+	DecidedAt   *time.Time `json:"decided_at,omitempty"`
+	Reason      string     `json:"reason,omitempty"`
+}
+
+type HoldResponse struct {
+	HoldID               string    `json:"hold_id"`
+	SourceAccountID      int64     `json:"source_account_id"`
+	DestinationAccountID int64     `json:"destination_account_id"`
+	Amount               string    `json:"amount"`
+	CapturedAmount       string    `json:"captured_amount,omitempty"`
+	Currency             string    `json:"currency"`
+	Status               string    `json:"status"`
+	TransactionID        string    `json:"transaction_id,omitempty"`
+	ExpiresAt            time.Time `json:"expires_at"`
+}
+
+type EscrowResponse struct {
+	EscrowID             string    `json:"escrow_id"`
+	BuyerAccountID       int64     `json:"buyer_account_id"`
+	SellerAccountID      int64     `json:"seller_account_id"`
+	EscrowAccountID      int64     `json:"escrow_account_id"`
+	BuyerParty           string    `json:"buyer_party"`
+	SellerParty          string    `json:"seller_party"`
+	Arbiter              string    `json:"arbiter"`
+	Amount               string    `json:"amount"`
+	Released             string    `json:"released"`
+	Refunded             string    `json:"refunded"`
+	Remaining            string    `json:"remaining"`
+	Currency             string    `json:"currency"`
+	Status               string    `json:"status"`
+	Description          string    `json:"description,omitempty"`
+	DisputeReason        string    `json:"dispute_reason,omitempty"`
+	FundingTransactionID string    `json:"funding_transaction_id"`
+	ExpiresAt            time.Time `json:"expires_at"`
+	CreatedAt            time.Time `json:"created_at"`
+}
+
+type StandingOrderResponse struct {
+	StandingOrderID      string     `json:"standing_order_id"`
+	SourceAccountID      int64      `json:"source_account_id"`
+	DestinationAccountID int64      `json:"destination_account_id"`
+	Amount               string     `json:"amount"`
+	Currency             string     `json:"currency"`
+	Frequency            string     `json:"frequency"`
+	Cron                 string     `json:"cron,omitempty"`
+	StartAt              time.Time  `json:"start_at"`
+	EndAt                *time.Time `json:"end_at,omitempty"`
+	MaxRuns              int        `json:"max_runs,omitempty"`
+	RunCount             int        `json:"run_count"`
+	NextRunAt            *time.Time `json:"next_run_at,omitempty"`
+	OnInsufficientFunds  string     `json:"on_insufficient_funds"`
+	Status               string     `json:"status"`
+	CreatedAt            time.Time  `json:"created_at"`
+}
+
+type StandingOrderRunResponse struct {
+	RunID         string    `json:"run_id"`
+	ScheduledFor  time.Time `json:"scheduled_for"`
+	Status        string    `json:"status"`
+	TransactionID string    `json:"transaction_id,omitempty"`
+	Error         string    `json:"error,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type LimitResponse struct {
+	AccountID         int64     `json:"account_id,omitempty"`
+	AccountType       string    `json:"account_type,omitempty"`
+	Currency          string    `json:"currency"`
+	MaxPerTransaction string    `json:"max_per_transaction,omitempty"`
+	DailyOutgoing     string    `json:"daily_outgoing,omitempty"`
+	MonthlyOutgoing   string    `json:"monthly_outgoing,omitempty"`
+	HourlyTransfers   int64     `json:"hourly_transfers,omitempty"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// AccountLimitsResponse is the limit that applies to an account and what it has used of it
+type AccountLimitsResponse struct {
+	Limit             *LimitResponse `json:"limit"`
+	DailyUsed         string         `json:"daily_used,omitempty"`
+	MonthlyUsed       string         `json:"monthly_used,omitempty"`
+	TransfersLastHour int64          `json:"transfers_last_hour"`
+}
+
+// LimitErrorResponse names the limit a transfer would exceed and the headroom left under it
+type LimitErrorResponse struct {
+	Error     string `json:"error"`
+	Limit     string `json:"limit"`
+	Remaining string `json:"remaining"`
 }
 
 type ErrorResponse struct {
@@ -33,3 +249,48 @@ type ErrorResponse struct {
 type HealthResponse struct {
 	Status string `json:"status"`
 }
+
+// StatementHeaderResponse, the streamed entries and StatementTotalsResponse together
+// make up one statement object
+type StatementHeaderResponse struct {
+	AccountID      int64     `json:"account_id"`
+	Currency       string    `json:"currency"`
+	From           time.Time `json:"from"`
+	To             time.Time `json:"to"`
+	OpeningBalance string    `json:"opening_balance"`
+}
+
+type StatementEntryResponse struct {
+	Date          time.Time `json:"date"`
+	TransactionID string    `json:"transaction_id"`
+	Kind          string    `json:"kind"`
+	Counterparty  int64     `json:"counterparty_account_id"`
+	Debit         string    `json:"debit"`
+	Credit        string    `json:"credit"`
+	Balance       string    `json:"balance"`
+}
+
+type StatementTotalsResponse struct {
+	ClosingBalance string `json:"closing_balance"`
+	TotalDebits    string `json:"total_debits"`
+	TotalCredits   string `json:"total_credits"`
+}
+
+type ReconciliationRunResponse struct {
+	RunID           string                `json:"run_id"`
+	Status          string                `json:"status"`
+	AccountsChecked int64                 `json:"accounts_checked"`
+	Discrepancies   []DiscrepancyResponse `json:"discrepancies"`
+	StartedAt       time.Time             `json:"started_at"`
+	FinishedAt      time.Time             `json:"finished_at"`
+}
+
+// DiscrepancyResponse is a failed check. Difference is actual minus expected.
+type DiscrepancyResponse struct {
+	Check      string `json:"check"`
+	AccountID  int64  `json:"account_id,omitempty"`
+	Currency   string `json:"currency"`
+	Expected   string `json:"expected"`
+	Actual     string `json:"actual"`
+	Difference string `json:"difference"`
+}
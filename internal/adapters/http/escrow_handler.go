@@ -0,0 +1,187 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/maneeshsagar/tps/internal/adapters/http/dto"
+	"github.com/maneeshsagar/tps/internal/application"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+)
+
+// PartyIDHeader identifies the party to an escrow behind a dispute, release or refund
+const PartyIDHeader = "X-Party-ID"
+
+func (h *Handler) CreateEscrow(c *gin.Context) {
+	var req dto.CreateEscrowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid request"})
+		return
+	}
+	if req.ExpiresInSeconds < 0 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid expires_in_seconds"})
+		return
+	}
+
+	cur, ok := h.sourceCurrency(c, req.BuyerAccountID)
+	if !ok {
+		return
+	}
+	amount, err := cur.Parse(req.Amount)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid amount format"})
+		return
+	}
+	if amount <= 0 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "amount must be positive"})
+		return
+	}
+
+	escrow, err := h.svc.CreateEscrow(c, application.NewEscrow{
+		BuyerAccountID:  req.BuyerAccountID,
+		SellerAccountID: req.SellerAccountID,
+		EscrowAccountID: req.EscrowAccountID,
+		Buyer:           strings.TrimSpace(req.BuyerParty),
+		Seller:          strings.TrimSpace(req.SellerParty),
+		Arbiter:         strings.TrimSpace(req.Arbiter),
+		Amount:          amount,
+		Description:     req.Description,
+		TTL:             time.Duration(req.ExpiresInSeconds) * time.Second,
+	})
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toEscrowResponse(escrow))
+}
+
+func (h *Handler) GetEscrow(c *gin.Context) {
+	id, ok := parseEscrowID(c)
+	if !ok {
+		return
+	}
+
+	escrow, err := h.svc.GetEscrow(c, id)
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toEscrowResponse(escrow))
+}
+
+func (h *Handler) DisputeEscrow(c *gin.Context) {
+	id, ok := parseEscrowID(c)
+	if !ok {
+		return
+	}
+	var req dto.DisputeEscrowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid request"})
+		return
+	}
+
+	escrow, err := h.svc.DisputeEscrow(c, id, escrowParty(c), req.Reason)
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toEscrowResponse(escrow))
+}
+
+// ReleaseEscrow pays the seller, in full or in part
+func (h *Handler) ReleaseEscrow(c *gin.Context) {
+	h.settleEscrow(c, h.svc.ReleaseEscrow)
+}
+
+// RefundEscrow pays the buyer back, in full or in part
+func (h *Handler) RefundEscrow(c *gin.Context) {
+	h.settleEscrow(c, h.svc.RefundEscrow)
+}
+
+func (h *Handler) settleEscrow(c *gin.Context, settle func(context.Context, uuid.UUID, string, int64) (*domain.Escrow, error)) {
+	id, ok := parseEscrowID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.SettleEscrowRequest
+	if err := c.ShouldBindJSON(&req); err != nil && c.Request.ContentLength > 0 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid request"})
+		return
+	}
+
+	// a partial settlement is quoted in the escrow's currency
+	var amount int64
+	if req.Amount != "" {
+		escrow, err := h.svc.GetEscrow(c, id)
+		if err != nil {
+			h.handleErr(c, err)
+			return
+		}
+		cur, ok := lookupCurrency(c, escrow.Currency)
+		if !ok {
+			return
+		}
+		amount, err = cur.Parse(req.Amount)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid amount format"})
+			return
+		}
+		if amount <= 0 {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "amount must be positive"})
+			return
+		}
+	}
+
+	escrow, err := settle(c, id, escrowParty(c), amount)
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toEscrowResponse(escrow))
+}
+
+// escrowParty returns the caller's party id, empty when the header is missing
+func escrowParty(c *gin.Context) string {
+	return strings.TrimSpace(c.GetHeader(PartyIDHeader))
+}
+
+func parseEscrowID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid escrow id"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func toEscrowResponse(escrow *domain.Escrow) dto.EscrowResponse {
+	return dto.EscrowResponse{
+		EscrowID:             escrow.ID.String(),
+		BuyerAccountID:       escrow.BuyerAccountID,
+		SellerAccountID:      escrow.SellerAccountID,
+		EscrowAccountID:      escrow.EscrowAccountID,
+		BuyerParty:           escrow.Buyer,
+		SellerParty:          escrow.Seller,
+		Arbiter:              escrow.Arbiter,
+		Amount:               formatAmount(escrow.Amount, escrow.Currency),
+		Released:             formatAmount(escrow.Released, escrow.Currency),
+		Refunded:             formatAmount(escrow.Refunded, escrow.Currency),
+		Remaining:            formatAmount(escrow.Remaining(), escrow.Currency),
+		Currency:             escrow.Currency,
+		Status:               string(escrow.Status),
+		Description:          escrow.Description,
+		DisputeReason:        escrow.DisputeReason,
+		FundingTransactionID: escrow.FundingTransactionID.String(),
+		ExpiresAt:            escrow.ExpiresAt,
+		CreatedAt:            escrow.CreatedAt,
+	}
+}
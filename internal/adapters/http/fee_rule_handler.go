@@ -0,0 +1,160 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/maneeshsagar/tps/internal/adapters/http/dto"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"github.com/maneeshsagar/tps/pkg/currency"
+)
+
+func (h *Handler) CreateFeeRule(c *gin.Context) {
+	var req dto.CreateFeeRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid request"})
+		return
+	}
+
+	cur, err := currency.Lookup(req.Currency)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "unsupported currency"})
+		return
+	}
+
+	rule := &domain.FeeRule{
+		Name:             req.Name,
+		Type:             domain.FeeType(req.Type),
+		Currency:         cur.Code,
+		AccountType:      domain.AccountType(req.AccountType),
+		RevenueAccountID: req.RevenueAccountID,
+		Priority:         req.Priority,
+	}
+
+	// every amount and percentage is optional, which ones are needed depends on the type
+	fields := []struct {
+		in      string
+		out     *int64
+		percent bool
+	}{
+		{req.Flat, &rule.Flat, false},
+		{req.Percent, &rule.RateBps, true},
+		{req.Min, &rule.MinFee, false},
+		{req.Max, &rule.MaxFee, false},
+	}
+	for _, f := range fields {
+		if !parseFeeField(c, cur, f.in, f.out, f.percent) {
+			return
+		}
+	}
+	for _, t := range req.Tiers {
+		var tier domain.FeeTier
+		if !parseFeeField(c, cur, t.UpTo, &tier.UpTo, false) ||
+			!parseFeeField(c, cur, t.Flat, &tier.Flat, false) ||
+			!parseFeeField(c, cur, t.Percent, &tier.RateBps, true) {
+			return
+		}
+		rule.Tiers = append(rule.Tiers, tier)
+	}
+
+	rule, err = h.svc.CreateFeeRule(c, rule)
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toFeeRuleResponse(rule))
+}
+
+func (h *Handler) ListFeeRules(c *gin.Context) {
+	rules, err := h.svc.ListFeeRules(c)
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	resp := make([]dto.FeeRuleResponse, 0, len(rules))
+	for _, rule := range rules {
+		resp = append(resp, toFeeRuleResponse(rule))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) DeactivateFeeRule(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid fee rule id"})
+		return
+	}
+
+	rule, err := h.svc.DeactivateFeeRule(c, id)
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toFeeRuleResponse(rule))
+}
+
+// parseFeeField parses an optional amount in cur, or a percentage into basis points,
+// writing an error response on failure
+func parseFeeField(c *gin.Context, cur currency.Currency, in string, out *int64, percent bool) bool {
+	if in == "" {
+		return true
+	}
+
+	var v int64
+	var err error
+	if percent {
+		v, err = currency.ParsePercent(in)
+	} else {
+		v, err = cur.Parse(in)
+	}
+	if err != nil || v < 0 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid fee amount"})
+		return false
+	}
+	*out = v
+	return true
+}
+
+func toFeeRuleResponse(rule *domain.FeeRule) dto.FeeRuleResponse {
+	resp := dto.FeeRuleResponse{
+		FeeRuleID:        rule.ID.String(),
+		Name:             rule.Name,
+		Type:             string(rule.Type),
+		Currency:         rule.Currency,
+		AccountType:      string(rule.AccountType),
+		RevenueAccountID: rule.RevenueAccountID,
+		Priority:         rule.Priority,
+		Active:           rule.Active,
+		CreatedAt:        rule.CreatedAt,
+	}
+
+	amount := func(v int64) string {
+		if v == 0 {
+			return ""
+		}
+		return formatAmount(v, rule.Currency)
+	}
+	percent := func(v int64) string {
+		if v == 0 {
+			return ""
+		}
+		return currency.FormatPercent(v)
+	}
+
+	resp.Flat = amount(rule.Flat)
+	resp.Percent = percent(rule.RateBps)
+	resp.Min = amount(rule.MinFee)
+	resp.Max = amount(rule.MaxFee)
+	for _, t := range rule.Tiers {
+		resp.Tiers = append(resp.Tiers, dto.FeeTierResponse{
+			UpTo:    amount(t.UpTo),
+			Flat:    amount(t.Flat),
+			Percent: percent(t.RateBps),
+		})
+	}
+	return resp
+}
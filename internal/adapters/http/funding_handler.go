@@ -0,0 +1,58 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maneeshsagar/tps/internal/adapters/http/dto"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+)
+
+// CreateDeposit credits an account from the treasury of its currency
+func (h *Handler) CreateDeposit(c *gin.Context) {
+	h.fund(c, h.svc.Deposit)
+}
+
+// CreateWithdrawal debits an account to the treasury of its currency
+func (h *Handler) CreateWithdrawal(c *gin.Context) {
+	h.fund(c, h.svc.Withdraw)
+}
+
+func (h *Handler) fund(c *gin.Context, move func(context.Context, int64, int64, domain.TransferDetails) (*domain.Transaction, error)) {
+	id, ok := parseAccountID(c)
+	if !ok {
+		return
+	}
+	var req dto.FundingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid request"})
+		return
+	}
+
+	cur, ok := h.sourceCurrency(c, id)
+	if !ok {
+		return
+	}
+	amount, err := cur.Parse(req.Amount)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid amount format"})
+		return
+	}
+	if amount <= 0 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "amount must be positive"})
+		return
+	}
+
+	rec, err := move(c, id, amount, domain.TransferDetails{
+		Description: req.Description,
+		ExternalRef: req.ExternalRef,
+		Metadata:    req.Metadata,
+	})
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toTransactionResponse(rec))
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -32,25 +33,35 @@ func (h *Handler) CreateAccount(c *gin.Context) {
 		return
 	}
 
-	balance, err := currency.RupeesToPaise(req.InitialBalance)
+	if req.Currency == "" {
+		req.Currency = currency.DefaultCode
+	}
+	cur, err := currency.Lookup(req.Currency)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid balance format"})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "unsupported currency"})
 		return
 	}
-	if balance < 0 {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "balance cannot be negative"})
+
+	// accounts open at zero, so a balance given by an older client is refused rather than dropped
+	if balance, err := cur.Parse(req.InitialBalance); req.InitialBalance != "" && (err != nil || balance != 0) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "initial_balance is not supported, fund the account with a deposit"})
 		return
 	}
 
-	if err := h.svc.CreateAccount(c, req.AccountID, balance); err != nil {
+	acc, err := h.svc.CreateAccount(c, application.NewAccount{
+		ID:          req.AccountID,
+		Currency:    cur.Code,
+		Type:        domain.AccountType(req.Type),
+		OwnerName:   req.OwnerName,
+		CustomerRef: req.CustomerRef,
+		Labels:      req.Labels,
+	})
+	if err != nil {
 		h.handleErr(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, dto.AccountResponse{
-		AccountID: req.AccountID,
-		Balance:   currency.PaiseToRupees(balance),
-	})
+	c.JSON(http.StatusCreated, toAccountResponse(acc))
 }
 
 func (h *Handler) GetAccount(c *gin.Context) {
@@ -60,18 +71,78 @@ func (h *Handler) GetAccount(c *gin.Context) {
 		return
 	}
 
+	if raw := c.Query("as_of"); raw != "" {
+		h.getBalanceAsOf(c, id, raw)
+		return
+	}
+
 	acc, err := h.svc.GetAccount(c, id)
 	if err != nil {
 		h.handleErr(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.AccountResponse{
-		AccountID: acc.AccountID,
-		Balance:   currency.PaiseToRupees(acc.Balance),
+	c.JSON(http.StatusOK, toAccountResponse(acc))
+}
+
+// getBalanceAsOf answers GET /accounts/:account_id?as_of=<RFC 3339 time>
+func (h *Handler) getBalanceAsOf(c *gin.Context, id int64, raw string) {
+	asOf, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid as_of"})
+		return
+	}
+
+	snap, err := h.svc.GetBalanceAsOf(c, id, asOf)
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.BalanceAsOfResponse{
+		AccountID: snap.AccountID,
+		Currency:  snap.Currency,
+		Balance:   formatAmount(snap.Balance, snap.Currency),
+		AsOf:      snap.At,
 	})
 }
 
+func toAccountResponse(acc *domain.Account) dto.AccountResponse {
+	resp := dto.AccountResponse{
+		AccountID:        acc.AccountID,
+		Currency:         acc.Currency,
+		Balance:          formatAmount(acc.Balance, acc.Currency),
+		HeldBalance:      formatAmount(acc.HeldBalance, acc.Currency),
+		AvailableBalance: formatAmount(acc.AvailableBalance(), acc.Currency),
+		Type:             string(acc.Type),
+		OwnerName:        acc.OwnerName,
+		CustomerRef:      acc.CustomerRef,
+		Labels:           acc.Labels,
+		Status:           string(acc.Status),
+		FreezeScope:      string(acc.FreezeScope),
+		StatusReason:     acc.StatusReason,
+		CreatedAt:        acc.CreatedAt,
+	}
+	if !acc.Type.AllowsNegative() {
+		resp.AvailableToSpend = formatAmount(acc.AvailableToSpend(), acc.Currency)
+	}
+	// bounds that are off stay empty
+	bounds := []struct {
+		in  int64
+		out *string
+	}{
+		{acc.OverdraftLimit, &resp.OverdraftLimit},
+		{acc.MinimumBalance, &resp.MinimumBalance},
+		{acc.MaximumBalance, &resp.MaximumBalance},
+	}
+	for _, b := range bounds {
+		if b.in > 0 {
+			*b.out = formatAmount(b.in, acc.Currency)
+		}
+	}
+	return resp
+}
+
 func (h *Handler) CreateTransaction(c *gin.Context) {
 	var req dto.CreateTransactionRequest
 
@@ -86,8 +157,14 @@ func (h *Handler) CreateTransaction(c *gin.Context) {
 		return
 	}
 
-	// convert amount to paise, this is best practice to avoid floating point arithmetic issues
-	amount, err := currency.RupeesToPaise(req.Amount)
+	// amounts are quoted in the source account's currency
+	cur, ok := h.sourceCurrency(c, req.SourceAccountID)
+	if !ok {
+		return
+	}
+
+	// convert amount to minor units, this is best practice to avoid floating point arithmetic issues
+	amount, err := cur.Parse(req.Amount)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid amount format"})
 		return
@@ -97,22 +174,94 @@ func (h *Handler) CreateTransaction(c *gin.Context) {
 		return
 	}
 
-	result, err := h.svc.Transfer(c, req.SourceAccountID, req.DestinationAccountID, amount)
+	result, err := h.svc.Transfer(c, req.SourceAccountID, req.DestinationAccountID, amount, transferDetails(req))
+	// large transfers wait for an operator to approve them instead of running now
+	if errors.Is(err, domain.ErrApprovalRequired) {
+		h.requestApproval(c, req, amount, cur, time.Time{})
+		return
+	}
 	if err != nil {
 		h.handleErr(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.TransactionResponse{
+	c.JSON(http.StatusOK, transferResponse(req.SourceAccountID, req.DestinationAccountID, amount, cur, result))
+}
+
+func transferResponse(from, to, amount int64, cur currency.Currency, result *application.TransferResult) dto.TransactionResponse {
+	resp := dto.TransactionResponse{
 		TransactionID:        result.TransactionID.String(),
-		SourceAccountID:      req.SourceAccountID,
-		DestinationAccountID: req.DestinationAccountID,
-		Amount:               currency.PaiseToRupees(amount),
-	})
+		SourceAccountID:      from,
+		DestinationAccountID: to,
+		Amount:               cur.Format(amount),
+		Currency:             cur.Code,
+		Description:          result.Details.Description,
+		ExternalRef:          result.Details.ExternalRef,
+		Metadata:             result.Details.Metadata,
+	}
+	if result.DestinationCurrency != cur.Code {
+		resp.ConvertedAmount = formatAmount(result.DestinationAmount, result.DestinationCurrency)
+		resp.DestinationCurrency = result.DestinationCurrency
+		resp.FXRate = currency.FormatRate(result.FXRate)
+		resp.FXRateAt = &result.FXRateAt
+	}
+	if result.Fee > 0 {
+		resp.Fee = cur.Format(result.Fee)
+	}
+	return resp
+}
+
+func transferDetails(req dto.CreateTransactionRequest) domain.TransferDetails {
+	return domain.TransferDetails{Description: req.Description, ExternalRef: req.ExternalRef, Metadata: req.Metadata}
+}
+
+func (h *Handler) CreateReversal(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid transaction id"})
+		return
+	}
+
+	var req dto.CreateReversalRequest
+	if err := c.ShouldBindJSON(&req); err != nil && c.Request.ContentLength > 0 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid request"})
+		return
+	}
+
+	// a partial reversal is quoted in the currency the original destination received
+	var amount int64
+	if req.Amount != "" {
+		orig, err := h.svc.GetTransaction(c, id)
+		if err != nil {
+			h.handleErr(c, err)
+			return
+		}
+		cur, ok := lookupCurrency(c, orig.DestinationCurrency)
+		if !ok {
+			return
+		}
+		amount, err = cur.Parse(req.Amount)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid amount format"})
+			return
+		}
+		if amount <= 0 {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "amount must be positive"})
+			return
+		}
+	}
+
+	rec, err := h.svc.ReverseTransaction(c, id, amount)
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toTransactionResponse(rec))
 }
 
 func (h *Handler) CreateAsyncTransaction(c *gin.Context) {
-	var req dto.CreateTransactionRequest
+	var req dto.CreateAsyncTransactionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid request"})
 		return
@@ -123,7 +272,12 @@ func (h *Handler) CreateAsyncTransaction(c *gin.Context) {
 		return
 	}
 
-	amount, err := currency.RupeesToPaise(req.Amount)
+	cur, ok := h.sourceCurrency(c, req.SourceAccountID)
+	if !ok {
+		return
+	}
+
+	amount, err := cur.Parse(req.Amount)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid amount format"})
 		return
@@ -133,7 +287,32 @@ func (h *Handler) CreateAsyncTransaction(c *gin.Context) {
 		return
 	}
 
-	id, err := h.svc.SubmitTransfer(c, req.SourceAccountID, req.DestinationAccountID, amount)
+	// future-dated transfers wait in the scheduler instead of going to the queue now
+	if req.ExecuteAt != nil {
+		id, err := h.svc.ScheduleTransfer(c, req.SourceAccountID, req.DestinationAccountID, amount, cur.Code, *req.ExecuteAt, transferDetails(req.CreateTransactionRequest))
+		// large transfers wait for approval first, and are scheduled once approved
+		if errors.Is(err, domain.ErrApprovalRequired) {
+			h.requestApproval(c, req.CreateTransactionRequest, amount, cur, *req.ExecuteAt)
+			return
+		}
+		if err != nil {
+			h.handleErr(c, err)
+			return
+		}
+
+		c.JSON(http.StatusAccepted, dto.AsyncTransactionResponse{
+			TransactionID: id.String(),
+			Status:        string(domain.TxStatusScheduled),
+			ExecuteAt:     req.ExecuteAt,
+		})
+		return
+	}
+
+	id, err := h.svc.SubmitTransfer(c, req.SourceAccountID, req.DestinationAccountID, amount, cur.Code, transferDetails(req.CreateTransactionRequest))
+	if errors.Is(err, domain.ErrApprovalRequired) {
+		h.requestApproval(c, req.CreateTransactionRequest, amount, cur, time.Time{})
+		return
+	}
 	if err != nil {
 		h.handleErr(c, err)
 		return
@@ -159,29 +338,298 @@ func (h *Handler) GetAsyncTransactionStatus(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.AsyncStatusResponse{
+	c.JSON(http.StatusOK, toAsyncStatusResponse(tx))
+}
+
+func (h *Handler) CancelAsyncTransaction(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid transaction id"})
+		return
+	}
+
+	tx, err := h.svc.CancelScheduledTransfer(c, id)
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toAsyncStatusResponse(tx))
+}
+
+func toAsyncStatusResponse(tx *domain.AsyncTransaction) dto.AsyncStatusResponse {
+	resp := dto.AsyncStatusResponse{
 		TransactionID: tx.ID.String(),
 		FromAccount:   tx.FromAccount,
 		ToAccount:     tx.ToAccount,
-		Amount:        currency.PaiseToRupees(tx.Amount),
+		Amount:        formatAmount(tx.Amount, tx.Currency),
+		Currency:      tx.Currency,
 		Status:        string(tx.Status),
 		Error:         tx.Error,
-	})
+		Description:   tx.Description,
+		ExternalRef:   tx.ExternalRef,
+		Metadata:      tx.Metadata,
+	}
+	if !tx.ExecuteAt.IsZero() {
+		resp.ExecuteAt = &tx.ExecuteAt
+	}
+	if tx.TransactionID != uuid.Nil {
+		resp.CompletedTransactionID = tx.TransactionID.String()
+	}
+	if !tx.RequestedAt.IsZero() {
+		resp.Approval = &dto.ApprovalResponse{
+			RequestedBy: tx.RequestedBy,
+			RequestedAt: tx.RequestedAt,
+			Decision:    string(tx.Decision),
+			DecidedBy:   tx.DecidedBy,
+			Reason:      tx.Reason,
+		}
+		if !tx.DecidedAt.IsZero() {
+			resp.Approval.DecidedAt = &tx.DecidedAt
+		}
+	}
+	return resp
+}
+
+func (h *Handler) SetFXRate(c *gin.Context) {
+	var req dto.SetFXRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid request"})
+		return
+	}
+
+	rate, err := currency.ParseRate(req.Rate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid rate format"})
+		return
+	}
+
+	fx, err := h.svc.SetFXRate(c, c.Param("base"), c.Param("quote"), rate)
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toFXRateResponse(fx))
+}
+
+func (h *Handler) ListFXRates(c *gin.Context) {
+	rates, err := h.svc.ListFXRates(c)
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	resp := make([]dto.FXRateResponse, 0, len(rates))
+	for _, fx := range rates {
+		resp = append(resp, toFXRateResponse(fx))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func toFXRateResponse(fx *domain.FXRate) dto.FXRateResponse {
+	return dto.FXRateResponse{
+		Base:      fx.Base,
+		Quote:     fx.Quote,
+		Rate:      currency.FormatRate(fx.Rate),
+		UpdatedAt: fx.UpdatedAt,
+	}
+}
+
+func toTransactionResponse(t *domain.Transaction) dto.TransactionResponse {
+	resp := dto.TransactionResponse{
+		TransactionID:        t.ID.String(),
+		Kind:                 string(t.Kind),
+		SourceAccountID:      t.SourceAccountID,
+		DestinationAccountID: t.DestinationAccountID,
+		Amount:               formatAmount(t.Amount, t.Currency),
+		Currency:             t.Currency,
+		Description:          t.Description,
+		ExternalRef:          t.ExternalRef,
+		Metadata:             t.Metadata,
+		CreatedAt:            t.CreatedAt,
+	}
+	if t.IsReversal() {
+		resp.ReversalOf = t.ReversalOf.String()
+	}
+	if t.ParentID != uuid.Nil {
+		resp.ParentID = t.ParentID.String()
+	}
+	if t.IsCrossCurrency() {
+		resp.ConvertedAmount = formatAmount(t.DestinationAmount, t.DestinationCurrency)
+		resp.DestinationCurrency = t.DestinationCurrency
+		resp.FXRate = currency.FormatRate(t.FXRate)
+		resp.FXRateAt = &t.FXRateAt
+	}
+	if t.Fee > 0 {
+		resp.Fee = formatAmount(t.Fee, t.Currency)
+	}
+	return resp
+}
+
+// sourceCurrency returns the currency of the account funds are taken from,
+// writing an error response and returning false if it cannot be resolved.
+func (h *Handler) sourceCurrency(c *gin.Context, accountID int64) (currency.Currency, bool) {
+	acc, err := h.svc.GetAccount(c, accountID)
+	if err != nil {
+		h.handleErr(c, err)
+		return currency.Currency{}, false
+	}
+	return lookupCurrency(c, acc.Currency)
+}
+
+// lookupCurrency resolves a stored currency code, writing an error response on failure
+func lookupCurrency(c *gin.Context, code string) (currency.Currency, bool) {
+	cur, err := currency.Lookup(code)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "unsupported currency"})
+		return currency.Currency{}, false
+	}
+	return cur, true
+}
+
+// formatAmount renders minor units as a decimal string in the given currency
+func formatAmount(amount int64, code string) string {
+	cur, err := currency.Lookup(code)
+	if err != nil {
+		return strconv.FormatInt(amount, 10)
+	}
+	return cur.Format(amount)
 }
 
 func (h *Handler) handleErr(c *gin.Context, err error) {
+	var limitErr *domain.LimitError
+	if errors.As(err, &limitErr) {
+		c.JSON(http.StatusUnprocessableEntity, toLimitErrorResponse(limitErr))
+		return
+	}
+
+	status, msg := errorStatus(err)
+	c.JSON(status, dto.ErrorResponse{Error: msg})
+}
+
+// errorStatus maps a service error to the http status and message returned for it
+func errorStatus(err error) (int, string) {
 	switch {
 	case errors.Is(err, domain.ErrAccountNotFound):
-		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "account not found"})
+		return http.StatusNotFound, "account not found"
 	case errors.Is(err, domain.ErrAccountAlreadyExists):
-		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: "account exists"})
+		return http.StatusConflict, "account exists"
+	case errors.Is(err, domain.ErrUnsupportedCurrency):
+		return http.StatusBadRequest, "unsupported currency"
+	case errors.Is(err, domain.ErrCurrencyMismatch):
+		return http.StatusUnprocessableEntity, "currency mismatch"
+	case errors.Is(err, domain.ErrTreasuryNotFound):
+		return http.StatusUnprocessableEntity, "no treasury account for this currency"
+	case errors.Is(err, domain.ErrTreasuryExists):
+		return http.StatusConflict, "a treasury account already exists for this currency"
+	case errors.Is(err, domain.ErrFXRateNotFound):
+		return http.StatusUnprocessableEntity, "fx rate not found"
+	case errors.Is(err, domain.ErrInvalidFXRate):
+		return http.StatusBadRequest, "invalid fx rate"
+	case errors.Is(err, domain.ErrEscrowNotFound):
+		return http.StatusNotFound, "escrow not found"
+	case errors.Is(err, domain.ErrEscrowNotOpen):
+		return http.StatusConflict, "escrow is not open"
+	case errors.Is(err, domain.ErrEscrowAmountExceeded):
+		return http.StatusUnprocessableEntity, "amount exceeds what remains in escrow"
+	case errors.Is(err, domain.ErrInvalidEscrowParty):
+		return http.StatusBadRequest, "buyer_party, seller_party and arbiter must be set and distinct"
+	case errors.Is(err, domain.ErrNotEscrowParty):
+		return http.StatusForbidden, "X-Party-ID is not allowed to do this on the escrow"
+	case errors.Is(err, domain.ErrHoldNotFound):
+		return http.StatusNotFound, "hold not found"
+	case errors.Is(err, domain.ErrHoldNotActive):
+		return http.StatusConflict, "hold not active"
+	case errors.Is(err, domain.ErrHoldExpired):
+		return http.StatusConflict, "hold expired"
+	case errors.Is(err, domain.ErrHoldAmountExceeded):
+		return http.StatusUnprocessableEntity, "capture exceeds held amount"
+	case errors.Is(err, domain.ErrNotReversible):
+		return http.StatusConflict, "transaction cannot be reversed"
+	case errors.Is(err, domain.ErrReversalExceeded):
+		return http.StatusUnprocessableEntity, "reversal exceeds original amount"
+	case errors.Is(err, domain.ErrInvalidExecuteAt):
+		return http.StatusBadRequest, "execute_at must be in the future"
+	case errors.Is(err, domain.ErrNotCancellable):
+		return http.StatusConflict, "transaction cannot be cancelled"
+	case errors.Is(err, domain.ErrStandingOrderNotFound):
+		return http.StatusNotFound, "standing order not found"
+	case errors.Is(err, domain.ErrInvalidSchedule):
+		return http.StatusBadRequest, "invalid schedule"
+	case errors.Is(err, domain.ErrInvalidStatusChange):
+		return http.StatusConflict, "invalid status change"
+	case errors.Is(err, domain.ErrInvalidBatch):
+		return http.StatusBadRequest, "batch must have between 1 and 100 legs"
+	case errors.Is(err, domain.ErrInvalidSplit):
+		return http.StatusBadRequest, "invalid split"
+	case errors.Is(err, domain.ErrAccountFrozen):
+		return http.StatusUnprocessableEntity, "account frozen"
+	case errors.Is(err, domain.ErrAccountClosed):
+		return http.StatusUnprocessableEntity, "account closed"
+	case errors.Is(err, domain.ErrAccountNotEmpty):
+		return http.StatusConflict, "account balance must be zero to close"
+	case errors.Is(err, domain.ErrInvalidAccountType):
+		return http.StatusBadRequest, "invalid account type"
+	case errors.Is(err, domain.ErrInvalidLabels):
+		return http.StatusBadRequest, "invalid labels"
+	case errors.Is(err, domain.ErrFeeRuleNotFound):
+		return http.StatusNotFound, "fee rule not found"
+	case errors.Is(err, domain.ErrInvalidFeeRule):
+		return http.StatusBadRequest, "invalid fee rule"
+	case errors.Is(err, domain.ErrLimitExceeded):
+		return http.StatusUnprocessableEntity, "limit exceeded"
+	case errors.Is(err, domain.ErrLimitNotFound):
+		return http.StatusNotFound, "limit not found"
+	case errors.Is(err, domain.ErrInvalidAsOf):
+		return http.StatusBadRequest, "as_of must not be in the future"
+	case errors.Is(err, domain.ErrInvalidStatementRange):
+		return http.StatusBadRequest, "from must be before to"
+	case errors.Is(err, domain.ErrInvalidIdempotencyKey):
+		return http.StatusBadRequest, "invalid idempotency key"
+	case errors.Is(err, domain.ErrIdempotencyMismatch):
+		return http.StatusConflict, "idempotency key already used with a different request"
+	case errors.Is(err, domain.ErrIdempotencyInProgress):
+		return http.StatusConflict, "a request with this idempotency key is in progress"
+	case errors.Is(err, domain.ErrInvalidFilter):
+		return http.StatusBadRequest, "invalid filter"
+	case errors.Is(err, domain.ErrInvalidDetails):
+		return http.StatusBadRequest, "description, external_ref or metadata too long"
+	case errors.Is(err, domain.ErrDuplicateRef):
+		return http.StatusConflict, "external_ref already used for a transfer from this account"
+	case errors.Is(err, domain.ErrInvalidLimit):
+		return http.StatusBadRequest, "invalid limit"
+	case errors.Is(err, domain.ErrInvalidAmount):
+		return http.StatusBadRequest, "invalid amount"
+	case errors.Is(err, domain.ErrSameAccount):
+		return http.StatusBadRequest, "same account"
 	case errors.Is(err, domain.ErrInsufficientBalance):
-		c.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{Error: "insufficient balance"})
+		return http.StatusUnprocessableEntity, "insufficient balance"
+	case errors.Is(err, domain.ErrOverdraftExceeded):
+		return http.StatusUnprocessableEntity, "overdraft limit exceeded"
+	case errors.Is(err, domain.ErrMinimumBalance):
+		return http.StatusUnprocessableEntity, "debit would breach the minimum balance"
+	case errors.Is(err, domain.ErrMaximumBalance):
+		return http.StatusUnprocessableEntity, "credit would exceed the maximum balance"
+	case errors.Is(err, domain.ErrInvalidBalancePolicy):
+		return http.StatusBadRequest, "invalid balance policy"
+	case errors.Is(err, domain.ErrNotPendingApproval):
+		return http.StatusConflict, "transfer is not pending approval"
+	case errors.Is(err, domain.ErrSameOperator):
+		return http.StatusForbidden, "a transfer must be decided by another operator than the one who requested it"
+	case errors.Is(err, domain.ErrInvalidOperator):
+		return http.StatusBadRequest, "X-Operator-ID header is required"
+	case errors.Is(err, domain.ErrApprovalRequired):
+		return http.StatusUnprocessableEntity, "amount is above the approval threshold; only single transfers can be submitted for approval"
+	case errors.Is(err, domain.ErrOperatorRequired):
+		return http.StatusBadRequest, "X-Operator-ID header is required for transfers above the approval threshold"
+	case errors.Is(err, domain.ErrUnknownRequester):
+		return http.StatusForbidden, "transfer has no requesting operator to check the approver against"
 	case errors.Is(err, domain.ErrLockAcquisitionFailed):
-		c.JSON(http.StatusServiceUnavailable, dto.ErrorResponse{Error: "busy, retry"})
+		return http.StatusServiceUnavailable, "busy, retry"
 	case errors.Is(err, domain.ErrTransactionNotFound):
-		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "transaction not found"})
+		return http.StatusNotFound, "transaction not found"
 	default:
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+		return http.StatusInternalServerError, "internal error"
 	}
 }
@@ -0,0 +1,151 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/maneeshsagar/tps/internal/adapters/http/dto"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+)
+
+func (h *Handler) CreateHold(c *gin.Context) {
+	var req dto.CreateHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid request"})
+		return
+	}
+
+	if req.SourceAccountID == req.DestinationAccountID {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "same account"})
+		return
+	}
+	if req.ExpiresInSeconds < 0 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid expires_in_seconds"})
+		return
+	}
+
+	cur, ok := h.sourceCurrency(c, req.SourceAccountID)
+	if !ok {
+		return
+	}
+	amount, err := cur.Parse(req.Amount)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid amount format"})
+		return
+	}
+	if amount <= 0 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "amount must be positive"})
+		return
+	}
+
+	ttl := time.Duration(req.ExpiresInSeconds) * time.Second
+	hold, err := h.svc.CreateHold(c, req.SourceAccountID, req.DestinationAccountID, amount, ttl)
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toHoldResponse(hold))
+}
+
+func (h *Handler) GetHold(c *gin.Context) {
+	id, ok := parseHoldID(c)
+	if !ok {
+		return
+	}
+
+	hold, err := h.svc.GetHold(c, id)
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toHoldResponse(hold))
+}
+
+func (h *Handler) CaptureHold(c *gin.Context) {
+	id, ok := parseHoldID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.CaptureHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil && c.Request.ContentLength > 0 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid request"})
+		return
+	}
+
+	// a partial capture is quoted in the hold's currency
+	var amount int64
+	if req.Amount != "" {
+		hold, err := h.svc.GetHold(c, id)
+		if err != nil {
+			h.handleErr(c, err)
+			return
+		}
+		cur, ok := lookupCurrency(c, hold.Currency)
+		if !ok {
+			return
+		}
+		amount, err = cur.Parse(req.Amount)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid amount format"})
+			return
+		}
+		if amount <= 0 {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "amount must be positive"})
+			return
+		}
+	}
+
+	hold, err := h.svc.CaptureHold(c, id, amount)
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toHoldResponse(hold))
+}
+
+func (h *Handler) VoidHold(c *gin.Context) {
+	id, ok := parseHoldID(c)
+	if !ok {
+		return
+	}
+
+	hold, err := h.svc.VoidHold(c, id)
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toHoldResponse(hold))
+}
+
+func parseHoldID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid hold id"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func toHoldResponse(hold *domain.Hold) dto.HoldResponse {
+	resp := dto.HoldResponse{
+		HoldID:               hold.ID.String(),
+		SourceAccountID:      hold.AccountID,
+		DestinationAccountID: hold.DestinationAccountID,
+		Amount:               formatAmount(hold.Amount, hold.Currency),
+		Currency:             hold.Currency,
+		Status:               string(hold.Status),
+		ExpiresAt:            hold.ExpiresAt,
+	}
+	if hold.Status == domain.HoldStatusCaptured {
+		resp.CapturedAmount = formatAmount(hold.CapturedAmount, hold.Currency)
+		resp.TransactionID = hold.TransactionID.String()
+	}
+	return resp
+}
@@ -0,0 +1,105 @@
+package http
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maneeshsagar/tps/internal/adapters/http/dto"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotentRequestBytes = 1 << 20
+)
+
+// Idempotent makes a POST endpoint safe to retry. A request with an Idempotency-Key
+// is processed once; a replay of the same request gets the stored response, and the
+// same key with a different request gets a 409. Requests without the header are
+// processed as usual. A server error or a panic releases the key so the client can try
+// again, and a key left behind by a crash is taken over once its lease runs out. Bodies
+// are fingerprinted whole, so one above maxIdempotentRequestBytes is refused.
+func (h *Handler) Idempotent(ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxIdempotentRequestBytes+1))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid request"})
+			return
+		}
+		if len(body) > maxIdempotentRequestBytes {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, dto.ErrorResponse{Error: "request body too large"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		rec, err := h.svc.BeginIdempotent(c, key, fingerprint(c, body), ttl)
+		if err != nil {
+			h.handleErr(c, err)
+			c.Abort()
+			return
+		}
+		if rec != nil {
+			c.Header(IdempotentReplayedHeader, "true")
+			c.Data(rec.ResponseStatus, "application/json; charset=utf-8", rec.ResponseBody)
+			c.Abort()
+			return
+		}
+
+		// runs on a panic too, which gin's recovery turns into a 500 further up
+		saved := false
+		defer func() {
+			if !saved {
+				if err := h.svc.ReleaseIdempotent(c, key); err != nil {
+					_ = c.Error(fmt.Errorf("releasing idempotency key: %w", err))
+				}
+			}
+		}()
+
+		w := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+
+		if status := w.Status(); status < http.StatusInternalServerError {
+			saved = true
+			if err := h.svc.CompleteIdempotent(c, key, status, w.body.Bytes()); err != nil {
+				_ = c.Error(fmt.Errorf("saving idempotency key: %w", err))
+			}
+		}
+	}
+}
+
+// fingerprint identifies a request by its method, path and body
+func fingerprint(c *gin.Context, body []byte) string {
+	sum := sha256.New()
+	sum.Write([]byte(c.Request.Method + " " + c.Request.URL.Path + "\n"))
+	sum.Write(body)
+	return hex.EncodeToString(sum.Sum(nil))
+}
+
+// recordingWriter keeps a copy of the response body as it is written
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,81 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maneeshsagar/tps/internal/application"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+)
+
+// idempotencySvc records what the middleware does with a key; any other service
+// method panics
+type idempotencySvc struct {
+	application.TransferServiceIntf
+	begun     int
+	released  int
+	completed int
+	status    int
+}
+
+func (s *idempotencySvc) BeginIdempotent(ctx context.Context, key, fingerprint string, ttl time.Duration) (*domain.IdempotencyKey, error) {
+	s.begun++
+	return nil, nil
+}
+
+func (s *idempotencySvc) CompleteIdempotent(ctx context.Context, key string, status int, body []byte) error {
+	s.completed++
+	s.status = status
+	return nil
+}
+
+func (s *idempotencySvc) ReleaseIdempotent(ctx context.Context, key string) error {
+	s.released++
+	return nil
+}
+
+func TestIdempotent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cases := []struct {
+		name          string
+		body          string
+		handler       gin.HandlerFunc
+		wantStatus    int
+		wantBegun     int
+		wantCompleted int
+		wantReleased  int
+	}{
+		{"completed", `{}`, func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{}) }, http.StatusOK, 1, 1, 0},
+		{"client error is stored", `{}`, func(c *gin.Context) { c.JSON(http.StatusUnprocessableEntity, gin.H{}) }, http.StatusUnprocessableEntity, 1, 1, 0},
+		{"server error releases", `{}`, func(c *gin.Context) { c.JSON(http.StatusServiceUnavailable, gin.H{}) }, http.StatusServiceUnavailable, 1, 0, 1},
+		{"panic releases", `{}`, func(c *gin.Context) { panic("boom") }, http.StatusInternalServerError, 1, 0, 1},
+		{"body too large", strings.Repeat("x", maxIdempotentRequestBytes+1), func(c *gin.Context) { c.Status(http.StatusOK) }, http.StatusRequestEntityTooLarge, 0, 0, 0},
+		{"body at the limit", strings.Repeat("x", maxIdempotentRequestBytes), func(c *gin.Context) { c.Status(http.StatusOK) }, http.StatusOK, 1, 1, 0},
+	}
+
+	for _, tc := range cases {
+		svc := &idempotencySvc{}
+		h := NewHandler(svc)
+		r := gin.New()
+		r.Use(gin.Recovery())
+		r.POST("/transactions", h.Idempotent(time.Hour), tc.handler)
+
+		req := httptest.NewRequest(http.MethodPost, "/transactions", strings.NewReader(tc.body))
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tc.wantStatus {
+			t.Errorf("%s: status = %d, want %d", tc.name, w.Code, tc.wantStatus)
+		}
+		if svc.begun != tc.wantBegun || svc.completed != tc.wantCompleted || svc.released != tc.wantReleased {
+			t.Errorf("%s: begun %d, completed %d, released %d; want %d, %d, %d", tc.name,
+				svc.begun, svc.completed, svc.released, tc.wantBegun, tc.wantCompleted, tc.wantReleased)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maneeshsagar/tps/internal/adapters/http/dto"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"github.com/maneeshsagar/tps/pkg/currency"
+)
+
+func (h *Handler) SetAccountLimit(c *gin.Context) {
+	id, ok := parseAccountID(c)
+	if !ok {
+		return
+	}
+
+	// limits are in the account's currency
+	cur, ok := h.sourceCurrency(c, id)
+	if !ok {
+		return
+	}
+	limit, ok := parseLimitRequest(c, cur)
+	if !ok {
+		return
+	}
+	limit.AccountID = id
+
+	limit, err := h.svc.SetAccountLimit(c, limit)
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toLimitResponse(limit))
+}
+
+func (h *Handler) DeleteAccountLimit(c *gin.Context) {
+	id, ok := parseAccountID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteAccountLimit(c, id); err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) SetTierLimit(c *gin.Context) {
+	cur, err := currency.Lookup(c.Param("currency"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "unsupported currency"})
+		return
+	}
+	limit, ok := parseLimitRequest(c, cur)
+	if !ok {
+		return
+	}
+	limit.AccountType = domain.AccountType(c.Param("account_type"))
+	limit.Currency = cur.Code
+
+	limit, err = h.svc.SetTierLimit(c, limit)
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toLimitResponse(limit))
+}
+
+func (h *Handler) DeleteTierLimit(c *gin.Context) {
+	cur, err := currency.Lookup(c.Param("currency"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "unsupported currency"})
+		return
+	}
+
+	if err := h.svc.DeleteTierLimit(c, domain.AccountType(c.Param("account_type")), cur.Code); err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) ListLimits(c *gin.Context) {
+	limits, err := h.svc.ListLimits(c)
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	resp := make([]dto.LimitResponse, 0, len(limits))
+	for _, limit := range limits {
+		resp = append(resp, toLimitResponse(limit))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) GetAccountLimits(c *gin.Context) {
+	id, ok := parseAccountID(c)
+	if !ok {
+		return
+	}
+
+	limit, usage, err := h.svc.GetAccountLimits(c, id)
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	// no limit applies to the account
+	if limit == nil {
+		c.JSON(http.StatusOK, dto.AccountLimitsResponse{})
+		return
+	}
+
+	l := toLimitResponse(limit)
+	c.JSON(http.StatusOK, dto.AccountLimitsResponse{
+		Limit:             &l,
+		DailyUsed:         formatAmount(usage.Daily, limit.Currency),
+		MonthlyUsed:       formatAmount(usage.Monthly, limit.Currency),
+		TransfersLastHour: usage.LastHour,
+	})
+}
+
+func parseAccountID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("account_id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid account_id"})
+		return 0, false
+	}
+	return id, true
+}
+
+// parseLimitRequest reads a limit with its amounts in cur, writing an error response on failure
+func parseLimitRequest(c *gin.Context, cur currency.Currency) (*domain.TransferLimit, bool) {
+	var req dto.SetLimitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid request"})
+		return nil, false
+	}
+	if req.HourlyTransfers < 0 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid hourly_transfers"})
+		return nil, false
+	}
+
+	limit := &domain.TransferLimit{HourlyCount: req.HourlyTransfers}
+	fields := []struct {
+		in  string
+		out *int64
+	}{
+		{req.MaxPerTransaction, &limit.MaxPerTransaction},
+		{req.DailyOutgoing, &limit.DailyOutgoing},
+		{req.MonthlyOutgoing, &limit.MonthlyOutgoing},
+	}
+	for _, f := range fields {
+		if f.in == "" {
+			continue
+		}
+		v, err := cur.Parse(f.in)
+		if err != nil || v < 0 {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid limit amount"})
+			return nil, false
+		}
+		*f.out = v
+	}
+	return limit, true
+}
+
+func toLimitResponse(limit *domain.TransferLimit) dto.LimitResponse {
+	amount := func(v int64) string {
+		if v == 0 {
+			return ""
+		}
+		return formatAmount(v, limit.Currency)
+	}
+	return dto.LimitResponse{
+		AccountID:         limit.AccountID,
+		AccountType:       string(limit.AccountType),
+		Currency:          limit.Currency,
+		MaxPerTransaction: amount(limit.MaxPerTransaction),
+		DailyOutgoing:     amount(limit.DailyOutgoing),
+		MonthlyOutgoing:   amount(limit.MonthlyOutgoing),
+		HourlyTransfers:   limit.HourlyCount,
+		UpdatedAt:         limit.UpdatedAt,
+	}
+}
+
+func toLimitErrorResponse(err *domain.LimitError) dto.LimitErrorResponse {
+	remaining := strconv.FormatInt(err.Remaining, 10)
+	if err.Limit != domain.LimitHourlyCount {
+		remaining = formatAmount(err.Remaining, err.Currency)
+	}
+	return dto.LimitErrorResponse{
+		Error:     "limit exceeded",
+		Limit:     err.Limit,
+		Remaining: remaining,
+	}
+}
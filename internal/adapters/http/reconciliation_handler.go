@@ -0,0 +1,52 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maneeshsagar/tps/internal/adapters/http/dto"
+)
+
+// ListReconciliationRuns returns the latest reconciliation runs, newest first
+func (h *Handler) ListReconciliationRuns(c *gin.Context) {
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid limit"})
+			return
+		}
+		limit = n
+	}
+
+	runs, err := h.svc.ListReconciliationRuns(c, limit)
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	resp := make([]dto.ReconciliationRunResponse, 0, len(runs))
+	for _, run := range runs {
+		r := dto.ReconciliationRunResponse{
+			RunID:           run.ID.String(),
+			Status:          string(run.Status),
+			AccountsChecked: run.AccountsChecked,
+			Discrepancies:   make([]dto.DiscrepancyResponse, 0, len(run.Discrepancies)),
+			StartedAt:       run.StartedAt,
+			FinishedAt:      run.FinishedAt,
+		}
+		for _, d := range run.Discrepancies {
+			r.Discrepancies = append(r.Discrepancies, dto.DiscrepancyResponse{
+				Check:      string(d.Check),
+				AccountID:  d.AccountID,
+				Currency:   d.Currency,
+				Expected:   formatAmount(d.Expected, d.Currency),
+				Actual:     formatAmount(d.Actual, d.Currency),
+				Difference: formatAmount(d.Difference(), d.Currency),
+			})
+		}
+		resp = append(resp, r)
+	}
+	c.JSON(http.StatusOK, resp)
+}
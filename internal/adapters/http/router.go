@@ -1,24 +1,86 @@
 package http
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/maneeshsagar/tps/internal/application"
 )
 
-func NewRouter(svc application.TransferServiceIntf) *gin.Engine {
+// NewRouter builds the API. idempotencyTTL is how long an Idempotency-Key is kept.
+func NewRouter(svc application.TransferServiceIntf, idempotencyTTL time.Duration) *gin.Engine {
 	r := gin.Default()
 	h := NewHandler(svc)
+	idempotent := h.Idempotent(idempotencyTTL)
 
 	r.GET("/health", h.HealthCheck)
 	r.POST("/accounts", h.CreateAccount)
 	r.GET("/accounts/:account_id", h.GetAccount)
+	r.GET("/accounts/:account_id/limits", h.GetAccountLimits)
+	r.GET("/accounts/:account_id/statement", h.GetStatement)
+	r.GET("/accounts/:account_id/transactions", h.ListAccountTransactions)
+
+	// money enters and leaves the system only through the treasury account of each currency
+	r.POST("/accounts/:account_id/deposits", idempotent, h.CreateDeposit)
+	r.POST("/accounts/:account_id/withdrawals", idempotent, h.CreateWithdrawal)
 
 	// this endpoint will perform a synchronous transfer and return the result immediately
-	r.POST("/transactions", h.CreateTransaction)
+	r.POST("/transactions", idempotent, h.CreateTransaction)
+	r.GET("/transactions", h.ListTransactions)
+	r.GET("/transactions/:id", h.GetTransaction)
+	r.POST("/transactions/:id/reversals", h.CreateReversal)
+
+	// all legs of a batch are applied in one db transaction, or none are
+	r.POST("/transactions/batch", h.CreateBatchTransaction)
+	// one debit fanned out to several destinations
+	r.POST("/transactions/split", h.CreateSplitTransaction)
 
 	// this is a new endpoint for creating async transactions and checking their status
-	r.POST("/async-transactions", h.CreateAsyncTransaction)
+	r.POST("/async-transactions", idempotent, h.CreateAsyncTransaction)
 	r.GET("/async-transactions/:id/status", h.GetAsyncTransactionStatus)
+	r.POST("/async-transactions/:id/cancel", h.CancelAsyncTransaction)
+
+	// two-phase transfers: reserve funds now, capture or void later
+	r.POST("/holds", h.CreateHold)
+	r.GET("/holds/:id", h.GetHold)
+	r.POST("/holds/:id/capture", h.CaptureHold)
+	r.POST("/holds/:id/void", h.VoidHold)
+
+	// marketplace escrows: released or refunded by their parties, refunded automatically at their deadline
+	r.POST("/escrows", idempotent, h.CreateEscrow)
+	r.GET("/escrows/:id", h.GetEscrow)
+	r.POST("/escrows/:id/dispute", h.DisputeEscrow)
+	r.POST("/escrows/:id/release", h.ReleaseEscrow)
+	r.POST("/escrows/:id/refund", h.RefundEscrow)
+
+	// recurring transfers
+	r.POST("/standing-orders", h.CreateStandingOrder)
+	r.GET("/standing-orders/:id", h.GetStandingOrder)
+	r.PATCH("/standing-orders/:id", h.UpdateStandingOrder)
+	r.DELETE("/standing-orders/:id", h.CancelStandingOrder)
+	r.GET("/standing-orders/:id/runs", h.ListStandingOrderRuns)
+	r.GET("/accounts/:account_id/standing-orders", h.ListAccountStandingOrders)
+
+	// admin endpoints for account status, balance policies, limits, fee rules, the fx rate table, reconciliation
+	// and maker-checker approval of transfers above the approval threshold
+	admin := r.Group("/admin")
+	admin.PUT("/accounts/:account_id/status", h.ChangeAccountStatus)
+	admin.GET("/accounts/:account_id/status-history", h.ListAccountStatusChanges)
+	admin.PUT("/accounts/:account_id/balance-policy", h.SetBalancePolicy)
+	admin.GET("/limits", h.ListLimits)
+	admin.PUT("/accounts/:account_id/limits", h.SetAccountLimit)
+	admin.DELETE("/accounts/:account_id/limits", h.DeleteAccountLimit)
+	admin.PUT("/limits/:account_type/:currency", h.SetTierLimit)
+	admin.DELETE("/limits/:account_type/:currency", h.DeleteTierLimit)
+	admin.POST("/fee-rules", h.CreateFeeRule)
+	admin.GET("/fee-rules", h.ListFeeRules)
+	admin.DELETE("/fee-rules/:id", h.DeactivateFeeRule)
+	admin.GET("/fx-rates", h.ListFXRates)
+	admin.PUT("/fx-rates/:base/:quote", h.SetFXRate)
+	admin.GET("/reconciliation", h.ListReconciliationRuns)
+	admin.GET("/approvals", h.ListPendingApprovals)
+	admin.POST("/approvals/:id/approve", h.ApproveTransfer)
+	admin.POST("/approvals/:id/reject", h.RejectTransfer)
 
 	return r
 }
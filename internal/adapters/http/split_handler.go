@@ -0,0 +1,84 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maneeshsagar/tps/internal/adapters/http/dto"
+	"github.com/maneeshsagar/tps/internal/application"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"github.com/maneeshsagar/tps/pkg/currency"
+)
+
+func (h *Handler) CreateSplitTransaction(c *gin.Context) {
+	var req dto.CreateSplitTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid request"})
+		return
+	}
+
+	// amounts are quoted in the source account's currency
+	cur, ok := h.sourceCurrency(c, req.SourceAccountID)
+	if !ok {
+		return
+	}
+
+	var amount int64
+	if req.Amount != "" {
+		var err error
+		amount, err = cur.Parse(req.Amount)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid amount format"})
+			return
+		}
+		if amount <= 0 {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "amount must be positive"})
+			return
+		}
+	}
+
+	legs := make([]application.SplitLeg, 0, len(req.Legs))
+	for _, l := range req.Legs {
+		leg := application.SplitLeg{ToAccount: l.DestinationAccountID}
+		switch {
+		case l.Amount != "" && l.Percent == "":
+			a, err := cur.Parse(l.Amount)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid amount format"})
+				return
+			}
+			if a <= 0 {
+				c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "amount must be positive"})
+				return
+			}
+			leg.Amount = a
+		case l.Percent != "" && l.Amount == "":
+			p, err := currency.ParsePercent(l.Percent)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid percent"})
+				return
+			}
+			leg.Share = p
+		default:
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "each leg needs either an amount or a percent"})
+			return
+		}
+		legs = append(legs, leg)
+	}
+
+	details := domain.TransferDetails{Description: req.Description, ExternalRef: req.ExternalRef, Metadata: req.Metadata}
+	result, err := h.svc.SplitTransfer(c, req.SourceAccountID, amount, legs, details)
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	resp := dto.SplitTransactionResponse{
+		Transaction: toTransactionResponse(result.Parent),
+		Legs:        make([]dto.TransactionResponse, 0, len(result.Legs)),
+	}
+	for _, leg := range result.Legs {
+		resp.Legs = append(resp.Legs, toTransactionResponse(leg))
+	}
+	c.JSON(http.StatusOK, resp)
+}
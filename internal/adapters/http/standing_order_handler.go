@@ -0,0 +1,233 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/maneeshsagar/tps/internal/adapters/http/dto"
+	"github.com/maneeshsagar/tps/internal/application"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+)
+
+func (h *Handler) CreateStandingOrder(c *gin.Context) {
+	var req dto.CreateStandingOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid request"})
+		return
+	}
+
+	if req.SourceAccountID == req.DestinationAccountID {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "same account"})
+		return
+	}
+
+	cur, ok := h.sourceCurrency(c, req.SourceAccountID)
+	if !ok {
+		return
+	}
+	amount, err := cur.Parse(req.Amount)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid amount format"})
+		return
+	}
+	if amount <= 0 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "amount must be positive"})
+		return
+	}
+
+	order := &domain.StandingOrder{
+		FromAccount:         req.SourceAccountID,
+		ToAccount:           req.DestinationAccountID,
+		Amount:              amount,
+		Frequency:           domain.Frequency(req.Frequency),
+		CronExpr:            req.Cron,
+		MaxRuns:             req.MaxRuns,
+		OnInsufficientFunds: domain.InsufficientFundsPolicy(req.OnInsufficientFunds),
+	}
+	if req.StartAt != nil {
+		order.StartAt = *req.StartAt
+	}
+	if req.EndAt != nil {
+		order.EndAt = *req.EndAt
+	}
+
+	order, err = h.svc.CreateStandingOrder(c, order)
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toStandingOrderResponse(order))
+}
+
+func (h *Handler) GetStandingOrder(c *gin.Context) {
+	id, ok := parseStandingOrderID(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.svc.GetStandingOrder(c, id)
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toStandingOrderResponse(order))
+}
+
+func (h *Handler) ListAccountStandingOrders(c *gin.Context) {
+	accountID, err := strconv.ParseInt(c.Param("account_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid account_id"})
+		return
+	}
+
+	orders, err := h.svc.ListStandingOrders(c, accountID)
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	resp := make([]dto.StandingOrderResponse, 0, len(orders))
+	for _, order := range orders {
+		resp = append(resp, toStandingOrderResponse(order))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) UpdateStandingOrder(c *gin.Context) {
+	id, ok := parseStandingOrderID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateStandingOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid request"})
+		return
+	}
+
+	upd := application.StandingOrderUpdate{
+		EndAt:   req.EndAt,
+		MaxRuns: req.MaxRuns,
+	}
+	if req.OnInsufficientFunds != nil {
+		policy := domain.InsufficientFundsPolicy(*req.OnInsufficientFunds)
+		upd.OnInsufficientFunds = &policy
+	}
+	if req.Status != nil {
+		status := domain.StandingOrderStatus(*req.Status)
+		upd.Status = &status
+	}
+
+	// a new amount is quoted in the order's currency
+	if req.Amount != nil {
+		order, err := h.svc.GetStandingOrder(c, id)
+		if err != nil {
+			h.handleErr(c, err)
+			return
+		}
+		cur, ok := lookupCurrency(c, order.Currency)
+		if !ok {
+			return
+		}
+		amount, err := cur.Parse(*req.Amount)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid amount format"})
+			return
+		}
+		if amount <= 0 {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "amount must be positive"})
+			return
+		}
+		upd.Amount = &amount
+	}
+
+	order, err := h.svc.UpdateStandingOrder(c, id, upd)
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toStandingOrderResponse(order))
+}
+
+func (h *Handler) CancelStandingOrder(c *gin.Context) {
+	id, ok := parseStandingOrderID(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.svc.CancelStandingOrder(c, id)
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toStandingOrderResponse(order))
+}
+
+func (h *Handler) ListStandingOrderRuns(c *gin.Context) {
+	id, ok := parseStandingOrderID(c)
+	if !ok {
+		return
+	}
+
+	runs, err := h.svc.ListStandingOrderRuns(c, id)
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	resp := make([]dto.StandingOrderRunResponse, 0, len(runs))
+	for _, run := range runs {
+		r := dto.StandingOrderRunResponse{
+			RunID:        run.ID.String(),
+			ScheduledFor: run.ScheduledFor,
+			Status:       string(run.Status),
+			Error:        run.Error,
+			CreatedAt:    run.CreatedAt,
+		}
+		if run.TransactionID != uuid.Nil {
+			r.TransactionID = run.TransactionID.String()
+		}
+		resp = append(resp, r)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func parseStandingOrderID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid standing order id"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func toStandingOrderResponse(order *domain.StandingOrder) dto.StandingOrderResponse {
+	resp := dto.StandingOrderResponse{
+		StandingOrderID:      order.ID.String(),
+		SourceAccountID:      order.FromAccount,
+		DestinationAccountID: order.ToAccount,
+		Amount:               formatAmount(order.Amount, order.Currency),
+		Currency:             order.Currency,
+		Frequency:            string(order.Frequency),
+		Cron:                 order.CronExpr,
+		StartAt:              order.StartAt,
+		MaxRuns:              order.MaxRuns,
+		RunCount:             order.RunCount,
+		OnInsufficientFunds:  string(order.OnInsufficientFunds),
+		Status:               string(order.Status),
+		CreatedAt:            order.CreatedAt,
+	}
+	if !order.EndAt.IsZero() {
+		resp.EndAt = &order.EndAt
+	}
+	if order.Status == domain.StandingOrderActive {
+		resp.NextRunAt = &order.NextRunAt
+	}
+	return resp
+}
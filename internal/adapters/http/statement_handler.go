@@ -0,0 +1,219 @@
+package http
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maneeshsagar/tps/internal/adapters/http/dto"
+	"github.com/maneeshsagar/tps/internal/application"
+)
+
+// statementFlushLines is how many lines are buffered before they are sent to the client
+const statementFlushLines = 100
+
+// GetStatement streams the statement of an account as CSV or JSON. from is required,
+// to defaults to now; both take an RFC 3339 time or a YYYY-MM-DD date, and a date in
+// to includes that whole day.
+func (h *Handler) GetStatement(c *gin.Context) {
+	id, ok := parseAccountID(c)
+	if !ok {
+		return
+	}
+	from, err := parseStatementTime(c.Query("from"), false)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid from"})
+		return
+	}
+	to := time.Now()
+	if raw := c.Query("to"); raw != "" {
+		if to, err = parseStatementTime(raw, true); err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid to"})
+			return
+		}
+	}
+
+	var w statementStream
+	switch c.DefaultQuery("format", "json") {
+	case "json":
+		w = &jsonStatementWriter{c: c}
+	case "csv":
+		w = &csvStatementWriter{c: c, w: csv.NewWriter(c.Writer)}
+	default:
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "format must be csv or json"})
+		return
+	}
+
+	if err := h.svc.WriteStatement(c, id, from, to, w); err != nil {
+		if !w.started() {
+			h.handleErr(c, err)
+			return
+		}
+		// the status is already sent, so a failure can only cut the body short
+		_ = c.Error(err)
+	}
+}
+
+func parseStatementTime(raw string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+type statementStream interface {
+	application.StatementWriter
+	started() bool
+}
+
+type csvStatementWriter struct {
+	c     *gin.Context
+	w     *csv.Writer
+	cur   string
+	lines int
+	begun bool
+}
+
+func (s *csvStatementWriter) started() bool { return s.begun }
+
+func (s *csvStatementWriter) Begin(st *application.Statement) error {
+	s.cur = st.Account.Currency
+	s.begun = true
+	s.c.Header("Content-Type", "text/csv")
+	s.c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=statement-%d.csv", st.Account.AccountID))
+	s.c.Status(http.StatusOK)
+
+	if err := s.w.Write([]string{"date", "transaction_id", "kind", "counterparty_account_id", "debit", "credit", "balance"}); err != nil {
+		return err
+	}
+	return s.w.Write([]string{st.From.UTC().Format(time.RFC3339), "", "opening_balance", "", "", "", formatAmount(st.OpeningBalance, s.cur)})
+}
+
+func (s *csvStatementWriter) Line(line *application.StatementLine) error {
+	rec := line.Transaction
+	err := s.w.Write([]string{
+		rec.CreatedAt.UTC().Format(time.RFC3339),
+		rec.ID.String(),
+		string(rec.Kind),
+		strconv.FormatInt(line.Counterparty, 10),
+		formatAmount(line.Debit, s.cur),
+		formatAmount(line.Credit, s.cur),
+		formatAmount(line.Balance, s.cur),
+	})
+	if err != nil {
+		return err
+	}
+	if s.lines++; s.lines%statementFlushLines == 0 {
+		return s.flush()
+	}
+	return nil
+}
+
+func (s *csvStatementWriter) End(st *application.Statement) error {
+	err := s.w.Write([]string{
+		st.To.UTC().Format(time.RFC3339), "", "closing_balance", "",
+		formatAmount(st.TotalDebits, s.cur),
+		formatAmount(st.TotalCredits, s.cur),
+		formatAmount(st.ClosingBalance, s.cur),
+	})
+	if err != nil {
+		return err
+	}
+	return s.flush()
+}
+
+func (s *csvStatementWriter) flush() error {
+	s.w.Flush()
+	s.c.Writer.Flush()
+	return s.w.Error()
+}
+
+// jsonStatementWriter writes a dto.StatementHeaderResponse and a dto.StatementTotalsResponse
+// as one object, with the entries array streamed between them
+type jsonStatementWriter struct {
+	c     *gin.Context
+	cur   string
+	lines int
+	begun bool
+}
+
+func (s *jsonStatementWriter) started() bool { return s.begun }
+
+func (s *jsonStatementWriter) Begin(st *application.Statement) error {
+	s.cur = st.Account.Currency
+	head, err := json.Marshal(dto.StatementHeaderResponse{
+		AccountID:      st.Account.AccountID,
+		Currency:       st.Account.Currency,
+		From:           st.From,
+		To:             st.To,
+		OpeningBalance: formatAmount(st.OpeningBalance, s.cur),
+	})
+	if err != nil {
+		return err
+	}
+
+	s.begun = true
+	s.c.Header("Content-Type", "application/json")
+	s.c.Status(http.StatusOK)
+	// drop the closing brace to carry on with the entries
+	return s.write(append(head[:len(head)-1], `,"entries":[`...))
+}
+
+func (s *jsonStatementWriter) Line(line *application.StatementLine) error {
+	rec := line.Transaction
+	entry, err := json.Marshal(dto.StatementEntryResponse{
+		Date:          rec.CreatedAt,
+		TransactionID: rec.ID.String(),
+		Kind:          string(rec.Kind),
+		Counterparty:  line.Counterparty,
+		Debit:         formatAmount(line.Debit, s.cur),
+		Credit:        formatAmount(line.Credit, s.cur),
+		Balance:       formatAmount(line.Balance, s.cur),
+	})
+	if err != nil {
+		return err
+	}
+	if s.lines > 0 {
+		entry = append([]byte{','}, entry...)
+	}
+	if err := s.write(entry); err != nil {
+		return err
+	}
+	if s.lines++; s.lines%statementFlushLines == 0 {
+		s.c.Writer.Flush()
+	}
+	return nil
+}
+
+func (s *jsonStatementWriter) End(st *application.Statement) error {
+	tail, err := json.Marshal(dto.StatementTotalsResponse{
+		ClosingBalance: formatAmount(st.ClosingBalance, s.cur),
+		TotalDebits:    formatAmount(st.TotalDebits, s.cur),
+		TotalCredits:   formatAmount(st.TotalCredits, s.cur),
+	})
+	if err != nil {
+		return err
+	}
+	// close the entries and merge the totals into the object opened in Begin
+	if err := s.write(append([]byte("],"), tail[1:]...)); err != nil {
+		return err
+	}
+	s.c.Writer.Flush()
+	return nil
+}
+
+func (s *jsonStatementWriter) write(b []byte) error {
+	_, err := s.c.Writer.Write(b)
+	return err
+}
@@ -0,0 +1,223 @@
+package http
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/maneeshsagar/tps/internal/adapters/http/dto"
+	"github.com/maneeshsagar/tps/internal/application"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"github.com/maneeshsagar/tps/pkg/currency"
+)
+
+// GetTransaction returns a completed transaction with its reversals and async submission
+func (h *Handler) GetTransaction(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid transaction id"})
+		return
+	}
+
+	details, err := h.svc.GetTransactionDetails(c, id)
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+
+	resp := dto.TransactionDetailResponse{
+		TransactionResponse: toTransactionResponse(details.Transaction),
+		Reversals:           make([]dto.TransactionResponse, 0, len(details.Reversals)),
+	}
+	for _, r := range details.Reversals {
+		resp.Reversals = append(resp.Reversals, toTransactionResponse(r))
+	}
+	if details.Submission != nil {
+		submission := toAsyncStatusResponse(details.Submission)
+		resp.AsyncSubmission = &submission
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// ListTransactions lists all transactions. Filters: counterparty, kind, currency,
+// min_amount / max_amount (need currency), from / to, external_ref, description
+// (a case-insensitive substring) and metadata[key]=value, plus cursor and limit.
+func (h *Handler) ListTransactions(c *gin.Context) {
+	filter, ok := parseTransactionFilter(c)
+	if !ok {
+		return
+	}
+	if c.Query("min_amount") != "" || c.Query("max_amount") != "" {
+		cur, err := currency.Lookup(c.Query("currency"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "currency is required with an amount range"})
+			return
+		}
+		if !parseAmountRange(c, cur, &filter) {
+			return
+		}
+	}
+	after, limit, ok := parsePage(c)
+	if !ok {
+		return
+	}
+
+	page, err := h.svc.ListTransactions(c, filter, after, limit)
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toTransactionListResponse(page))
+}
+
+// ListAccountTransactions lists the transactions of one account. On top of the filters
+// of ListTransactions it takes a direction (debit or credit); amounts are in the account's currency.
+func (h *Handler) ListAccountTransactions(c *gin.Context) {
+	id, ok := parseAccountID(c)
+	if !ok {
+		return
+	}
+	filter, ok := parseTransactionFilter(c)
+	if !ok {
+		return
+	}
+	filter.Direction = domain.EntryDirection(c.Query("direction"))
+	if c.Query("min_amount") != "" || c.Query("max_amount") != "" {
+		cur, ok := h.sourceCurrency(c, id)
+		if !ok {
+			return
+		}
+		if !parseAmountRange(c, cur, &filter) {
+			return
+		}
+	}
+	after, limit, ok := parsePage(c)
+	if !ok {
+		return
+	}
+
+	page, err := h.svc.ListAccountTransactions(c, id, filter, after, limit)
+	if err != nil {
+		h.handleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toTransactionListResponse(page))
+}
+
+// parseTransactionFilter reads the filters shared by both listings, writing an error response on failure
+func parseTransactionFilter(c *gin.Context) (domain.TransactionFilter, bool) {
+	filter := domain.TransactionFilter{
+		Kind:        domain.TxKind(c.Query("kind")),
+		Currency:    strings.ToUpper(c.Query("currency")),
+		ExternalRef: c.Query("external_ref"),
+		Description: c.Query("description"),
+	}
+	if metadata := c.QueryMap("metadata"); len(metadata) > 0 {
+		filter.Metadata = metadata
+	}
+	if raw := c.Query("counterparty"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || id <= 0 {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid counterparty"})
+			return filter, false
+		}
+		filter.Counterparty = id
+	}
+
+	var err error
+	if raw := c.Query("from"); raw != "" {
+		if filter.From, err = parseStatementTime(raw, false); err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid from"})
+			return filter, false
+		}
+	}
+	if raw := c.Query("to"); raw != "" {
+		if filter.To, err = parseStatementTime(raw, true); err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid to"})
+			return filter, false
+		}
+	}
+	return filter, true
+}
+
+func parseAmountRange(c *gin.Context, cur currency.Currency, filter *domain.TransactionFilter) bool {
+	var err error
+	if raw := c.Query("min_amount"); raw != "" {
+		if filter.MinAmount, err = cur.Parse(raw); err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid min_amount"})
+			return false
+		}
+	}
+	if raw := c.Query("max_amount"); raw != "" {
+		if filter.MaxAmount, err = cur.Parse(raw); err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid max_amount"})
+			return false
+		}
+	}
+	return true
+}
+
+func parsePage(c *gin.Context) (*domain.TxCursor, int, bool) {
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid limit"})
+			return nil, 0, false
+		}
+		limit = n
+	}
+
+	raw := c.Query("cursor")
+	if raw == "" {
+		return nil, limit, true
+	}
+	cursor, err := decodeCursor(raw)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid cursor"})
+		return nil, 0, false
+	}
+	return cursor, limit, true
+}
+
+// a cursor is the created_at (unix nanoseconds) and id of the last row of a page, made opaque
+func encodeCursor(cursor *domain.TxCursor) string {
+	raw := strconv.FormatInt(cursor.CreatedAt.UnixNano(), 10) + "_" + cursor.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(s string) (*domain.TxCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	nanos, id, ok := strings.Cut(string(raw), "_")
+	if !ok {
+		return nil, errors.New("malformed cursor")
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.TxCursor{CreatedAt: time.Unix(0, n), ID: uid}, nil
+}
+
+func toTransactionListResponse(page *application.TransactionPage) dto.TransactionListResponse {
+	resp := dto.TransactionListResponse{Transactions: make([]dto.TransactionResponse, 0, len(page.Transactions))}
+	for _, t := range page.Transactions {
+		resp.Transactions = append(resp.Transactions, toTransactionResponse(t))
+	}
+	if page.Next != nil {
+		resp.NextCursor = encodeCursor(page.Next)
+	}
+	return resp
+}
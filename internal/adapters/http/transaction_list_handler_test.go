@@ -0,0 +1,57 @@
+package http
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	cases := []time.Time{
+		time.Date(2024, 3, 1, 12, 30, 45, 123456789, time.UTC),
+		time.Date(1969, 12, 31, 23, 59, 59, 0, time.UTC),
+		time.Unix(0, 0),
+	}
+
+	for _, at := range cases {
+		want := &domain.TxCursor{CreatedAt: at, ID: uuid.New()}
+		got, err := decodeCursor(encodeCursor(want))
+		if err != nil {
+			t.Errorf("%s: decodeCursor error: %v", at, err)
+			continue
+		}
+		if !got.CreatedAt.Equal(want.CreatedAt) || got.ID != want.ID {
+			t.Errorf("%s: round trip = %+v, want %+v", at, got, want)
+		}
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+	id := uuid.New().String()
+	cases := []struct {
+		name   string
+		cursor string
+	}{
+		{"not base64", "not*base64!"},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte("1_" + id))},
+		{"no separator", encode("1709296245000000000" + id)},
+		{"nanos not a number", encode("yesterday_" + id)},
+		{"nanos out of range", encode("99999999999999999999_" + id)},
+		{"empty nanos", encode("_" + id)},
+		{"bad uuid", encode("1709296245000000000_not-a-uuid")},
+		{"empty uuid", encode("1709296245000000000_")},
+		{"empty", ""},
+	}
+
+	for _, tc := range cases {
+		if cursor, err := decodeCursor(tc.cursor); err == nil {
+			t.Errorf("%s: decodeCursor(%q) = %+v, want an error", tc.name, tc.cursor, cursor)
+		}
+	}
+}
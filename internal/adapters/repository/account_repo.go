@@ -1,62 +1,205 @@
 package repository
 
 import (
+	"encoding/json"
 	"errors"
 	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/maneeshsagar/tps/internal/core/domain"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type AccountModel struct {
-	AccountID int64 `gorm:"primaryKey;column:account_id"`
-	Balance   int64 `gorm:"column:balance"`
+	AccountID      int64  `gorm:"primaryKey;column:account_id"`
+	Currency       string `gorm:"column:currency;type:char(3);not null;default:'INR';uniqueIndex:idx_accounts_treasury_currency,where:type = 'treasury'"`
+	Balance        int64  `gorm:"column:balance"`
+	OpeningBalance int64  `gorm:"column:opening_balance"`
+	HeldBalance    int64  `gorm:"column:held_balance"`
+	Type           string `gorm:"column:type;not null;default:'customer_wallet'"`
+	OwnerName      string `gorm:"column:owner_name"`
+	CustomerRef    string `gorm:"column:customer_ref;index"`
+	Labels         string `gorm:"column:labels;type:jsonb;not null;default:'{}'"`
+	Status         string `gorm:"column:status;not null;default:'active'"`
+	FreezeScope    string `gorm:"column:freeze_scope"`
+	StatusReason   string `gorm:"column:status_reason"`
+	OverdraftLimit int64  `gorm:"column:overdraft_limit;not null;default:0"`
+	MinimumBalance int64  `gorm:"column:minimum_balance;not null;default:0"`
+	MaximumBalance int64  `gorm:"column:maximum_balance;not null;default:0"`
+	// CreatedAt defaults to now() so rows written before the column existed get the migration time
+	CreatedAt time.Time `gorm:"column:created_at;not null;default:now()"`
 }
 
 func (AccountModel) TableName() string {
 	return "accounts"
 }
 
+type AccountStatusChangeModel struct {
+	ID          uuid.UUID `gorm:"primaryKey;column:id;type:uuid"`
+	AccountID   int64     `gorm:"column:account_id;index"`
+	FromStatus  string    `gorm:"column:from_status"`
+	ToStatus    string    `gorm:"column:to_status"`
+	FreezeScope string    `gorm:"column:freeze_scope"`
+	Reason      string    `gorm:"column:reason"`
+	CreatedAt   time.Time `gorm:"column:created_at"`
+}
+
+func (AccountStatusChangeModel) TableName() string {
+	return "account_status_changes"
+}
+
 type AccountRepo struct {
 	db *gorm.DB
+	// forUpdate locks the rows read inside a db transaction, so a balance written back
+	// can never overwrite an AddToBalance increment committed in between
+	forUpdate bool
 }
 
 func NewAccountRepo(db *gorm.DB) *AccountRepo {
-	return &AccountRepo{db}
+	return &AccountRepo{db: db}
 }
 
 func (r *AccountRepo) GetByID(id int64) (*domain.Account, error) {
 	var m AccountModel
-	if err := r.db.First(&m, "account_id = ?", id).Error; err != nil {
+	q := r.db
+	if r.forUpdate {
+		q = q.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+	if err := q.First(&m, "account_id = ?", id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, domain.ErrAccountNotFound
 		}
 		return nil, err
 	}
-	return &domain.Account{
-		AccountID: m.AccountID,
-		Balance:   m.Balance,
-	}, nil
+	return toAccount(m)
+}
+
+func toAccount(m AccountModel) (*domain.Account, error) {
+	acc := &domain.Account{
+		AccountID:      m.AccountID,
+		Currency:       m.Currency,
+		Balance:        m.Balance,
+		OpeningBalance: m.OpeningBalance,
+		HeldBalance:    m.HeldBalance,
+		Type:           domain.AccountType(m.Type),
+		OwnerName:      m.OwnerName,
+		CustomerRef:    m.CustomerRef,
+		Status:         accountStatus(m.Status),
+		FreezeScope:    domain.FreezeScope(m.FreezeScope),
+		StatusReason:   m.StatusReason,
+		BalancePolicy: domain.BalancePolicy{
+			OverdraftLimit: m.OverdraftLimit,
+			MinimumBalance: m.MinimumBalance,
+			MaximumBalance: m.MaximumBalance,
+		},
+		CreatedAt: m.CreatedAt,
+	}
+	if acc.Type == "" {
+		acc.Type = domain.AccountTypeCustomer
+	}
+	if m.Labels != "" {
+		if err := json.Unmarshal([]byte(m.Labels), &acc.Labels); err != nil {
+			return nil, err
+		}
+	}
+	return acc, nil
+}
+
+func (r *AccountRepo) ListByType(accountType domain.AccountType) ([]*domain.Account, error) {
+	return r.list(r.db.Where("type = ?", string(accountType)).Order("account_id"))
+}
+
+func (r *AccountRepo) GetTreasury(currency string) (*domain.Account, error) {
+	var m AccountModel
+	err := r.db.First(&m, "type = ? AND currency = ?", string(domain.AccountTypeTreasury), currency).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrTreasuryNotFound
+		}
+		return nil, err
+	}
+	return toAccount(m)
+}
+
+func (r *AccountRepo) List(afterID int64, limit int) ([]*domain.Account, error) {
+	return r.list(r.db.Where("account_id > ?", afterID).Order("account_id").Limit(limit))
+}
+
+func (r *AccountRepo) list(q *gorm.DB) ([]*domain.Account, error) {
+	var models []AccountModel
+	if err := q.Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	accounts := make([]*domain.Account, 0, len(models))
+	for _, m := range models {
+		acc, err := toAccount(m)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, acc)
+	}
+	return accounts, nil
 }
 
 func (r *AccountRepo) Update(account *domain.Account) error {
 	result := r.db.Model(&AccountModel{}).
 		Where("account_id = ?", account.AccountID).
-		Update("balance", account.Balance)
+		Updates(map[string]interface{}{
+			"balance":      account.Balance,
+			"held_balance": account.HeldBalance,
+		})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
 
+func (r *AccountRepo) AddToBalance(accountID, amount int64) error {
+	result := r.db.Model(&AccountModel{}).
+		Where("account_id = ?", accountID).
+		Update("balance", gorm.Expr("balance + ?", amount))
 	if result.Error != nil {
 		return result.Error
 	}
+	if result.RowsAffected == 0 {
+		return domain.ErrAccountNotFound
+	}
 	return nil
 }
 
 func (r *AccountRepo) Create(account *domain.Account) error {
+	labels, err := json.Marshal(account.Labels)
+	if err != nil {
+		return err
+	}
+	if account.Labels == nil {
+		labels = []byte("{}")
+	}
+
 	m := AccountModel{
-		AccountID: account.AccountID,
-		Balance:   account.Balance,
+		AccountID:      account.AccountID,
+		Currency:       account.Currency,
+		Balance:        account.Balance,
+		OpeningBalance: account.OpeningBalance,
+		Type:           string(account.Type),
+		OwnerName:      account.OwnerName,
+		CustomerRef:    account.CustomerRef,
+		Labels:         string(labels),
+		Status:         string(accountStatus(string(account.Status))),
+		OverdraftLimit: account.OverdraftLimit,
+		MinimumBalance: account.MinimumBalance,
+		MaximumBalance: account.MaximumBalance,
+		CreatedAt:      account.CreatedAt,
 	}
 
 	if err := r.db.Create(&m).Error; err != nil {
+		if strings.Contains(err.Error(), "idx_accounts_treasury_currency") {
+			return domain.ErrTreasuryExists
+		}
 		if strings.Contains(err.Error(), "duplicate key") ||
 			strings.Contains(err.Error(), "unique constraint") {
 			return domain.ErrAccountAlreadyExists
@@ -65,3 +208,65 @@ func (r *AccountRepo) Create(account *domain.Account) error {
 	}
 	return nil
 }
+
+func (r *AccountRepo) UpdateStatus(account *domain.Account, change *domain.AccountStatusChange) error {
+	err := r.db.Model(&AccountModel{}).
+		Where("account_id = ?", account.AccountID).
+		Updates(map[string]interface{}{
+			"status":        string(account.Status),
+			"freeze_scope":  string(account.FreezeScope),
+			"status_reason": account.StatusReason,
+		}).Error
+	if err != nil {
+		return err
+	}
+
+	return r.db.Create(&AccountStatusChangeModel{
+		ID:          change.ID,
+		AccountID:   change.AccountID,
+		FromStatus:  string(change.From),
+		ToStatus:    string(change.To),
+		FreezeScope: string(change.FreezeScope),
+		Reason:      change.Reason,
+		CreatedAt:   change.CreatedAt,
+	}).Error
+}
+
+func (r *AccountRepo) UpdateBalancePolicy(account *domain.Account) error {
+	return r.db.Model(&AccountModel{}).
+		Where("account_id = ?", account.AccountID).
+		Updates(map[string]interface{}{
+			"overdraft_limit": account.OverdraftLimit,
+			"minimum_balance": account.MinimumBalance,
+			"maximum_balance": account.MaximumBalance,
+		}).Error
+}
+
+func (r *AccountRepo) ListStatusChanges(accountID int64) ([]*domain.AccountStatusChange, error) {
+	var models []AccountStatusChangeModel
+	if err := r.db.Where("account_id = ?", accountID).Order("created_at").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	changes := make([]*domain.AccountStatusChange, 0, len(models))
+	for _, m := range models {
+		changes = append(changes, &domain.AccountStatusChange{
+			ID:          m.ID,
+			AccountID:   m.AccountID,
+			From:        domain.AccountStatus(m.FromStatus),
+			To:          domain.AccountStatus(m.ToStatus),
+			FreezeScope: domain.FreezeScope(m.FreezeScope),
+			Reason:      m.Reason,
+			CreatedAt:   m.CreatedAt,
+		})
+	}
+	return changes, nil
+}
+
+// accountStatus treats rows written before account statuses existed as active
+func accountStatus(s string) domain.AccountStatus {
+	if s == "" {
+		return domain.AccountActive
+	}
+	return domain.AccountStatus(s)
+}
@@ -8,15 +8,32 @@ import (
 	"gorm.io/gorm"
 )
 
+// AsyncTransactionStatusModel keeps an external_ref unique per source account among the
+// submissions that are live or completed; a failed, rejected or cancelled one frees it
+// for a retry.
 type AsyncTransactionStatusModel struct {
 	ID          string `gorm:"primaryKey;column:id"`
-	FromAccount int64  `gorm:"column:from_account"`
+	FromAccount int64  `gorm:"column:from_account;uniqueIndex:idx_async_status_from_live_external_ref,priority:1,where:external_ref <> '' AND status <> 'failed' AND status <> 'rejected' AND status <> 'cancelled'"`
 	ToAccount   int64  `gorm:"column:to_account"`
 	Amount      int64  `gorm:"column:amount"`
-	Status      string `gorm:"column:status"`
+	Currency    string `gorm:"column:currency;type:char(3);not null;default:'INR'"`
+	Status      string `gorm:"column:status;index:idx_async_status_execute_at"`
 	Error       string `gorm:"column:error"`
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	Description string `gorm:"column:description;not null;default:''"`
+	ExternalRef string `gorm:"column:external_ref;not null;default:'';uniqueIndex:idx_async_status_from_live_external_ref,priority:2,where:external_ref <> '' AND status <> 'failed' AND status <> 'rejected' AND status <> 'cancelled'"`
+	Metadata    string `gorm:"column:metadata;type:jsonb;not null;default:'{}'"`
+	// TransactionID is a string like ID, empty until the transfer completes
+	TransactionID string     `gorm:"column:transaction_id;index"`
+	ExecuteAt     *time.Time `gorm:"column:execute_at;index:idx_async_status_execute_at"`
+	// the maker-checker trail, set only for transfers held for approval
+	ApprovalRequestedBy string     `gorm:"column:approval_requested_by;not null;default:''"`
+	ApprovalRequestedAt *time.Time `gorm:"column:approval_requested_at"`
+	ApprovalDecision    string     `gorm:"column:approval_decision;not null;default:''"`
+	ApprovalDecidedBy   string     `gorm:"column:approval_decided_by;not null;default:''"`
+	ApprovalDecidedAt   *time.Time `gorm:"column:approval_decided_at"`
+	ApprovalReason      string     `gorm:"column:approval_reason;not null;default:''"`
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
 }
 
 func (AsyncTransactionStatusModel) TableName() string {
@@ -32,16 +49,37 @@ func NewAsyncTransactionRepo(db *gorm.DB) *AsyncTransactionRepo {
 }
 
 func (r *AsyncTransactionRepo) Create(tx *domain.AsyncTransaction) error {
+	metadata, err := marshalMetadata(tx.Metadata)
+	if err != nil {
+		return err
+	}
 	model := &AsyncTransactionStatusModel{
 		ID:          tx.ID.String(),
 		FromAccount: tx.FromAccount,
 		ToAccount:   tx.ToAccount,
 		Amount:      tx.Amount,
+		Currency:    tx.Currency,
 		Status:      string(tx.Status),
+		Description: tx.Description,
+		ExternalRef: tx.ExternalRef,
+		Metadata:    metadata,
 		CreatedAt:   tx.CreatedAt,
 		UpdatedAt:   tx.UpdatedAt,
 	}
-	return r.db.Create(model).Error
+	if !tx.ExecuteAt.IsZero() {
+		model.ExecuteAt = &tx.ExecuteAt
+	}
+	if !tx.RequestedAt.IsZero() {
+		model.ApprovalRequestedBy = tx.RequestedBy
+		model.ApprovalRequestedAt = &tx.RequestedAt
+	}
+	if err := r.db.Create(model).Error; err != nil {
+		if tx.ExternalRef != "" && isUniqueViolation(err) {
+			return domain.ErrDuplicateRef
+		}
+		return err
+	}
+	return nil
 }
 
 func (r *AsyncTransactionRepo) GetByID(id uuid.UUID) (*domain.AsyncTransaction, error) {
@@ -52,17 +90,29 @@ func (r *AsyncTransactionRepo) GetByID(id uuid.UUID) (*domain.AsyncTransaction,
 		}
 		return nil, err
 	}
-	uid, _ := uuid.Parse(m.ID)
-	return &domain.AsyncTransaction{
-		ID:          uid,
-		FromAccount: m.FromAccount,
-		ToAccount:   m.ToAccount,
-		Amount:      m.Amount,
-		Status:      domain.TxStatus(m.Status),
-		Error:       m.Error,
-		CreatedAt:   m.CreatedAt,
-		UpdatedAt:   m.UpdatedAt,
-	}, nil
+	return toAsyncTransaction(m), nil
+}
+
+func (r *AsyncTransactionRepo) GetByTransactionID(transactionID uuid.UUID) (*domain.AsyncTransaction, error) {
+	var m AsyncTransactionStatusModel
+	if err := r.db.Where("transaction_id = ?", transactionID.String()).First(&m).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrTransactionNotFound
+		}
+		return nil, err
+	}
+	return toAsyncTransaction(m), nil
+}
+
+func (r *AsyncTransactionRepo) Complete(id, transactionID uuid.UUID) error {
+	return r.db.Model(&AsyncTransactionStatusModel{}).
+		Where("id = ?", id.String()).
+		Updates(map[string]interface{}{
+			"status":         string(domain.TxStatusCompleted),
+			"error":          "",
+			"transaction_id": transactionID.String(),
+			"updated_at":     time.Now(),
+		}).Error
 }
 
 func (r *AsyncTransactionRepo) UpdateStatus(id uuid.UUID, status domain.TxStatus, errMsg string) error {
@@ -74,3 +124,109 @@ func (r *AsyncTransactionRepo) UpdateStatus(id uuid.UUID, status domain.TxStatus
 			"updated_at": time.Now(),
 		}).Error
 }
+
+func (r *AsyncTransactionRepo) TransitionStatus(id uuid.UUID, from, to domain.TxStatus) (bool, error) {
+	result := r.db.Model(&AsyncTransactionStatusModel{}).
+		Where("id = ? AND status = ?", id.String(), string(from)).
+		Updates(map[string]interface{}{
+			"status":     string(to),
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *AsyncTransactionRepo) RecordDecision(tx *domain.AsyncTransaction) (bool, error) {
+	result := r.db.Model(&AsyncTransactionStatusModel{}).
+		Where("id = ? AND status = ?", tx.ID.String(), string(domain.TxStatusPendingApproval)).
+		Updates(map[string]interface{}{
+			"status":              string(tx.Status),
+			"approval_decision":   string(tx.Decision),
+			"approval_decided_by": tx.DecidedBy,
+			"approval_decided_at": tx.DecidedAt,
+			"approval_reason":     tx.Reason,
+			"updated_at":          tx.UpdatedAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *AsyncTransactionRepo) ListByStatus(status domain.TxStatus, limit int) ([]*domain.AsyncTransaction, error) {
+	var models []AsyncTransactionStatusModel
+	err := r.db.Where("status = ?", string(status)).Order("created_at").Limit(limit).Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	txs := make([]*domain.AsyncTransaction, 0, len(models))
+	for _, m := range models {
+		txs = append(txs, toAsyncTransaction(m))
+	}
+	return txs, nil
+}
+
+func (r *AsyncTransactionRepo) ClaimDue(now time.Time, limit int) ([]*domain.AsyncTransaction, error) {
+	var models []AsyncTransactionStatusModel
+	// SKIP LOCKED lets several replicas claim disjoint batches without waiting on each other
+	err := r.db.Raw(`
+		UPDATE async_transactions_status SET status = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM async_transactions_status
+			WHERE status = ? AND execute_at <= ?
+			ORDER BY execute_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		string(domain.TxStatusExecuting), time.Now(),
+		string(domain.TxStatusScheduled), now, limit,
+	).Scan(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	txs := make([]*domain.AsyncTransaction, 0, len(models))
+	for _, m := range models {
+		txs = append(txs, toAsyncTransaction(m))
+	}
+	return txs, nil
+}
+
+func toAsyncTransaction(m AsyncTransactionStatusModel) *domain.AsyncTransaction {
+	uid, _ := uuid.Parse(m.ID)
+	tx := &domain.AsyncTransaction{
+		ID:          uid,
+		FromAccount: m.FromAccount,
+		ToAccount:   m.ToAccount,
+		Amount:      m.Amount,
+		Currency:    m.Currency,
+		Status:      domain.TxStatus(m.Status),
+		Error:       m.Error,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+	tx.Description = m.Description
+	tx.ExternalRef = m.ExternalRef
+	tx.Metadata = unmarshalMetadata(m.Metadata)
+	if m.ExecuteAt != nil {
+		tx.ExecuteAt = *m.ExecuteAt
+	}
+	if m.TransactionID != "" {
+		tx.TransactionID, _ = uuid.Parse(m.TransactionID)
+	}
+	if m.ApprovalRequestedAt != nil {
+		tx.RequestedBy = m.ApprovalRequestedBy
+		tx.RequestedAt = *m.ApprovalRequestedAt
+		tx.Decision = domain.ApprovalDecision(m.ApprovalDecision)
+		tx.DecidedBy = m.ApprovalDecidedBy
+		tx.Reason = m.ApprovalReason
+	}
+	if m.ApprovalDecidedAt != nil {
+		tx.DecidedAt = *m.ApprovalDecidedAt
+	}
+	return tx
+}
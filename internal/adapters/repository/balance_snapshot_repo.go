@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BalanceSnapshotModel struct {
+	AccountID int64     `gorm:"primaryKey;column:account_id;autoIncrement:false"`
+	At        time.Time `gorm:"primaryKey;column:at"`
+	Currency  string    `gorm:"column:currency;type:char(3)"`
+	Balance   int64     `gorm:"column:balance"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+func (BalanceSnapshotModel) TableName() string {
+	return "balance_snapshots"
+}
+
+type BalanceSnapshotRepo struct {
+	db *gorm.DB
+}
+
+func NewBalanceSnapshotRepo(db *gorm.DB) *BalanceSnapshotRepo {
+	return &BalanceSnapshotRepo{db}
+}
+
+func (r *BalanceSnapshotRepo) Create(snapshot *domain.BalanceSnapshot) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&BalanceSnapshotModel{
+		AccountID: snapshot.AccountID,
+		At:        snapshot.At,
+		Currency:  snapshot.Currency,
+		Balance:   snapshot.Balance,
+		CreatedAt: snapshot.CreatedAt,
+	}).Error
+}
+
+func (r *BalanceSnapshotRepo) Latest(accountID int64, at time.Time) (*domain.BalanceSnapshot, error) {
+	var m BalanceSnapshotModel
+	err := r.db.Where("account_id = ? AND at <= ?", accountID, at).Order("at DESC").First(&m).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrSnapshotNotFound
+		}
+		return nil, err
+	}
+	return &domain.BalanceSnapshot{
+		AccountID: m.AccountID,
+		Currency:  m.Currency,
+		Balance:   m.Balance,
+		At:        m.At,
+		CreatedAt: m.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"gorm.io/gorm"
+)
+
+type EscrowModel struct {
+	ID                   uuid.UUID `gorm:"primaryKey;column:id;type:uuid"`
+	BuyerAccountID       int64     `gorm:"column:buyer_account_id;index"`
+	SellerAccountID      int64     `gorm:"column:seller_account_id;index"`
+	EscrowAccountID      int64     `gorm:"column:escrow_account_id"`
+	Buyer                string    `gorm:"column:buyer"`
+	Seller               string    `gorm:"column:seller"`
+	Arbiter              string    `gorm:"column:arbiter"`
+	Amount               int64     `gorm:"column:amount"`
+	Currency             string    `gorm:"column:currency;type:char(3)"`
+	Released             int64     `gorm:"column:released;not null;default:0"`
+	Refunded             int64     `gorm:"column:refunded;not null;default:0"`
+	Status               string    `gorm:"column:status;index:idx_escrows_status_expires"`
+	Description          string    `gorm:"column:description"`
+	DisputeReason        string    `gorm:"column:dispute_reason"`
+	FundingTransactionID uuid.UUID `gorm:"column:funding_transaction_id;type:uuid"`
+	ExpiresAt            time.Time `gorm:"column:expires_at;index:idx_escrows_status_expires"`
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+}
+
+func (EscrowModel) TableName() string {
+	return "escrows"
+}
+
+type EscrowRepo struct {
+	db *gorm.DB
+}
+
+func NewEscrowRepo(db *gorm.DB) *EscrowRepo {
+	return &EscrowRepo{db}
+}
+
+func (r *EscrowRepo) Create(escrow *domain.Escrow) error {
+	m := EscrowModel{
+		ID:                   escrow.ID,
+		BuyerAccountID:       escrow.BuyerAccountID,
+		SellerAccountID:      escrow.SellerAccountID,
+		EscrowAccountID:      escrow.EscrowAccountID,
+		Buyer:                escrow.Buyer,
+		Seller:               escrow.Seller,
+		Arbiter:              escrow.Arbiter,
+		Amount:               escrow.Amount,
+		Currency:             escrow.Currency,
+		Released:             escrow.Released,
+		Refunded:             escrow.Refunded,
+		Status:               string(escrow.Status),
+		Description:          escrow.Description,
+		DisputeReason:        escrow.DisputeReason,
+		FundingTransactionID: escrow.FundingTransactionID,
+		ExpiresAt:            escrow.ExpiresAt,
+		CreatedAt:            escrow.CreatedAt,
+		UpdatedAt:            escrow.UpdatedAt,
+	}
+	return r.db.Create(&m).Error
+}
+
+func (r *EscrowRepo) GetByID(id uuid.UUID) (*domain.Escrow, error) {
+	var m EscrowModel
+	if err := r.db.First(&m, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrEscrowNotFound
+		}
+		return nil, err
+	}
+	return toEscrow(m), nil
+}
+
+func (r *EscrowRepo) Update(escrow *domain.Escrow) error {
+	return r.db.Model(&EscrowModel{}).
+		Where("id = ?", escrow.ID).
+		Updates(map[string]interface{}{
+			"released":       escrow.Released,
+			"refunded":       escrow.Refunded,
+			"status":         string(escrow.Status),
+			"dispute_reason": escrow.DisputeReason,
+			"updated_at":     escrow.UpdatedAt,
+		}).Error
+}
+
+func (r *EscrowRepo) ListExpired(now time.Time, limit int) ([]*domain.Escrow, error) {
+	var models []EscrowModel
+	err := r.db.
+		Where("status = ? AND expires_at <= ?", string(domain.EscrowFunded), now).
+		Order("expires_at").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	escrows := make([]*domain.Escrow, 0, len(models))
+	for _, m := range models {
+		escrows = append(escrows, toEscrow(m))
+	}
+	return escrows, nil
+}
+
+func toEscrow(m EscrowModel) *domain.Escrow {
+	return &domain.Escrow{
+		ID:                   m.ID,
+		BuyerAccountID:       m.BuyerAccountID,
+		SellerAccountID:      m.SellerAccountID,
+		EscrowAccountID:      m.EscrowAccountID,
+		Buyer:                m.Buyer,
+		Seller:               m.Seller,
+		Arbiter:              m.Arbiter,
+		Amount:               m.Amount,
+		Currency:             m.Currency,
+		Released:             m.Released,
+		Refunded:             m.Refunded,
+		Status:               domain.EscrowStatus(m.Status),
+		Description:          m.Description,
+		DisputeReason:        m.DisputeReason,
+		FundingTransactionID: m.FundingTransactionID,
+		ExpiresAt:            m.ExpiresAt,
+		CreatedAt:            m.CreatedAt,
+		UpdatedAt:            m.UpdatedAt,
+	}
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"gorm.io/gorm"
+)
+
+type FeeRuleModel struct {
+	ID               uuid.UUID `gorm:"primaryKey;column:id;type:uuid"`
+	Name             string    `gorm:"column:name;not null"`
+	Type             string    `gorm:"column:type;not null"`
+	Currency         string    `gorm:"column:currency;type:char(3);not null"`
+	AccountType      string    `gorm:"column:account_type"`
+	Flat             int64     `gorm:"column:flat"`
+	RateBps          int64     `gorm:"column:rate_bps"`
+	MinFee           int64     `gorm:"column:min_fee"`
+	MaxFee           int64     `gorm:"column:max_fee"`
+	Tiers            string    `gorm:"column:tiers;type:jsonb;not null;default:'[]'"`
+	RevenueAccountID int64     `gorm:"column:revenue_account_id"`
+	Priority         int       `gorm:"column:priority"`
+	Active           bool      `gorm:"column:active;index"`
+	CreatedAt        time.Time `gorm:"column:created_at"`
+}
+
+func (FeeRuleModel) TableName() string {
+	return "fee_rules"
+}
+
+// feeTier is the stored form of a tier
+type feeTier struct {
+	UpTo    int64 `json:"up_to"`
+	Flat    int64 `json:"flat"`
+	RateBps int64 `json:"rate_bps"`
+}
+
+type FeeRuleRepo struct {
+	db *gorm.DB
+}
+
+func NewFeeRuleRepo(db *gorm.DB) *FeeRuleRepo {
+	return &FeeRuleRepo{db}
+}
+
+func (r *FeeRuleRepo) Create(rule *domain.FeeRule) error {
+	tiers := make([]feeTier, 0, len(rule.Tiers))
+	for _, t := range rule.Tiers {
+		tiers = append(tiers, feeTier{UpTo: t.UpTo, Flat: t.Flat, RateBps: t.RateBps})
+	}
+	data, err := json.Marshal(tiers)
+	if err != nil {
+		return err
+	}
+
+	m := FeeRuleModel{
+		ID:               rule.ID,
+		Name:             rule.Name,
+		Type:             string(rule.Type),
+		Currency:         rule.Currency,
+		AccountType:      string(rule.AccountType),
+		Flat:             rule.Flat,
+		RateBps:          rule.RateBps,
+		MinFee:           rule.MinFee,
+		MaxFee:           rule.MaxFee,
+		Tiers:            string(data),
+		RevenueAccountID: rule.RevenueAccountID,
+		Priority:         rule.Priority,
+		Active:           rule.Active,
+		CreatedAt:        rule.CreatedAt,
+	}
+	return r.db.Create(&m).Error
+}
+
+func (r *FeeRuleRepo) GetByID(id uuid.UUID) (*domain.FeeRule, error) {
+	var m FeeRuleModel
+	if err := r.db.First(&m, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrFeeRuleNotFound
+		}
+		return nil, err
+	}
+	return toFeeRule(m)
+}
+
+func (r *FeeRuleRepo) List() ([]*domain.FeeRule, error) {
+	return r.find(r.db.Order("active DESC"))
+}
+
+func (r *FeeRuleRepo) ListActive() ([]*domain.FeeRule, error) {
+	return r.find(r.db.Where("active = ?", true))
+}
+
+func (r *FeeRuleRepo) Deactivate(id uuid.UUID) error {
+	result := r.db.Model(&FeeRuleModel{}).Where("id = ?", id).Update("active", false)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrFeeRuleNotFound
+	}
+	return nil
+}
+
+func (r *FeeRuleRepo) find(q *gorm.DB) ([]*domain.FeeRule, error) {
+	var models []FeeRuleModel
+	if err := q.Order("priority").Order("created_at").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	rules := make([]*domain.FeeRule, 0, len(models))
+	for _, m := range models {
+		rule, err := toFeeRule(m)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func toFeeRule(m FeeRuleModel) (*domain.FeeRule, error) {
+	var tiers []feeTier
+	if m.Tiers != "" {
+		if err := json.Unmarshal([]byte(m.Tiers), &tiers); err != nil {
+			return nil, err
+		}
+	}
+
+	rule := &domain.FeeRule{
+		ID:               m.ID,
+		Name:             m.Name,
+		Type:             domain.FeeType(m.Type),
+		Currency:         m.Currency,
+		AccountType:      domain.AccountType(m.AccountType),
+		Flat:             m.Flat,
+		RateBps:          m.RateBps,
+		MinFee:           m.MinFee,
+		MaxFee:           m.MaxFee,
+		RevenueAccountID: m.RevenueAccountID,
+		Priority:         m.Priority,
+		Active:           m.Active,
+		CreatedAt:        m.CreatedAt,
+	}
+	for _, t := range tiers {
+		rule.Tiers = append(rule.Tiers, domain.FeeTier{UpTo: t.UpTo, Flat: t.Flat, RateBps: t.RateBps})
+	}
+	return rule, nil
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FXRateModel struct {
+	Base      string    `gorm:"primaryKey;column:base;type:char(3)"`
+	Quote     string    `gorm:"primaryKey;column:quote;type:char(3)"`
+	Rate      int64     `gorm:"column:rate"`
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+}
+
+func (FXRateModel) TableName() string {
+	return "fx_rates"
+}
+
+type FXRateRepo struct {
+	db *gorm.DB
+}
+
+func NewFXRateRepo(db *gorm.DB) *FXRateRepo {
+	return &FXRateRepo{db}
+}
+
+func (r *FXRateRepo) Get(base, quote string) (*domain.FXRate, error) {
+	var m FXRateModel
+	if err := r.db.First(&m, "base = ? AND quote = ?", base, quote).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrFXRateNotFound
+		}
+		return nil, err
+	}
+	return toFXRate(m), nil
+}
+
+func (r *FXRateRepo) Upsert(rate *domain.FXRate) error {
+	m := FXRateModel{
+		Base:      rate.Base,
+		Quote:     rate.Quote,
+		Rate:      rate.Rate,
+		UpdatedAt: rate.UpdatedAt,
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "base"}, {Name: "quote"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "updated_at"}),
+	}).Create(&m).Error
+}
+
+func (r *FXRateRepo) List() ([]*domain.FXRate, error) {
+	var models []FXRateModel
+	if err := r.db.Order("base, quote").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	rates := make([]*domain.FXRate, 0, len(models))
+	for _, m := range models {
+		rates = append(rates, toFXRate(m))
+	}
+	return rates, nil
+}
+
+func toFXRate(m FXRateModel) *domain.FXRate {
+	return &domain.FXRate{
+		Base:      m.Base,
+		Quote:     m.Quote,
+		Rate:      m.Rate,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
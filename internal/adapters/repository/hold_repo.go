@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"gorm.io/gorm"
+)
+
+type HoldModel struct {
+	ID                   uuid.UUID  `gorm:"primaryKey;column:id;type:uuid"`
+	AccountID            int64      `gorm:"column:account_id;index"`
+	DestinationAccountID int64      `gorm:"column:destination_account_id"`
+	Amount               int64      `gorm:"column:amount"`
+	Currency             string     `gorm:"column:currency;type:char(3)"`
+	CapturedAmount       int64      `gorm:"column:captured_amount"`
+	TransactionID        *uuid.UUID `gorm:"column:transaction_id;type:uuid"`
+	Status               string     `gorm:"column:status;index:idx_holds_status_expires"`
+	ExpiresAt            time.Time  `gorm:"column:expires_at;index:idx_holds_status_expires"`
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+}
+
+func (HoldModel) TableName() string {
+	return "holds"
+}
+
+type HoldRepo struct {
+	db *gorm.DB
+}
+
+func NewHoldRepo(db *gorm.DB) *HoldRepo {
+	return &HoldRepo{db}
+}
+
+func (r *HoldRepo) Create(hold *domain.Hold) error {
+	m := toHoldModel(hold)
+	return r.db.Create(&m).Error
+}
+
+func (r *HoldRepo) GetByID(id uuid.UUID) (*domain.Hold, error) {
+	var m HoldModel
+	if err := r.db.First(&m, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrHoldNotFound
+		}
+		return nil, err
+	}
+	return toHold(m), nil
+}
+
+func (r *HoldRepo) Update(hold *domain.Hold) error {
+	m := toHoldModel(hold)
+	return r.db.Model(&HoldModel{}).
+		Where("id = ?", hold.ID).
+		Updates(map[string]interface{}{
+			"captured_amount": m.CapturedAmount,
+			"transaction_id":  m.TransactionID,
+			"status":          m.Status,
+			"updated_at":      hold.UpdatedAt,
+		}).Error
+}
+
+func (r *HoldRepo) ListExpired(now time.Time, limit int) ([]*domain.Hold, error) {
+	var models []HoldModel
+	err := r.db.
+		Where("status = ? AND expires_at <= ?", string(domain.HoldStatusActive), now).
+		Order("expires_at").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	holds := make([]*domain.Hold, 0, len(models))
+	for _, m := range models {
+		holds = append(holds, toHold(m))
+	}
+	return holds, nil
+}
+
+func (r *HoldRepo) SumActive(accountID int64, since time.Time) (int64, int64, error) {
+	var sums struct {
+		Amount int64
+		Count  int64
+	}
+	err := r.db.Model(&HoldModel{}).
+		Select("COALESCE(SUM(amount), 0) AS amount, COUNT(*) AS count").
+		Where("account_id = ? AND status = ? AND created_at >= ?", accountID, string(domain.HoldStatusActive), since).
+		Scan(&sums).Error
+	if err != nil {
+		return 0, 0, err
+	}
+	return sums.Amount, sums.Count, nil
+}
+
+func toHoldModel(h *domain.Hold) HoldModel {
+	m := HoldModel{
+		ID:                   h.ID,
+		AccountID:            h.AccountID,
+		DestinationAccountID: h.DestinationAccountID,
+		Amount:               h.Amount,
+		Currency:             h.Currency,
+		CapturedAmount:       h.CapturedAmount,
+		Status:               string(h.Status),
+		ExpiresAt:            h.ExpiresAt,
+		CreatedAt:            h.CreatedAt,
+		UpdatedAt:            h.UpdatedAt,
+	}
+	if h.TransactionID != uuid.Nil {
+		m.TransactionID = &h.TransactionID
+	}
+	return m
+}
+
+func toHold(m HoldModel) *domain.Hold {
+	h := &domain.Hold{
+		ID:                   m.ID,
+		AccountID:            m.AccountID,
+		DestinationAccountID: m.DestinationAccountID,
+		Amount:               m.Amount,
+		Currency:             m.Currency,
+		CapturedAmount:       m.CapturedAmount,
+		Status:               domain.HoldStatus(m.Status),
+		ExpiresAt:            m.ExpiresAt,
+		CreatedAt:            m.CreatedAt,
+		UpdatedAt:            m.UpdatedAt,
+	}
+	if m.TransactionID != nil {
+		h.TransactionID = *m.TransactionID
+	}
+	return h
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdempotencyKeyModel struct {
+	Key            string    `gorm:"primaryKey;column:key"`
+	Fingerprint    string    `gorm:"column:fingerprint;not null"`
+	Status         string    `gorm:"column:status;not null"`
+	ResponseStatus int       `gorm:"column:response_status"`
+	ResponseBody   []byte    `gorm:"column:response_body;type:bytea"`
+	CreatedAt      time.Time `gorm:"column:created_at"`
+	LockedUntil    time.Time `gorm:"column:locked_until"`
+	ExpiresAt      time.Time `gorm:"column:expires_at;index"`
+}
+
+func (IdempotencyKeyModel) TableName() string {
+	return "idempotency_keys"
+}
+
+type IdempotencyRepo struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyRepo(db *gorm.DB) *IdempotencyRepo {
+	return &IdempotencyRepo{db}
+}
+
+func (r *IdempotencyRepo) Acquire(key *domain.IdempotencyKey) (bool, error) {
+	m := IdempotencyKeyModel{
+		Key:         key.Key,
+		Fingerprint: key.Fingerprint,
+		Status:      string(key.Status),
+		CreatedAt:   key.CreatedAt,
+		LockedUntil: key.LockedUntil,
+		ExpiresAt:   key.ExpiresAt,
+	}
+	// the insert and the takeover of an expired key or a lapsed claim are one statement,
+	// so two replicas racing on the same key can't both win
+	result := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "key"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"fingerprint":     m.Fingerprint,
+			"status":          m.Status,
+			"response_status": 0,
+			"response_body":   nil,
+			"created_at":      m.CreatedAt,
+			"locked_until":    m.LockedUntil,
+			"expires_at":      m.ExpiresAt,
+		}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{
+				SQL: "idempotency_keys.expires_at <= ? OR (idempotency_keys.status = ? AND " +
+					"(idempotency_keys.locked_until IS NULL OR idempotency_keys.locked_until <= ?))",
+				Vars: []interface{}{m.CreatedAt, string(domain.IdempotencyInProgress), m.CreatedAt},
+			},
+		}},
+	}).Create(&m)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *IdempotencyRepo) Get(key string) (*domain.IdempotencyKey, error) {
+	var m IdempotencyKeyModel
+	if err := r.db.First(&m, "key = ?", key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrIdempotencyNotFound
+		}
+		return nil, err
+	}
+	return &domain.IdempotencyKey{
+		Key:            m.Key,
+		Fingerprint:    m.Fingerprint,
+		Status:         domain.IdempotencyStatus(m.Status),
+		ResponseStatus: m.ResponseStatus,
+		ResponseBody:   m.ResponseBody,
+		CreatedAt:      m.CreatedAt,
+		LockedUntil:    m.LockedUntil,
+		ExpiresAt:      m.ExpiresAt,
+	}, nil
+}
+
+func (r *IdempotencyRepo) Complete(key string, status int, body []byte) error {
+	return r.db.Model(&IdempotencyKeyModel{}).
+		Where("key = ? AND status = ?", key, string(domain.IdempotencyInProgress)).
+		Updates(map[string]interface{}{
+			"status":          string(domain.IdempotencyCompleted),
+			"response_status": status,
+			"response_body":   body,
+		}).Error
+}
+
+func (r *IdempotencyRepo) Release(key string) error {
+	return r.db.
+		Where("key = ? AND status = ?", key, string(domain.IdempotencyInProgress)).
+		Delete(&IdempotencyKeyModel{}).Error
+}
+
+func (r *IdempotencyRepo) DeleteExpired(now time.Time) (int64, error) {
+	result := r.db.Where("expires_at <= ?", now).Delete(&IdempotencyKeyModel{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// InterestAccrualModel has one row per account and day; the primary key is what makes
+// the accrual job safe to rerun
+type InterestAccrualModel struct {
+	AccountID   int64     `gorm:"primaryKey;column:account_id;autoIncrement:false"`
+	AccrualDate time.Time `gorm:"primaryKey;column:accrual_date;type:date"`
+	Balance     int64     `gorm:"column:balance"`
+	RateBps     int64     `gorm:"column:rate_bps"`
+	Accrued     int64     `gorm:"column:accrued"`
+	CreatedAt   time.Time `gorm:"column:created_at"`
+}
+
+func (InterestAccrualModel) TableName() string {
+	return "interest_accruals"
+}
+
+// InterestPostingModel has one row per account and month, written in the same db
+// transaction as the interest payment
+type InterestPostingModel struct {
+	AccountID     int64     `gorm:"primaryKey;column:account_id;autoIncrement:false"`
+	Period        string    `gorm:"primaryKey;column:period;type:char(7)"`
+	Amount        int64     `gorm:"column:amount"`
+	TransactionID uuid.UUID `gorm:"column:transaction_id;type:uuid"`
+	CreatedAt     time.Time `gorm:"column:created_at"`
+}
+
+func (InterestPostingModel) TableName() string {
+	return "interest_postings"
+}
+
+type InterestRepo struct {
+	db *gorm.DB
+}
+
+func NewInterestRepo(db *gorm.DB) *InterestRepo {
+	return &InterestRepo{db}
+}
+
+func (r *InterestRepo) LastAccrualDate(accountID int64) (time.Time, error) {
+	var m InterestAccrualModel
+	result := r.db.Where("account_id = ?", accountID).Order("accrual_date DESC").Limit(1).Find(&m)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return time.Time{}, result.Error
+	}
+	return m.AccrualDate.UTC(), nil
+}
+
+func (r *InterestRepo) CreateAccrual(accrual *domain.InterestAccrual) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&InterestAccrualModel{
+		AccountID:   accrual.AccountID,
+		AccrualDate: accrual.Date,
+		Balance:     accrual.Balance,
+		RateBps:     accrual.RateBps,
+		Accrued:     accrual.Accrued,
+		CreatedAt:   accrual.CreatedAt,
+	})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *InterestRepo) SumAccrued(accountID int64, before time.Time) (int64, error) {
+	var sum int64
+	err := r.db.Model(&InterestAccrualModel{}).
+		Select("COALESCE(SUM(accrued), 0)").
+		Where("account_id = ? AND accrual_date < ?", accountID, before).
+		Scan(&sum).Error
+	return sum, err
+}
+
+func (r *InterestRepo) HasPosting(accountID int64, period string) (bool, error) {
+	var count int64
+	err := r.db.Model(&InterestPostingModel{}).
+		Where("account_id = ? AND period = ?", accountID, period).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *InterestRepo) CreatePosting(posting *domain.InterestPosting) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&InterestPostingModel{
+		AccountID:     posting.AccountID,
+		Period:        posting.Period,
+		Amount:        posting.Amount,
+		TransactionID: posting.TransactionID,
+		CreatedAt:     posting.CreatedAt,
+	})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *InterestRepo) SumPosted(accountID int64) (int64, error) {
+	var sum int64
+	err := r.db.Model(&InterestPostingModel{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("account_id = ?", accountID).
+		Scan(&sum).Error
+	return sum, err
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"gorm.io/gorm"
+)
+
+type LedgerEntryModel struct {
+	ID            uuid.UUID `gorm:"primaryKey;column:id;type:uuid"`
+	TransactionID uuid.UUID `gorm:"column:transaction_id;type:uuid;index"`
+	AccountID     int64     `gorm:"column:account_id;index:idx_ledger_account_created"`
+	Direction     string    `gorm:"column:direction"`
+	Amount        int64     `gorm:"column:amount"`
+	Currency      string    `gorm:"column:currency;type:char(3);not null;default:'INR'"`
+	CreatedAt     time.Time `gorm:"column:created_at;index:idx_ledger_account_created"`
+}
+
+func (LedgerEntryModel) TableName() string {
+	return "ledger_entries"
+}
+
+type LedgerRepo struct {
+	db *gorm.DB
+}
+
+func NewLedgerRepo(db *gorm.DB) *LedgerRepo {
+	return &LedgerRepo{db}
+}
+
+func (r *LedgerRepo) Create(entries []*domain.LedgerEntry) error {
+	models := make([]LedgerEntryModel, 0, len(entries))
+	for _, e := range entries {
+		models = append(models, LedgerEntryModel{
+			ID:            e.ID,
+			TransactionID: e.TransactionID,
+			AccountID:     e.AccountID,
+			Direction:     string(e.Direction),
+			Amount:        e.Amount,
+			Currency:      e.Currency,
+			CreatedAt:     e.CreatedAt,
+		})
+	}
+	return r.db.Create(&models).Error
+}
+
+func (r *LedgerRepo) NetBefore(accountID int64, before time.Time) (int64, error) {
+	return r.net(r.db.Where("account_id = ? AND created_at < ?", accountID, before))
+}
+
+func (r *LedgerRepo) NetBetween(accountID int64, from, to time.Time) (int64, error) {
+	return r.net(r.db.Where("account_id = ? AND created_at >= ? AND created_at < ?", accountID, from, to))
+}
+
+func (r *LedgerRepo) net(q *gorm.DB) (int64, error) {
+	var net int64
+	err := q.Model(&LedgerEntryModel{}).
+		Select("COALESCE(SUM(CASE WHEN direction = ? THEN amount ELSE -amount END), 0)", string(domain.EntryCredit)).
+		Scan(&net).Error
+	return net, err
+}
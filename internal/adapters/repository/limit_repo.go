@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TransferLimitModel holds both kinds of limit: account limits have an account_id, an
+// empty account_type and the account's currency, tier limits have account_id 0
+type TransferLimitModel struct {
+	AccountID         int64     `gorm:"primaryKey;column:account_id;autoIncrement:false"`
+	AccountType       string    `gorm:"primaryKey;column:account_type"`
+	Currency          string    `gorm:"primaryKey;column:currency"`
+	MaxPerTransaction int64     `gorm:"column:max_per_transaction"`
+	DailyOutgoing     int64     `gorm:"column:daily_outgoing"`
+	MonthlyOutgoing   int64     `gorm:"column:monthly_outgoing"`
+	HourlyCount       int64     `gorm:"column:hourly_count"`
+	UpdatedAt         time.Time `gorm:"column:updated_at"`
+}
+
+func (TransferLimitModel) TableName() string {
+	return "transfer_limits"
+}
+
+type LimitRepo struct {
+	db *gorm.DB
+}
+
+func NewLimitRepo(db *gorm.DB) *LimitRepo {
+	return &LimitRepo{db}
+}
+
+func (r *LimitRepo) GetForAccount(accountID int64) (*domain.TransferLimit, error) {
+	return r.get(r.db.Where("account_id = ? AND account_type = ''", accountID))
+}
+
+func (r *LimitRepo) GetForTier(accountType domain.AccountType, currency string) (*domain.TransferLimit, error) {
+	return r.get(r.db.Where("account_id = 0 AND account_type = ? AND currency = ?", string(accountType), currency))
+}
+
+func (r *LimitRepo) get(q *gorm.DB) (*domain.TransferLimit, error) {
+	var m TransferLimitModel
+	if err := q.First(&m).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrLimitNotFound
+		}
+		return nil, err
+	}
+	return toTransferLimit(m), nil
+}
+
+func (r *LimitRepo) Upsert(limit *domain.TransferLimit) error {
+	m := TransferLimitModel{
+		AccountID:         limit.AccountID,
+		AccountType:       string(limit.AccountType),
+		Currency:          limit.Currency,
+		MaxPerTransaction: limit.MaxPerTransaction,
+		DailyOutgoing:     limit.DailyOutgoing,
+		MonthlyOutgoing:   limit.MonthlyOutgoing,
+		HourlyCount:       limit.HourlyCount,
+		UpdatedAt:         limit.UpdatedAt,
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "account_id"}, {Name: "account_type"}, {Name: "currency"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"max_per_transaction", "daily_outgoing", "monthly_outgoing", "hourly_count", "updated_at",
+		}),
+	}).Create(&m).Error
+}
+
+func (r *LimitRepo) Delete(limit *domain.TransferLimit) error {
+	q := r.db.Where("account_id = 0 AND account_type = ? AND currency = ?", string(limit.AccountType), limit.Currency)
+	if limit.AccountID != 0 {
+		q = r.db.Where("account_id = ? AND account_type = ''", limit.AccountID)
+	}
+	result := q.Delete(&TransferLimitModel{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrLimitNotFound
+	}
+	return nil
+}
+
+func (r *LimitRepo) List() ([]*domain.TransferLimit, error) {
+	var models []TransferLimitModel
+	if err := r.db.Order("account_id, account_type, currency").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	limits := make([]*domain.TransferLimit, 0, len(models))
+	for _, m := range models {
+		limits = append(limits, toTransferLimit(m))
+	}
+	return limits, nil
+}
+
+func toTransferLimit(m TransferLimitModel) *domain.TransferLimit {
+	return &domain.TransferLimit{
+		AccountID:         m.AccountID,
+		AccountType:       domain.AccountType(m.AccountType),
+		Currency:          m.Currency,
+		MaxPerTransaction: m.MaxPerTransaction,
+		DailyOutgoing:     m.DailyOutgoing,
+		MonthlyOutgoing:   m.MonthlyOutgoing,
+		HourlyCount:       m.HourlyCount,
+		UpdatedAt:         m.UpdatedAt,
+	}
+}
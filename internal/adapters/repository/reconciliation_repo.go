@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"gorm.io/gorm"
+)
+
+type ReconciliationRunModel struct {
+	ID              uuid.UUID `gorm:"primaryKey;column:id;type:uuid"`
+	Status          string    `gorm:"column:status;not null"`
+	AccountsChecked int64     `gorm:"column:accounts_checked"`
+	Discrepancies   string    `gorm:"column:discrepancies;type:jsonb;not null;default:'[]'"`
+	StartedAt       time.Time `gorm:"column:started_at;index"`
+	FinishedAt      time.Time `gorm:"column:finished_at"`
+}
+
+func (ReconciliationRunModel) TableName() string {
+	return "reconciliation_runs"
+}
+
+// discrepancy is the stored form of a domain.Discrepancy
+type discrepancy struct {
+	Check     string `json:"check"`
+	AccountID int64  `json:"account_id,omitempty"`
+	Currency  string `json:"currency"`
+	Expected  int64  `json:"expected"`
+	Actual    int64  `json:"actual"`
+}
+
+type ReconciliationRepo struct {
+	db *gorm.DB
+}
+
+func NewReconciliationRepo(db *gorm.DB) *ReconciliationRepo {
+	return &ReconciliationRepo{db}
+}
+
+func (r *ReconciliationRepo) CountAccounts() (int64, error) {
+	var n int64
+	err := r.db.Model(&AccountModel{}).Count(&n).Error
+	return n, err
+}
+
+func (r *ReconciliationRepo) FundMismatches() ([]domain.Discrepancy, error) {
+	var rows []discrepancy
+	err := r.db.Raw(`
+		SELECT a.currency, a.funded AS expected, a.balance + COALESCE(fx.net, 0) AS actual
+		FROM (
+			SELECT currency, SUM(opening_balance) AS funded, SUM(balance) AS balance
+			FROM accounts GROUP BY currency
+		) a
+		LEFT JOIN (
+			SELECT currency, SUM(CASE WHEN direction = ? THEN amount ELSE -amount END) AS net
+			FROM ledger_entries WHERE account_id = ? GROUP BY currency
+		) fx ON fx.currency = a.currency
+		WHERE a.funded <> a.balance + COALESCE(fx.net, 0)
+		ORDER BY a.currency`,
+		string(domain.EntryCredit), domain.FXPositionAccountID,
+	).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return toDiscrepancies(domain.CheckTotalFunds, rows), nil
+}
+
+func (r *ReconciliationRepo) FlowMismatches(limit int) ([]domain.Discrepancy, error) {
+	var rows []discrepancy
+	// split parents only record the fan-out, their legs carry the money. Rows written
+	// before cross-currency support have no destination side and credit the source amount.
+	err := r.db.Raw(`
+		WITH flows AS (
+			SELECT source_account_id AS account_id, -(amount + fee) AS net
+			FROM transactions WHERE kind <> ?
+			UNION ALL
+			SELECT destination_account_id,
+				CASE WHEN COALESCE(destination_currency, '') = '' THEN amount ELSE destination_amount END
+			FROM transactions WHERE kind <> ?
+			UNION ALL
+			SELECT fee_account_id, fee
+			FROM transactions WHERE kind <> ? AND fee_account_id IS NOT NULL
+		)
+		SELECT a.account_id, a.currency, a.opening_balance + COALESCE(f.net, 0) AS expected, a.balance AS actual
+		FROM accounts a
+		LEFT JOIN (SELECT account_id, SUM(net) AS net FROM flows GROUP BY account_id) f ON f.account_id = a.account_id
+		WHERE a.balance <> a.opening_balance + COALESCE(f.net, 0)
+		ORDER BY a.account_id
+		LIMIT ?`,
+		string(domain.TxKindSplit), string(domain.TxKindSplit), string(domain.TxKindSplit), limit,
+	).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return toDiscrepancies(domain.CheckAccountFlows, rows), nil
+}
+
+func (r *ReconciliationRepo) NegativeBalances(allowed []domain.AccountType, limit int) ([]domain.Discrepancy, error) {
+	q := r.db.Model(&AccountModel{}).
+		Select("account_id, currency, -overdraft_limit AS expected, balance AS actual").
+		Where("balance < -overdraft_limit")
+	if len(allowed) > 0 {
+		types := make([]string, 0, len(allowed))
+		for _, t := range allowed {
+			types = append(types, string(t))
+		}
+		q = q.Where("type NOT IN ?", types)
+	}
+
+	var rows []discrepancy
+	if err := q.Order("account_id").Limit(limit).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return toDiscrepancies(domain.CheckNegativeBalance, rows), nil
+}
+
+func (r *ReconciliationRepo) CreateRun(run *domain.ReconciliationRun) error {
+	rows := make([]discrepancy, 0, len(run.Discrepancies))
+	for _, d := range run.Discrepancies {
+		rows = append(rows, discrepancy{
+			Check:     string(d.Check),
+			AccountID: d.AccountID,
+			Currency:  d.Currency,
+			Expected:  d.Expected,
+			Actual:    d.Actual,
+		})
+	}
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return err
+	}
+
+	return r.db.Create(&ReconciliationRunModel{
+		ID:              run.ID,
+		Status:          string(run.Status),
+		AccountsChecked: run.AccountsChecked,
+		Discrepancies:   string(data),
+		StartedAt:       run.StartedAt,
+		FinishedAt:      run.FinishedAt,
+	}).Error
+}
+
+func (r *ReconciliationRepo) ListRuns(limit int) ([]*domain.ReconciliationRun, error) {
+	var models []ReconciliationRunModel
+	if err := r.db.Order("started_at DESC").Limit(limit).Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	runs := make([]*domain.ReconciliationRun, 0, len(models))
+	for _, m := range models {
+		var rows []discrepancy
+		if err := json.Unmarshal([]byte(m.Discrepancies), &rows); err != nil {
+			return nil, err
+		}
+		runs = append(runs, &domain.ReconciliationRun{
+			ID:              m.ID,
+			Status:          domain.ReconciliationStatus(m.Status),
+			AccountsChecked: m.AccountsChecked,
+			Discrepancies:   toDiscrepancies("", rows),
+			StartedAt:       m.StartedAt,
+			FinishedAt:      m.FinishedAt,
+		})
+	}
+	return runs, nil
+}
+
+// toDiscrepancies converts rows to domain discrepancies, setting their check unless it is empty
+func toDiscrepancies(check domain.ReconciliationCheck, rows []discrepancy) []domain.Discrepancy {
+	out := make([]domain.Discrepancy, 0, len(rows))
+	for _, row := range rows {
+		d := domain.Discrepancy{
+			Check:     domain.ReconciliationCheck(row.Check),
+			AccountID: row.AccountID,
+			Currency:  row.Currency,
+			Expected:  row.Expected,
+			Actual:    row.Actual,
+		}
+		if check != "" {
+			d.Check = check
+		}
+		out = append(out, d)
+	}
+	return out
+}
@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"gorm.io/gorm"
+)
+
+type StandingOrderModel struct {
+	ID                  uuid.UUID  `gorm:"primaryKey;column:id;type:uuid"`
+	FromAccount         int64      `gorm:"column:from_account;index"`
+	ToAccount           int64      `gorm:"column:to_account"`
+	Amount              int64      `gorm:"column:amount"`
+	Currency            string     `gorm:"column:currency;type:char(3)"`
+	Frequency           string     `gorm:"column:frequency"`
+	CronExpr            string     `gorm:"column:cron_expr"`
+	StartAt             time.Time  `gorm:"column:start_at"`
+	EndAt               *time.Time `gorm:"column:end_at"`
+	MaxRuns             int        `gorm:"column:max_runs"`
+	RunCount            int        `gorm:"column:run_count"`
+	ScheduledFor        time.Time  `gorm:"column:scheduled_for"`
+	NextRunAt           time.Time  `gorm:"column:next_run_at;index:idx_standing_orders_due"`
+	OnInsufficientFunds string     `gorm:"column:on_insufficient_funds"`
+	Status              string     `gorm:"column:status;index:idx_standing_orders_due"`
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+func (StandingOrderModel) TableName() string {
+	return "standing_orders"
+}
+
+type StandingOrderRunModel struct {
+	ID            uuid.UUID  `gorm:"primaryKey;column:id;type:uuid"`
+	OrderID       uuid.UUID  `gorm:"column:order_id;type:uuid;index"`
+	ScheduledFor  time.Time  `gorm:"column:scheduled_for"`
+	Status        string     `gorm:"column:status"`
+	TransactionID *uuid.UUID `gorm:"column:transaction_id;type:uuid"`
+	Error         string     `gorm:"column:error"`
+	CreatedAt     time.Time
+}
+
+func (StandingOrderRunModel) TableName() string {
+	return "standing_order_runs"
+}
+
+type StandingOrderRepo struct {
+	db *gorm.DB
+}
+
+func NewStandingOrderRepo(db *gorm.DB) *StandingOrderRepo {
+	return &StandingOrderRepo{db}
+}
+
+func (r *StandingOrderRepo) Create(order *domain.StandingOrder) error {
+	m := toStandingOrderModel(order)
+	return r.db.Create(&m).Error
+}
+
+func (r *StandingOrderRepo) GetByID(id uuid.UUID) (*domain.StandingOrder, error) {
+	var m StandingOrderModel
+	if err := r.db.First(&m, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrStandingOrderNotFound
+		}
+		return nil, err
+	}
+	return toStandingOrder(m), nil
+}
+
+func (r *StandingOrderRepo) Update(order *domain.StandingOrder) error {
+	m := toStandingOrderModel(order)
+	return r.db.Model(&StandingOrderModel{}).
+		Where("id = ?", order.ID).
+		Updates(map[string]interface{}{
+			"amount":                m.Amount,
+			"end_at":                m.EndAt,
+			"max_runs":              m.MaxRuns,
+			"run_count":             m.RunCount,
+			"scheduled_for":         m.ScheduledFor,
+			"next_run_at":           m.NextRunAt,
+			"on_insufficient_funds": m.OnInsufficientFunds,
+			"status":                m.Status,
+			"updated_at":            m.UpdatedAt,
+		}).Error
+}
+
+func (r *StandingOrderRepo) ListByAccount(accountID int64) ([]*domain.StandingOrder, error) {
+	var models []StandingOrderModel
+	if err := r.db.Where("from_account = ?", accountID).Order("created_at").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return toStandingOrders(models), nil
+}
+
+func (r *StandingOrderRepo) ListDue(now time.Time, limit int) ([]*domain.StandingOrder, error) {
+	var models []StandingOrderModel
+	err := r.db.
+		Where("status = ? AND next_run_at <= ?", string(domain.StandingOrderActive), now).
+		Order("next_run_at").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	return toStandingOrders(models), nil
+}
+
+func (r *StandingOrderRepo) CreateRun(run *domain.StandingOrderRun) error {
+	m := StandingOrderRunModel{
+		ID:           run.ID,
+		OrderID:      run.OrderID,
+		ScheduledFor: run.ScheduledFor,
+		Status:       string(run.Status),
+		Error:        run.Error,
+		CreatedAt:    run.CreatedAt,
+	}
+	if run.TransactionID != uuid.Nil {
+		m.TransactionID = &run.TransactionID
+	}
+	return r.db.Create(&m).Error
+}
+
+func (r *StandingOrderRepo) ListRuns(orderID uuid.UUID) ([]*domain.StandingOrderRun, error) {
+	var models []StandingOrderRunModel
+	if err := r.db.Where("order_id = ?", orderID).Order("created_at").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	runs := make([]*domain.StandingOrderRun, 0, len(models))
+	for _, m := range models {
+		run := &domain.StandingOrderRun{
+			ID:           m.ID,
+			OrderID:      m.OrderID,
+			ScheduledFor: m.ScheduledFor,
+			Status:       domain.StandingOrderRunStatus(m.Status),
+			Error:        m.Error,
+			CreatedAt:    m.CreatedAt,
+		}
+		if m.TransactionID != nil {
+			run.TransactionID = *m.TransactionID
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+func toStandingOrderModel(o *domain.StandingOrder) StandingOrderModel {
+	m := StandingOrderModel{
+		ID:                  o.ID,
+		FromAccount:         o.FromAccount,
+		ToAccount:           o.ToAccount,
+		Amount:              o.Amount,
+		Currency:            o.Currency,
+		Frequency:           string(o.Frequency),
+		CronExpr:            o.CronExpr,
+		StartAt:             o.StartAt,
+		MaxRuns:             o.MaxRuns,
+		RunCount:            o.RunCount,
+		ScheduledFor:        o.ScheduledFor,
+		NextRunAt:           o.NextRunAt,
+		OnInsufficientFunds: string(o.OnInsufficientFunds),
+		Status:              string(o.Status),
+		CreatedAt:           o.CreatedAt,
+		UpdatedAt:           o.UpdatedAt,
+	}
+	if !o.EndAt.IsZero() {
+		m.EndAt = &o.EndAt
+	}
+	return m
+}
+
+func toStandingOrder(m StandingOrderModel) *domain.StandingOrder {
+	o := &domain.StandingOrder{
+		ID:                  m.ID,
+		FromAccount:         m.FromAccount,
+		ToAccount:           m.ToAccount,
+		Amount:              m.Amount,
+		Currency:            m.Currency,
+		Frequency:           domain.Frequency(m.Frequency),
+		CronExpr:            m.CronExpr,
+		StartAt:             m.StartAt,
+		MaxRuns:             m.MaxRuns,
+		RunCount:            m.RunCount,
+		ScheduledFor:        m.ScheduledFor,
+		NextRunAt:           m.NextRunAt,
+		OnInsufficientFunds: domain.InsufficientFundsPolicy(m.OnInsufficientFunds),
+		Status:              domain.StandingOrderStatus(m.Status),
+		CreatedAt:           m.CreatedAt,
+		UpdatedAt:           m.UpdatedAt,
+	}
+	if m.EndAt != nil {
+		o.EndAt = *m.EndAt
+	}
+	return o
+}
+
+func toStandingOrders(models []StandingOrderModel) []*domain.StandingOrder {
+	orders := make([]*domain.StandingOrder, 0, len(models))
+	for _, m := range models {
+		orders = append(orders, toStandingOrder(m))
+	}
+	return orders
+}
@@ -43,7 +43,7 @@ func (r *AccountRepo) WithTx(tx ports.Transaction) ports.AccountRepository {
 	if !ok {
 		panic("WithTx: expected *gorm.DB")
 	}
-	return &AccountRepo{db: gormTx}
+	return &AccountRepo{db: gormTx, forUpdate: true}
 }
 
 func (r *TransactionRepo) WithTx(tx ports.Transaction) ports.TransactionRepository {
@@ -53,3 +53,43 @@ func (r *TransactionRepo) WithTx(tx ports.Transaction) ports.TransactionReposito
 	}
 	return &TransactionRepo{db: gormTx}
 }
+
+func (r *LedgerRepo) WithTx(tx ports.Transaction) ports.LedgerRepository {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		panic("WithTx: expected *gorm.DB")
+	}
+	return &LedgerRepo{db: gormTx}
+}
+
+func (r *HoldRepo) WithTx(tx ports.Transaction) ports.HoldRepository {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		panic("WithTx: expected *gorm.DB")
+	}
+	return &HoldRepo{db: gormTx}
+}
+
+func (r *StandingOrderRepo) WithTx(tx ports.Transaction) ports.StandingOrderRepository {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		panic("WithTx: expected *gorm.DB")
+	}
+	return &StandingOrderRepo{db: gormTx}
+}
+
+func (r *InterestRepo) WithTx(tx ports.Transaction) ports.InterestRepository {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		panic("WithTx: expected *gorm.DB")
+	}
+	return &InterestRepo{db: gormTx}
+}
+
+func (r *EscrowRepo) WithTx(tx ports.Transaction) ports.EscrowRepository {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		panic("WithTx: expected *gorm.DB")
+	}
+	return &EscrowRepo{db: gormTx}
+}
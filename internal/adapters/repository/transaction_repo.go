@@ -1,6 +1,9 @@
 package repository
 
 import (
+	"encoding/json"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,11 +12,25 @@ import (
 )
 
 type TransactionModel struct {
-	ID                   uuid.UUID `gorm:"primaryKey;column:id;type:uuid"`
-	SourceAccountID      int64     `gorm:"column:source_account_id;index"`
-	DestinationAccountID int64     `gorm:"column:destination_account_id;index"`
-	Amount               int64     `gorm:"column:amount"`
-	CreatedAt            time.Time `gorm:"column:created_at"`
+	ID                   uuid.UUID  `gorm:"primaryKey;column:id;type:uuid;index:idx_transactions_created_id,priority:2"`
+	Kind                 string     `gorm:"column:kind;not null;default:'transfer'"`
+	ReversalOf           *uuid.UUID `gorm:"column:reversal_of;type:uuid;index"`
+	ParentID             *uuid.UUID `gorm:"column:parent_id;type:uuid;index"`
+	SourceAccountID      int64      `gorm:"column:source_account_id;index;index:idx_transactions_source_created,priority:1;uniqueIndex:idx_transactions_source_external_ref,priority:1,where:external_ref <> ''"`
+	DestinationAccountID int64      `gorm:"column:destination_account_id;index;index:idx_transactions_destination_created,priority:1"`
+	Amount               int64      `gorm:"column:amount"`
+	Currency             string     `gorm:"column:currency;type:char(3);not null;default:'INR'"`
+	DestinationAmount    int64      `gorm:"column:destination_amount"`
+	DestinationCurrency  string     `gorm:"column:destination_currency;type:char(3)"`
+	FXRate               int64      `gorm:"column:fx_rate"`
+	FXRateAt             *time.Time `gorm:"column:fx_rate_at"`
+	Fee                  int64      `gorm:"column:fee;not null;default:0"`
+	FeeAccountID         *int64     `gorm:"column:fee_account_id;index"`
+	Description          string     `gorm:"column:description;not null;default:''"`
+	// ExternalRef is unique per source account; an empty one is not a reference
+	ExternalRef string    `gorm:"column:external_ref;not null;default:'';uniqueIndex:idx_transactions_source_external_ref,priority:2,where:external_ref <> ''"`
+	Metadata    string    `gorm:"column:metadata;type:jsonb;not null;default:'{}'"`
+	CreatedAt   time.Time `gorm:"column:created_at;index:idx_transactions_source_created,priority:2;index:idx_transactions_destination_created,priority:2;index:idx_transactions_created_id,priority:1"`
 }
 
 func (TransactionModel) TableName() string {
@@ -29,12 +46,290 @@ func NewTransactionRepo(db *gorm.DB) *TransactionRepo {
 }
 
 func (r *TransactionRepo) Create(tx *domain.Transaction) error {
+	metadata, err := marshalMetadata(tx.Metadata)
+	if err != nil {
+		return err
+	}
 	m := TransactionModel{
 		ID:                   tx.ID,
+		Kind:                 string(tx.Kind),
 		SourceAccountID:      tx.SourceAccountID,
 		DestinationAccountID: tx.DestinationAccountID,
 		Amount:               tx.Amount,
+		Currency:             tx.Currency,
+		DestinationAmount:    tx.DestinationAmount,
+		DestinationCurrency:  tx.DestinationCurrency,
+		FXRate:               tx.FXRate,
+		Fee:                  tx.Fee,
+		Description:          tx.Description,
+		ExternalRef:          tx.ExternalRef,
+		Metadata:             metadata,
 		CreatedAt:            tx.CreatedAt,
 	}
-	return r.db.Create(&m).Error
+	if tx.Fee > 0 {
+		m.FeeAccountID = &tx.FeeAccountID
+	}
+	if m.Kind == "" {
+		m.Kind = string(domain.TxKindTransfer)
+	}
+	if tx.ReversalOf != uuid.Nil {
+		m.ReversalOf = &tx.ReversalOf
+	}
+	if tx.ParentID != uuid.Nil {
+		m.ParentID = &tx.ParentID
+	}
+	if !tx.FXRateAt.IsZero() {
+		m.FXRateAt = &tx.FXRateAt
+	}
+	if err := r.db.Create(&m).Error; err != nil {
+		if tx.ExternalRef != "" && isUniqueViolation(err) {
+			return domain.ErrDuplicateRef
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *TransactionRepo) GetByID(id uuid.UUID) (*domain.Transaction, error) {
+	var m TransactionModel
+	if err := r.db.First(&m, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrTransactionNotFound
+		}
+		return nil, err
+	}
+	return toTransaction(m), nil
+}
+
+func (r *TransactionRepo) GetByExternalRef(sourceAccountID int64, externalRef string) (*domain.Transaction, error) {
+	var m TransactionModel
+	if err := r.db.First(&m, "source_account_id = ? AND external_ref = ?", sourceAccountID, externalRef).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrTransactionNotFound
+		}
+		return nil, err
+	}
+	return toTransaction(m), nil
+}
+
+func (r *TransactionRepo) SumReversals(originalID uuid.UUID) (int64, int64, error) {
+	var sums struct {
+		Amount            int64
+		DestinationAmount int64
+	}
+	err := r.db.Model(&TransactionModel{}).
+		Select("COALESCE(SUM(amount), 0) AS amount, COALESCE(SUM(destination_amount), 0) AS destination_amount").
+		Where("reversal_of = ?", originalID).
+		Scan(&sums).Error
+	if err != nil {
+		return 0, 0, err
+	}
+	return sums.Amount, sums.DestinationAmount, nil
+}
+
+func (r *TransactionRepo) ListReversals(originalID uuid.UUID) ([]*domain.Transaction, error) {
+	var models []TransactionModel
+	if err := r.db.Where("reversal_of = ?", originalID).Order("created_at").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	txns := make([]*domain.Transaction, 0, len(models))
+	for _, m := range models {
+		txns = append(txns, toTransaction(m))
+	}
+	return txns, nil
+}
+
+func (r *TransactionRepo) SumOutgoing(accountID int64, since time.Time) (int64, int64, error) {
+	var sums struct {
+		Amount int64
+		Count  int64
+	}
+	err := r.db.Model(&TransactionModel{}).
+		Select("COALESCE(SUM(amount), 0) AS amount, COUNT(*) AS count").
+		Where("source_account_id = ? AND kind IN ? AND created_at >= ?", accountID,
+			[]string{string(domain.TxKindTransfer), string(domain.TxKindWithdrawal)}, since).
+		Scan(&sums).Error
+	if err != nil {
+		return 0, 0, err
+	}
+	return sums.Amount, sums.Count, nil
+}
+
+func (r *TransactionRepo) StreamForAccount(accountID int64, from, to time.Time, fn func(*domain.Transaction) error) error {
+	rows, err := r.db.Model(&TransactionModel{}).
+		Where("kind <> ? AND created_at >= ? AND created_at < ?", string(domain.TxKindSplit), from, to).
+		Where("source_account_id = ? OR destination_account_id = ? OR fee_account_id = ?", accountID, accountID, accountID).
+		Order("created_at, id").
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var m TransactionModel
+		if err := r.db.ScanRows(rows, &m); err != nil {
+			return err
+		}
+		if err := fn(toTransaction(m)); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *TransactionRepo) List(filter domain.TransactionFilter, after *domain.TxCursor, limit int) ([]*domain.Transaction, error) {
+	q := r.db.Model(&TransactionModel{})
+	if filter.AccountID != 0 {
+		q = q.Where("kind <> ?", string(domain.TxKindSplit)).Where(r.accountSides(filter))
+	} else {
+		if filter.Counterparty != 0 {
+			q = q.Where("source_account_id = ? OR destination_account_id = ?", filter.Counterparty, filter.Counterparty)
+		}
+		if filter.MinAmount > 0 {
+			q = q.Where("amount >= ?", filter.MinAmount)
+		}
+		if filter.MaxAmount > 0 {
+			q = q.Where("amount <= ?", filter.MaxAmount)
+		}
+	}
+	if filter.Kind != "" {
+		q = q.Where("kind = ?", string(filter.Kind))
+	}
+	if filter.Currency != "" {
+		q = q.Where("currency = ?", filter.Currency)
+	}
+	if filter.ExternalRef != "" {
+		q = q.Where("external_ref = ?", filter.ExternalRef)
+	}
+	if filter.Description != "" {
+		q = q.Where("description ILIKE ?", "%"+likeEscaper.Replace(filter.Description)+"%")
+	}
+	if len(filter.Metadata) > 0 {
+		metadata, err := json.Marshal(filter.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		q = q.Where("metadata @> ?::jsonb", string(metadata))
+	}
+	if !filter.From.IsZero() {
+		q = q.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		q = q.Where("created_at < ?", filter.To)
+	}
+	if after != nil {
+		q = q.Where("(created_at, id) < (?, ?)", after.CreatedAt, after.ID)
+	}
+
+	var models []TransactionModel
+	if err := q.Order("created_at DESC, id DESC").Limit(limit).Find(&models).Error; err != nil {
+		return nil, err
+	}
+	txns := make([]*domain.Transaction, 0, len(models))
+	for _, m := range models {
+		txns = append(txns, toTransaction(m))
+	}
+	return txns, nil
+}
+
+// accountSides matches the transactions that debited or credited filter.AccountID: as the
+// source, as the destination, or as the fee account, each with its own amount column
+func (r *TransactionRepo) accountSides(filter domain.TransactionFilter) *gorm.DB {
+	type side struct{ account, amount, other string }
+	var sides []side
+	if filter.Direction != domain.EntryCredit {
+		sides = append(sides, side{"source_account_id", "amount", "destination_account_id"})
+	}
+	if filter.Direction != domain.EntryDebit {
+		sides = append(sides,
+			side{"destination_account_id", "destination_amount", "source_account_id"},
+			side{"fee_account_id", "fee", "source_account_id"},
+		)
+	}
+
+	db := r.db.Session(&gorm.Session{NewDB: true})
+	var cond *gorm.DB
+	for _, sd := range sides {
+		q := db.Where(sd.account+" = ?", filter.AccountID)
+		if filter.Counterparty != 0 {
+			q = q.Where(sd.other+" = ?", filter.Counterparty)
+		}
+		if filter.MinAmount > 0 {
+			q = q.Where(sd.amount+" >= ?", filter.MinAmount)
+		}
+		if filter.MaxAmount > 0 {
+			q = q.Where(sd.amount+" <= ?", filter.MaxAmount)
+		}
+		if cond == nil {
+			cond = q
+		} else {
+			cond = cond.Or(q)
+		}
+	}
+	return cond
+}
+
+func toTransaction(m TransactionModel) *domain.Transaction {
+	t := &domain.Transaction{
+		ID:                   m.ID,
+		Kind:                 domain.TxKind(m.Kind),
+		SourceAccountID:      m.SourceAccountID,
+		DestinationAccountID: m.DestinationAccountID,
+		Amount:               m.Amount,
+		Currency:             m.Currency,
+		DestinationAmount:    m.DestinationAmount,
+		DestinationCurrency:  m.DestinationCurrency,
+		FXRate:               m.FXRate,
+		Fee:                  m.Fee,
+		CreatedAt:            m.CreatedAt,
+	}
+	t.Description = m.Description
+	t.ExternalRef = m.ExternalRef
+	t.Metadata = unmarshalMetadata(m.Metadata)
+	if m.FeeAccountID != nil {
+		t.FeeAccountID = *m.FeeAccountID
+	}
+	if m.ReversalOf != nil {
+		t.ReversalOf = *m.ReversalOf
+	}
+	if m.ParentID != nil {
+		t.ParentID = *m.ParentID
+	}
+	if m.FXRateAt != nil {
+		t.FXRateAt = *m.FXRateAt
+	}
+	// rows written before cross-currency support only carry the source side
+	if t.DestinationCurrency == "" {
+		t.DestinationAmount = t.Amount
+		t.DestinationCurrency = t.Currency
+	}
+	return t
+}
+
+// likeEscaper escapes the wildcards of a LIKE pattern, backslash being the default escape
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func isUniqueViolation(err error) bool {
+	return strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "unique constraint")
+}
+
+func marshalMetadata(metadata map[string]string) (string, error) {
+	if len(metadata) == 0 {
+		return "{}", nil
+	}
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// unmarshalMetadata leaves the map nil when there is nothing in it
+func unmarshalMetadata(raw string) map[string]string {
+	var metadata map[string]string
+	if raw != "" && raw != "{}" {
+		_ = json.Unmarshal([]byte(raw), &metadata)
+	}
+	return metadata
 }
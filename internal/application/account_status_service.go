@@ -0,0 +1,63 @@
+package application
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"github.com/maneeshsagar/tps/internal/core/ports"
+)
+
+// ChangeAccountStatus freezes, unfreezes or closes an account. The change and its
+// reason are recorded. It takes the account lock, so it never interleaves with a transfer.
+func (s *TransferService) ChangeAccountStatus(ctx context.Context, id int64, status domain.AccountStatus, scope domain.FreezeScope, reason string) (*domain.Account, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, domain.ErrInvalidStatusChange
+	}
+
+	unlock, err := s.locks.LockAccounts(ctx, []int64{id}, lockTTL)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	var acc *domain.Account
+	err = s.db.WithTransaction(ctx, func(tx ports.Transaction) error {
+		repo := s.accounts.WithTx(tx)
+		acc, err = repo.GetByID(id)
+		if err != nil {
+			return err
+		}
+
+		from := acc.Status
+		if err := acc.ChangeStatus(status, scope, reason); err != nil {
+			return err
+		}
+		return repo.UpdateStatus(acc, &domain.AccountStatusChange{
+			ID:          uuid.New(),
+			AccountID:   id,
+			From:        from,
+			To:          acc.Status,
+			FreezeScope: acc.FreezeScope,
+			Reason:      reason,
+			CreatedAt:   time.Now(),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("account status changed", "account", id, "status", acc.Status, "scope", acc.FreezeScope, "reason", reason)
+	return acc, nil
+}
+
+// ListAccountStatusChanges returns the status history of an account, oldest first
+func (s *TransferService) ListAccountStatusChanges(ctx context.Context, id int64) ([]*domain.AccountStatusChange, error) {
+	if _, err := s.accounts.GetByID(id); err != nil {
+		return nil, err
+	}
+	return s.accounts.ListStatusChanges(id)
+}
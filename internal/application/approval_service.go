@@ -0,0 +1,135 @@
+package application
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+)
+
+const (
+	defaultPendingApprovals = 50
+	maxPendingApprovals     = 200
+)
+
+// RequestTransferApproval records a transfer that must be approved before it runs.
+// Nothing is queued until an operator other than requestedBy approves it; with a
+// non-zero executeAt it is then scheduled like any future-dated transfer. requestedBy
+// is required, since the checker is told apart from the maker by it.
+func (s *TransferService) RequestTransferApproval(ctx context.Context, from, to, amount int64, currencyCode string, executeAt time.Time, details domain.TransferDetails, requestedBy string) (uuid.UUID, error) {
+	now := time.Now()
+	if !executeAt.IsZero() && !executeAt.After(now) {
+		return uuid.Nil, domain.ErrInvalidExecuteAt
+	}
+	if err := validateTransfer(from, to, amount, details); err != nil {
+		return uuid.Nil, err
+	}
+	requestedBy = strings.TrimSpace(requestedBy)
+	if requestedBy == "" {
+		return uuid.Nil, domain.ErrOperatorRequired
+	}
+	if _, err := s.accounts.GetByID(from); err != nil {
+		return uuid.Nil, err
+	}
+	if _, err := s.accounts.GetByID(to); err != nil {
+		return uuid.Nil, err
+	}
+	if err := s.checkExternalRef(from, details); err != nil {
+		return uuid.Nil, err
+	}
+
+	tx := &domain.AsyncTransaction{
+		ID:              uuid.New(),
+		FromAccount:     from,
+		ToAccount:       to,
+		Amount:          amount,
+		Currency:        currencyCode,
+		Status:          domain.TxStatusPendingApproval,
+		TransferDetails: details,
+		Approval:        domain.Approval{RequestedBy: requestedBy, RequestedAt: now},
+		ExecuteAt:       executeAt,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if err := s.asynctxns.Create(tx); err != nil {
+		s.log.Error("failed to create transfer pending approval", "id", tx.ID, "err", err)
+		return uuid.Nil, err
+	}
+
+	s.log.Info("transfer pending approval", "id", tx.ID, "from", from, "to", to, "amount", amount, "requested_by", requestedBy)
+	return tx.ID, nil
+}
+
+// checkApproval refuses to move amount out of an account when it is above the approval
+// threshold of the account's currency. Only a single transfer can be held for approval,
+// through RequestTransferApproval; any other money move above the threshold is refused.
+func (s *TransferService) checkApproval(accountID, amount int64) error {
+	if len(s.approvalThresholds) == 0 {
+		return nil
+	}
+	acc, err := s.accounts.GetByID(accountID)
+	if err != nil {
+		return err
+	}
+	return s.checkApprovalIn(acc.Currency, amount)
+}
+
+// checkApprovalIn is checkApproval for an amount whose currency is already known
+func (s *TransferService) checkApprovalIn(currencyCode string, amount int64) error {
+	if domain.NeedsApproval(s.approvalThresholds, currencyCode, amount) {
+		return domain.ErrApprovalRequired
+	}
+	return nil
+}
+
+// ApproveTransfer releases a transfer pending approval to the async pipeline
+func (s *TransferService) ApproveTransfer(ctx context.Context, id uuid.UUID, operator, reason string) (*domain.AsyncTransaction, error) {
+	return s.decideTransfer(ctx, id, domain.ApprovalApproved, operator, reason)
+}
+
+// RejectTransfer turns down a transfer pending approval; it never runs
+func (s *TransferService) RejectTransfer(ctx context.Context, id uuid.UUID, operator, reason string) (*domain.AsyncTransaction, error) {
+	return s.decideTransfer(ctx, id, domain.ApprovalRejected, operator, reason)
+}
+
+func (s *TransferService) decideTransfer(ctx context.Context, id uuid.UUID, decision domain.ApprovalDecision, operator, reason string) (*domain.AsyncTransaction, error) {
+	tx, err := s.asynctxns.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Decide(decision, operator, reason, time.Now()); err != nil {
+		return nil, err
+	}
+
+	// the decision only sticks if no other operator decided in between
+	ok, err := s.asynctxns.RecordDecision(tx)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, domain.ErrNotPendingApproval
+	}
+	s.log.Info("transfer approval decided", "id", id, "decision", decision, "operator", tx.DecidedBy)
+
+	// scheduled transfers are picked up by the dispatcher once due
+	if tx.Status == domain.TxStatusPending {
+		if err := s.enqueue(ctx, tx); err != nil {
+			s.asynctxns.UpdateStatus(id, domain.TxStatusFailed, err.Error())
+			return nil, err
+		}
+	}
+	return tx, nil
+}
+
+// ListPendingApprovals returns the transfers waiting for approval, oldest first
+func (s *TransferService) ListPendingApprovals(ctx context.Context, limit int) ([]*domain.AsyncTransaction, error) {
+	if limit <= 0 {
+		limit = defaultPendingApprovals
+	}
+	if limit > maxPendingApprovals {
+		limit = maxPendingApprovals
+	}
+	return s.asynctxns.ListByStatus(domain.TxStatusPendingApproval, limit)
+}
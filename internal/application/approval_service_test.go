@@ -0,0 +1,161 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"github.com/maneeshsagar/tps/internal/core/ports"
+)
+
+// fakeAccounts serves accounts from a map; any other repository method panics
+type fakeAccounts struct {
+	ports.AccountRepository
+	byID map[int64]*domain.Account
+}
+
+func (f fakeAccounts) GetByID(id int64) (*domain.Account, error) {
+	acc, ok := f.byID[id]
+	if !ok {
+		return nil, domain.ErrAccountNotFound
+	}
+	cp := *acc
+	return &cp, nil
+}
+
+func (f fakeAccounts) Create(acc *domain.Account) error {
+	f.byID[acc.AccountID] = acc
+	return nil
+}
+
+func (f fakeAccounts) WithTx(tx ports.Transaction) ports.AccountRepository {
+	return f
+}
+
+// TestApprovalThreshold checks that money moves above the approval threshold are refused
+// before any lock is taken: the service has no lock manager or db, so going further panics.
+func TestApprovalThreshold(t *testing.T) {
+	s := &TransferService{
+		accounts: fakeAccounts{byID: map[int64]*domain.Account{
+			1: {AccountID: 1, Currency: "USD"},
+			2: {AccountID: 2, Currency: "USD"},
+			3: {AccountID: 3, Currency: "USD"},
+		}},
+		approvalThresholds: map[string]int64{"USD": 10000},
+	}
+	ctx := context.Background()
+
+	cases := []struct {
+		name    string
+		run     func() error
+		wantLeg int
+	}{
+		{"transfer", func() error {
+			_, err := s.Transfer(ctx, 1, 2, 10001, domain.TransferDetails{})
+			return err
+		}, -1},
+		{"async transfer", func() error {
+			_, err := s.SubmitTransfer(ctx, 1, 2, 10001, "USD", domain.TransferDetails{})
+			return err
+		}, -1},
+		{"scheduled transfer", func() error {
+			_, err := s.ScheduleTransfer(ctx, 1, 2, 10001, "USD", time.Now().Add(time.Hour), domain.TransferDetails{})
+			return err
+		}, -1},
+		{"batch leg", func() error {
+			_, err := s.TransferBatch(ctx, []TransferLeg{
+				{FromAccount: 1, ToAccount: 2, Amount: 10000},
+				{FromAccount: 2, ToAccount: 3, Amount: 10001},
+			})
+			return err
+		}, 1},
+		{"split by amounts", func() error {
+			_, err := s.SplitTransfer(ctx, 1, 0, []SplitLeg{{ToAccount: 2, Amount: 6000}, {ToAccount: 3, Amount: 4001}}, domain.TransferDetails{})
+			return err
+		}, -1},
+		{"split by shares", func() error {
+			_, err := s.SplitTransfer(ctx, 1, 20000, []SplitLeg{{ToAccount: 2, Share: 5000}, {ToAccount: 3, Share: 5000}}, domain.TransferDetails{})
+			return err
+		}, -1},
+		{"hold", func() error {
+			_, err := s.CreateHold(ctx, 1, 2, 10001, 0)
+			return err
+		}, -1},
+		{"deposit", func() error {
+			_, err := s.Deposit(ctx, 1, 10001, domain.TransferDetails{})
+			return err
+		}, -1},
+		{"withdrawal", func() error {
+			_, err := s.Withdraw(ctx, 1, 10001, domain.TransferDetails{})
+			return err
+		}, -1},
+	}
+
+	for _, tc := range cases {
+		err := tc.run()
+		if !errors.Is(err, domain.ErrApprovalRequired) {
+			t.Errorf("%s: err = %v, want %v", tc.name, err, domain.ErrApprovalRequired)
+			continue
+		}
+		var legErr *BatchLegError
+		if tc.wantLeg >= 0 && (!errors.As(err, &legErr) || legErr.Leg != tc.wantLeg) {
+			t.Errorf("%s: err = %v, want failed leg %d", tc.name, err, tc.wantLeg)
+		}
+	}
+}
+
+func TestRequestTransferApprovalValidation(t *testing.T) {
+	s := &TransferService{accounts: fakeAccounts{byID: map[int64]*domain.Account{
+		1: {AccountID: 1, Currency: "USD"},
+		2: {AccountID: 2, Currency: "USD"},
+	}}}
+	cases := []struct {
+		name     string
+		from, to int64
+		amount   int64
+		operator string
+		want     error
+	}{
+		{"no operator", 1, 2, 10001, "", domain.ErrOperatorRequired},
+		{"blank operator", 1, 2, 10001, "   ", domain.ErrOperatorRequired},
+		{"zero amount", 1, 2, 0, "alice", domain.ErrInvalidAmount},
+		{"negative amount", 1, 2, -5, "alice", domain.ErrInvalidAmount},
+		{"same account", 1, 1, 10001, "alice", domain.ErrSameAccount},
+		{"unknown source", 9, 2, 10001, "alice", domain.ErrAccountNotFound},
+		{"unknown destination", 1, 9, 10001, "alice", domain.ErrAccountNotFound},
+	}
+
+	for _, tc := range cases {
+		_, err := s.RequestTransferApproval(context.Background(), tc.from, tc.to, tc.amount, "USD", time.Time{}, domain.TransferDetails{}, tc.operator)
+		if !errors.Is(err, tc.want) {
+			t.Errorf("%s: RequestTransferApproval = %v, want %v", tc.name, err, tc.want)
+		}
+	}
+}
+
+// busyLocks refuses every lock, so a transfer stops right after its checks
+type busyLocks struct {
+	ports.LockManager
+}
+
+func (busyLocks) LockAccounts(ctx context.Context, accountIDs []int64, ttl time.Duration) (func() error, error) {
+	return nil, domain.ErrLockAcquisitionFailed
+}
+
+// TestQueuedTransferSkipsApproval checks that a queued transfer, checked on submission or
+// approved since, is not held to the approval threshold again when it runs
+func TestQueuedTransferSkipsApproval(t *testing.T) {
+	s := &TransferService{
+		accounts:           fakeAccounts{byID: map[int64]*domain.Account{1: {AccountID: 1, Currency: "USD"}}},
+		locks:              busyLocks{},
+		approvalThresholds: map[string]int64{"USD": 10000},
+	}
+	if _, err := s.Transfer(context.Background(), 1, 2, 10001, domain.TransferDetails{}); !errors.Is(err, domain.ErrApprovalRequired) {
+		t.Errorf("Transfer = %v, want %v", err, domain.ErrApprovalRequired)
+	}
+	if _, err := s.runTransfer(context.Background(), 1, 2, 10001, domain.TransferDetails{}); !errors.Is(err, domain.ErrLockAcquisitionFailed) {
+		t.Errorf("runTransfer = %v, want %v", err, domain.ErrLockAcquisitionFailed)
+	}
+}
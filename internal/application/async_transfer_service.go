@@ -18,64 +18,135 @@ const (
 )
 
 type TransferMessage struct {
-	ID     string `json:"id"`
-	From   int64  `json:"from"`
-	To     int64  `json:"to"`
-	Amount int64  `json:"amount"`
-	Retry  int    `json:"retry,omitempty"`
+	ID          string            `json:"id"`
+	From        int64             `json:"from"`
+	To          int64             `json:"to"`
+	Amount      int64             `json:"amount"`
+	Description string            `json:"description,omitempty"`
+	ExternalRef string            `json:"external_ref,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	Retry       int               `json:"retry,omitempty"`
+}
+
+func (m TransferMessage) details() domain.TransferDetails {
+	return domain.TransferDetails{Description: m.Description, ExternalRef: m.ExternalRef, Metadata: m.Metadata}
 }
 
 // TransferService only submits transfer requests to a queue and updates their status.
 // The actual transfer logic is handled by the consumer.
-func (s *TransferService) SubmitTransfer(ctx context.Context, from, to, amount int64) (uuid.UUID, error) {
+func (s *TransferService) SubmitTransfer(ctx context.Context, from, to, amount int64, currencyCode string, details domain.TransferDetails) (uuid.UUID, error) {
+	if err := details.Validate(); err != nil {
+		return uuid.Nil, err
+	}
+	if err := s.checkApproval(from, amount); err != nil {
+		return uuid.Nil, err
+	}
+	if err := s.checkExternalRef(from, details); err != nil {
+		return uuid.Nil, err
+	}
 
 	id := uuid.New()
 	now := time.Now()
 
 	tx := &domain.AsyncTransaction{
-		ID:          id,
-		FromAccount: from,
-		ToAccount:   to,
-		Amount:      amount,
-		Status:      domain.TxStatusPending,
-		CreatedAt:   now,
-		UpdatedAt:   now,
+		ID:              id,
+		FromAccount:     from,
+		ToAccount:       to,
+		Amount:          amount,
+		Currency:        currencyCode,
+		Status:          domain.TxStatusPending,
+		TransferDetails: details,
+		CreatedAt:       now,
+		UpdatedAt:       now,
 	}
 	if err := s.asynctxns.Create(tx); err != nil {
 		s.log.Error("failed to create async transaction", "id", id, "err", err)
 		return uuid.Nil, err
 	}
 
+	if err := s.enqueue(ctx, tx); err != nil {
+		s.asynctxns.UpdateStatus(id, domain.TxStatusFailed, err.Error())
+		return uuid.Nil, err
+	}
+
+	s.log.Info("submitted transfer", "id", id, "from", from, "to", to, "amount", amount)
+
+	return id, nil
+}
+
+// checkExternalRef refuses a submission whose external_ref an account already used for a
+// completed transfer, so it fails now rather than in the consumer. A ref reused by another
+// submission is caught by the unique index of the submissions, which only covers those
+// that did not fail, get rejected or get cancelled, so a failed payment can be retried.
+func (s *TransferService) checkExternalRef(from int64, details domain.TransferDetails) error {
+	if details.ExternalRef == "" {
+		return nil
+	}
+	_, err := s.txns.GetByExternalRef(from, details.ExternalRef)
+	switch {
+	case err == nil:
+		return domain.ErrDuplicateRef
+	case errors.Is(err, domain.ErrTransactionNotFound):
+		return nil
+	}
+	return err
+}
+
+// bookedBefore returns the transaction a redelivered message already booked, or nil.
+// Booking it again fails on the external_ref the first delivery used; the transaction
+// with that ref is this submission's if it matches it and no other submission claims it.
+func (s *TransferService) bookedBefore(id uuid.UUID, msg TransferMessage) (*domain.Transaction, error) {
+	sub, err := s.asynctxns.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	rec, err := s.txns.GetByExternalRef(msg.From, msg.ExternalRef)
+	if err != nil {
+		return nil, err
+	}
+	if rec.DestinationAccountID != msg.To || rec.Amount != msg.Amount || rec.CreatedAt.Before(sub.CreatedAt) {
+		return nil, nil
+	}
+	other, err := s.asynctxns.GetByTransactionID(rec.ID)
+	switch {
+	case err == nil && other.ID != id:
+		return nil, nil
+	case err != nil && !errors.Is(err, domain.ErrTransactionNotFound):
+		return nil, err
+	}
+	return rec, nil
+}
+
+// enqueue publishes a transfer message for the consumer to process
+func (s *TransferService) enqueue(ctx context.Context, tx *domain.AsyncTransaction) error {
 	msg := TransferMessage{
-		ID:     id.String(),
-		From:   from,
-		To:     to,
-		Amount: amount,
+		ID:          tx.ID.String(),
+		From:        tx.FromAccount,
+		To:          tx.ToAccount,
+		Amount:      tx.Amount,
+		Description: tx.Description,
+		ExternalRef: tx.ExternalRef,
+		Metadata:    tx.Metadata,
 	}
 
 	data, err := json.Marshal(msg)
 	if err != nil {
-		s.log.Error("failed to marshal transfer message", "id", id, "err", err)
-		s.asynctxns.UpdateStatus(id, domain.TxStatusFailed, "internal error")
-		return uuid.Nil, err
+		s.log.Error("failed to marshal transfer message", "id", tx.ID, "err", err)
+		return errors.New("internal error")
 	}
 
-	s.log.Debug("Sending transfer message to queue", "id", id, "data", string(data))
+	s.log.Debug("Sending transfer message to queue", "id", tx.ID, "data", string(data))
 
 	err = s.producer.Publish(ctx, TopicTransactions, ports.Message{
-		Key:   id.String(),
+		Key:   tx.ID.String(),
 		Value: data,
 	})
 
 	if err != nil {
-		s.log.Error("failed to publish transfer message", "id", id, "err", err)
-		s.asynctxns.UpdateStatus(id, domain.TxStatusFailed, "failed to queue")
-		return uuid.Nil, err
+		s.log.Error("failed to publish transfer message", "id", tx.ID, "err", err)
+		return errors.New("failed to queue")
 	}
-
-	s.log.Info("submitted transfer", "id", id, "from", from, "to", to, "amount", amount)
-
-	return id, nil
+	return nil
 }
 
 // GetStatus returns the current status of submitted transaction
@@ -96,7 +167,18 @@ func (s *TransferService) ProcessTransfer(ctx context.Context, msg TransferMessa
 
 	s.log.Info("started processing transfer", "id", id, "from", msg.From, "to", msg.To, "amount", msg.Amount, "retry", msg.Retry)
 
-	_, err = s.Transfer(ctx, msg.From, msg.To, msg.Amount)
+	// queued transfers were checked against the approval threshold on submission or approved
+	result, err := s.runTransfer(ctx, msg.From, msg.To, msg.Amount, msg.details())
+	if errors.Is(err, domain.ErrDuplicateRef) {
+		// a redelivery of a message whose transfer was committed but not marked completed
+		rec, lookupErr := s.bookedBefore(id, msg)
+		if lookupErr != nil {
+			err = lookupErr
+		} else if rec != nil {
+			s.log.Warn("transfer already booked, completing it", "id", msg.ID, "transaction_id", rec.ID)
+			result, err = transferResult(rec), nil
+		}
+	}
 	if err != nil {
 		// business errors - no retry, mark as failed
 		if isBusinessError(err) {
@@ -120,8 +202,8 @@ func (s *TransferService) ProcessTransfer(ctx context.Context, msg TransferMessa
 		return nil
 	}
 
-	s.asynctxns.UpdateStatus(id, domain.TxStatusCompleted, "")
-	s.log.Info("transfer completed", "id", msg.ID)
+	s.asynctxns.Complete(id, result.TransactionID)
+	s.log.Info("transfer completed", "id", msg.ID, "transaction_id", result.TransactionID)
 	return nil
 }
 
@@ -180,8 +262,24 @@ func (s *TransferService) sendToDLQ(ctx context.Context, msg TransferMessage, re
 
 // isBusinessError checks if the error is a known business error that should not be retried
 func isBusinessError(err error) bool {
-	return errors.Is(err, domain.ErrInsufficientBalance) ||
+	return insufficientFunds(err) ||
+		errors.Is(err, domain.ErrMaximumBalance) ||
 		errors.Is(err, domain.ErrAccountNotFound) ||
 		errors.Is(err, domain.ErrInvalidAmount) ||
-		errors.Is(err, domain.ErrSameAccount)
+		errors.Is(err, domain.ErrSameAccount) ||
+		errors.Is(err, domain.ErrCurrencyMismatch) ||
+		errors.Is(err, domain.ErrFXRateNotFound) ||
+		errors.Is(err, domain.ErrAccountFrozen) ||
+		errors.Is(err, domain.ErrAccountClosed) ||
+		errors.Is(err, domain.ErrLimitExceeded) ||
+		errors.Is(err, domain.ErrInvalidDetails) ||
+		errors.Is(err, domain.ErrDuplicateRef) ||
+		errors.Is(err, domain.ErrApprovalRequired)
+}
+
+// insufficientFunds checks if the error is a debit refused by the balance or its policy
+func insufficientFunds(err error) bool {
+	return errors.Is(err, domain.ErrInsufficientBalance) ||
+		errors.Is(err, domain.ErrOverdraftExceeded) ||
+		errors.Is(err, domain.ErrMinimumBalance)
 }
@@ -0,0 +1,42 @@
+package application
+
+import (
+	"context"
+
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"github.com/maneeshsagar/tps/internal/core/ports"
+)
+
+// SetBalancePolicy replaces the overdraft, minimum and maximum balance of an account.
+// It takes the account lock, so a transfer never checks against a half-changed policy.
+func (s *TransferService) SetBalancePolicy(ctx context.Context, id int64, policy domain.BalancePolicy) (*domain.Account, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	unlock, err := s.locks.LockAccounts(ctx, []int64{id}, lockTTL)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	var acc *domain.Account
+	err = s.db.WithTransaction(ctx, func(tx ports.Transaction) error {
+		repo := s.accounts.WithTx(tx)
+		acc, err = repo.GetByID(id)
+		if err != nil {
+			return err
+		}
+		if err := acc.SetBalancePolicy(policy); err != nil {
+			return err
+		}
+		return repo.UpdateBalancePolicy(acc)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("balance policy set", "account", id, "overdraft", policy.OverdraftLimit,
+		"minimum", policy.MinimumBalance, "maximum", policy.MaximumBalance)
+	return acc, nil
+}
@@ -0,0 +1,110 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/maneeshsagar/tps/internal/core/domain"
+)
+
+const (
+	// settleDelay keeps the jobs that look back at a finished day from reading it until
+	// transfers started just before midnight have committed
+	settleDelay = 5 * time.Minute
+	// snapshotBatch is how many accounts are snapshotted per page
+	snapshotBatch = 500
+)
+
+// GetBalanceAsOf rebuilds the ledger balance of an account at a past point in time,
+// from the latest snapshot before it plus the entries posted since. For the current
+// time it equals the live balance, which is the same sum kept up to date.
+func (s *TransferService) GetBalanceAsOf(ctx context.Context, id int64, asOf time.Time) (*domain.BalanceSnapshot, error) {
+	if asOf.After(time.Now()) {
+		return nil, domain.ErrInvalidAsOf
+	}
+	acc, err := s.accounts.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	// the account did not exist yet
+	if asOf.Before(acc.CreatedAt) {
+		return nil, domain.ErrAccountNotFound
+	}
+
+	balance, err := s.balanceAt(acc, asOf)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.BalanceSnapshot{
+		AccountID: acc.AccountID,
+		Currency:  acc.Currency,
+		Balance:   balance,
+		At:        asOf,
+	}, nil
+}
+
+// TakeBalanceSnapshots snapshots every account at the start of the current UTC day.
+// A snapshot is built from the previous one, so each run only reads a day of entries.
+func (s *TransferService) TakeBalanceSnapshots(ctx context.Context) error {
+	at := startOfDay(time.Now().UTC().Add(-settleDelay))
+
+	var afterID int64
+	for {
+		accounts, err := s.accounts.List(afterID, snapshotBatch)
+		if err != nil {
+			return err
+		}
+		for _, acc := range accounts {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := s.takeBalanceSnapshot(acc, at); err != nil {
+				s.log.Error("balance snapshot failed", "account", acc.AccountID, "err", err)
+			}
+		}
+		if len(accounts) < snapshotBatch {
+			return nil
+		}
+		afterID = accounts[len(accounts)-1].AccountID
+	}
+}
+
+func (s *TransferService) takeBalanceSnapshot(acc *domain.Account, at time.Time) error {
+	if at.Before(acc.CreatedAt) {
+		return nil
+	}
+	last, err := s.snapshots.Latest(acc.AccountID, at)
+	if err != nil && !errors.Is(err, domain.ErrSnapshotNotFound) {
+		return err
+	}
+	if last != nil && last.At.Equal(at) {
+		return nil
+	}
+
+	balance, err := s.balanceAt(acc, at)
+	if err != nil {
+		return err
+	}
+	return s.snapshots.Create(&domain.BalanceSnapshot{
+		AccountID: acc.AccountID,
+		Currency:  acc.Currency,
+		Balance:   balance,
+		At:        at,
+		CreatedAt: time.Now(),
+	})
+}
+
+// balanceAt is the ledger balance of an account with every entry posted before at
+func (s *TransferService) balanceAt(acc *domain.Account, at time.Time) (int64, error) {
+	snap, err := s.snapshots.Latest(acc.AccountID, at)
+	if errors.Is(err, domain.ErrSnapshotNotFound) {
+		net, err := s.ledger.NetBefore(acc.AccountID, at)
+		return acc.OpeningBalance + net, err
+	}
+	if err != nil {
+		return 0, err
+	}
+	net, err := s.ledger.NetBetween(acc.AccountID, snap.At, at)
+	return snap.Balance + net, err
+}
@@ -0,0 +1,137 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"github.com/maneeshsagar/tps/internal/core/ports"
+)
+
+// netAt is the net of one account's postings at a time
+type netAt struct {
+	at  time.Time
+	net int64
+}
+
+type fakeLedger struct {
+	ports.LedgerRepository
+	entries []netAt
+}
+
+func (f fakeLedger) NetBefore(accountID int64, before time.Time) (int64, error) {
+	return f.NetBetween(accountID, time.Time{}, before)
+}
+
+func (f fakeLedger) NetBetween(accountID int64, from, to time.Time) (int64, error) {
+	var net int64
+	for _, e := range f.entries {
+		if !e.at.Before(from) && e.at.Before(to) {
+			net += e.net
+		}
+	}
+	return net, nil
+}
+
+// fakeSnapshots keeps the snapshots of one account
+type fakeSnapshots struct {
+	taken *[]*domain.BalanceSnapshot
+}
+
+func (f fakeSnapshots) Create(snapshot *domain.BalanceSnapshot) error {
+	*f.taken = append(*f.taken, snapshot)
+	return nil
+}
+
+func (f fakeSnapshots) Latest(accountID int64, at time.Time) (*domain.BalanceSnapshot, error) {
+	var latest *domain.BalanceSnapshot
+	for _, s := range *f.taken {
+		if !s.At.After(at) && (latest == nil || s.At.After(latest.At)) {
+			latest = s
+		}
+	}
+	if latest == nil {
+		return nil, domain.ErrSnapshotNotFound
+	}
+	return latest, nil
+}
+
+func TestGetBalanceAsOf(t *testing.T) {
+	day := func(d, h int) time.Time { return time.Date(2024, 3, d, h, 0, 0, 0, time.UTC) }
+	acc := &domain.Account{AccountID: 1, Currency: "USD", OpeningBalance: 100, CreatedAt: day(1, 9)}
+	entries := []netAt{{day(1, 10), 500}, {day(1, 15), -200}, {day(2, 11), 300}, {day(3, 8), -50}}
+
+	cases := []struct {
+		name      string
+		snapshots []*domain.BalanceSnapshot
+		asOf      time.Time
+		want      int64
+		wantErr   error
+	}{
+		{"at creation", nil, day(1, 9), 100, nil},
+		{"from the whole ledger", nil, day(2, 12), 700, nil},
+		{"entry at as_of is left out", nil, day(2, 11), 400, nil},
+		{"from a snapshot", []*domain.BalanceSnapshot{{At: day(2, 0), Balance: 400}}, day(3, 12), 650, nil},
+		{"snapshot at as_of", []*domain.BalanceSnapshot{{At: day(2, 0), Balance: 400}}, day(2, 0), 400, nil},
+		// a snapshot carries its own balance, so the entries before it are not read again
+		{"latest snapshot wins", []*domain.BalanceSnapshot{{At: day(2, 0), Balance: 400}, {At: day(3, 0), Balance: 9000}}, day(3, 12), 8950, nil},
+		{"before the account existed", nil, day(1, 8), 0, domain.ErrAccountNotFound},
+		{"in the future", nil, time.Now().Add(time.Hour), 0, domain.ErrInvalidAsOf},
+	}
+
+	for _, tc := range cases {
+		taken := tc.snapshots
+		s := &TransferService{
+			accounts:  fakeAccounts{byID: map[int64]*domain.Account{1: acc}},
+			ledger:    fakeLedger{entries: entries},
+			snapshots: fakeSnapshots{taken: &taken},
+		}
+		got, err := s.GetBalanceAsOf(context.Background(), 1, tc.asOf)
+		if !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: GetBalanceAsOf = %v, want %v", tc.name, err, tc.wantErr)
+			continue
+		}
+		if err == nil && (got.Balance != tc.want || got.Currency != "USD" || !got.At.Equal(tc.asOf)) {
+			t.Errorf("%s: GetBalanceAsOf = %+v, want balance %d at %s", tc.name, got, tc.want, tc.asOf)
+		}
+	}
+}
+
+func TestTakeBalanceSnapshot(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC) }
+	acc := &domain.Account{AccountID: 1, Currency: "USD", CreatedAt: day(1).Add(9 * time.Hour)}
+	entries := []netAt{{day(1).Add(10 * time.Hour), 500}, {day(2).Add(11 * time.Hour), 300}}
+
+	var taken []*domain.BalanceSnapshot
+	s := &TransferService{ledger: fakeLedger{entries: entries}, snapshots: fakeSnapshots{taken: &taken}}
+
+	steps := []struct {
+		name      string
+		at        time.Time
+		wantTaken int
+		want      int64
+	}{
+		{"before the account existed", day(1), 0, 0},
+		{"first day", day(2), 1, 500},
+		{"same day again", day(2), 1, 500},
+		{"built on the previous one", day(3), 2, 800},
+	}
+
+	for _, st := range steps {
+		if err := s.takeBalanceSnapshot(acc, st.at); err != nil {
+			t.Fatalf("%s: takeBalanceSnapshot error: %v", st.name, err)
+		}
+		if len(taken) != st.wantTaken {
+			t.Errorf("%s: %d snapshots, want %d", st.name, len(taken), st.wantTaken)
+			continue
+		}
+		if st.wantTaken > 0 {
+			last := taken[len(taken)-1]
+			if last.Balance != st.want || !last.At.Equal(st.at) {
+				t.Errorf("%s: snapshot %+v, want balance %d at %s", st.name, last, st.want, st.at)
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"github.com/maneeshsagar/tps/internal/core/ports"
+)
+
+// MaxBatchLegs is the largest number of legs accepted in one batch
+const MaxBatchLegs = 100
+
+// TransferLeg is one transfer of a batch, with the amount in the source account's currency
+type TransferLeg struct {
+	FromAccount int64
+	ToAccount   int64
+	Amount      int64
+	Details     domain.TransferDetails
+}
+
+// BatchLegError reports the leg that made a batch roll back
+type BatchLegError struct {
+	Leg int
+	Err error
+}
+
+func (e *BatchLegError) Error() string {
+	return fmt.Sprintf("leg %d: %v", e.Leg, e.Err)
+}
+
+func (e *BatchLegError) Unwrap() error {
+	return e.Err
+}
+
+// TransferBatch applies every leg in order inside one db transaction, holding the locks
+// of all involved accounts. Either every leg is booked or none is; a failing leg is
+// returned as a *BatchLegError. A leg above the approval threshold fails the batch
+// with domain.ErrApprovalRequired.
+func (s *TransferService) TransferBatch(ctx context.Context, legs []TransferLeg) ([]*TransferResult, error) {
+	if len(legs) == 0 || len(legs) > MaxBatchLegs {
+		return nil, domain.ErrInvalidBatch
+	}
+
+	// each account is locked once, however many legs it appears in
+	seen := make(map[int64]bool)
+	var ids []int64
+	for i, leg := range legs {
+		if leg.Amount <= 0 {
+			return nil, &BatchLegError{Leg: i, Err: domain.ErrInvalidAmount}
+		}
+		if leg.FromAccount == leg.ToAccount {
+			return nil, &BatchLegError{Leg: i, Err: domain.ErrSameAccount}
+		}
+		// a batch runs at once, so it can't hold a leg for approval
+		if err := s.checkApproval(leg.FromAccount, leg.Amount); err != nil {
+			return nil, &BatchLegError{Leg: i, Err: err}
+		}
+		for _, id := range []int64{leg.FromAccount, leg.ToAccount} {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	unlock, err := s.locks.LockAccounts(ctx, ids, lockTTL)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	results := make([]*TransferResult, 0, len(legs))
+	err = s.db.WithTransaction(ctx, func(tx ports.Transaction) error {
+		for i, leg := range legs {
+			rec, err := s.transfer(tx, leg.FromAccount, leg.ToAccount, leg.Amount, leg.Details)
+			if err != nil {
+				return &BatchLegError{Leg: i, Err: err}
+			}
+			results = append(results, transferResult(rec))
+		}
+		return nil
+	})
+	if err != nil {
+		s.log.Error("batch transfer failed", "legs", len(legs), "err", err)
+		return nil, err
+	}
+
+	s.log.Info("batch transfer completed", "legs", len(legs))
+	return results, nil
+}
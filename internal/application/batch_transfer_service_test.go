@@ -0,0 +1,87 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"github.com/maneeshsagar/tps/internal/core/ports"
+)
+
+func TestTransferBatchValidation(t *testing.T) {
+	s := &TransferService{}
+	tooMany := make([]TransferLeg, MaxBatchLegs+1)
+	for i := range tooMany {
+		tooMany[i] = TransferLeg{FromAccount: 1, ToAccount: 2, Amount: 1}
+	}
+	cases := []struct {
+		name    string
+		legs    []TransferLeg
+		wantErr error
+		wantLeg int
+	}{
+		{"no legs", nil, domain.ErrInvalidBatch, -1},
+		{"too many legs", tooMany, domain.ErrInvalidBatch, -1},
+		{"zero amount", []TransferLeg{{FromAccount: 1, ToAccount: 2, Amount: 10}, {FromAccount: 2, ToAccount: 3}}, domain.ErrInvalidAmount, 1},
+		{"negative amount", []TransferLeg{{FromAccount: 1, ToAccount: 2, Amount: -10}}, domain.ErrInvalidAmount, 0},
+		{"same account", []TransferLeg{{FromAccount: 1, ToAccount: 2, Amount: 10}, {FromAccount: 3, ToAccount: 4, Amount: 10}, {FromAccount: 5, ToAccount: 5, Amount: 10}}, domain.ErrSameAccount, 2},
+	}
+
+	for _, tc := range cases {
+		_, err := s.TransferBatch(context.Background(), tc.legs)
+		if !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: TransferBatch = %v, want %v", tc.name, err, tc.wantErr)
+			continue
+		}
+		var legErr *BatchLegError
+		isLeg := errors.As(err, &legErr)
+		if tc.wantLeg < 0 && isLeg {
+			t.Errorf("%s: TransferBatch = %v, want no failed leg", tc.name, err)
+		}
+		if tc.wantLeg >= 0 && (!isLeg || legErr.Leg != tc.wantLeg) {
+			t.Errorf("%s: TransferBatch = %v, want failed leg %d", tc.name, err, tc.wantLeg)
+		}
+	}
+}
+
+// recordingLocks records the accounts it is asked to lock and refuses the lock
+type recordingLocks struct {
+	ports.LockManager
+	ids *[]int64
+}
+
+func (l recordingLocks) LockAccounts(ctx context.Context, accountIDs []int64, ttl time.Duration) (func() error, error) {
+	*l.ids = append(*l.ids, accountIDs...)
+	return nil, domain.ErrLockAcquisitionFailed
+}
+
+func TestTransferBatchLocksEachAccountOnce(t *testing.T) {
+	var locked []int64
+	s := &TransferService{locks: recordingLocks{ids: &locked}}
+	legs := []TransferLeg{
+		{FromAccount: 1, ToAccount: 2, Amount: 10},
+		{FromAccount: 2, ToAccount: 3, Amount: 10},
+		{FromAccount: 3, ToAccount: 1, Amount: 10},
+		{FromAccount: 1, ToAccount: 3, Amount: 10},
+	}
+
+	if _, err := s.TransferBatch(context.Background(), legs); !errors.Is(err, domain.ErrLockAcquisitionFailed) {
+		t.Fatalf("TransferBatch = %v, want %v", err, domain.ErrLockAcquisitionFailed)
+	}
+	if want := []int64{1, 2, 3}; !slices.Equal(locked, want) {
+		t.Errorf("locked %v, want %v", locked, want)
+	}
+}
+
+func TestBatchLegError(t *testing.T) {
+	err := error(&BatchLegError{Leg: 3, Err: domain.ErrInsufficientBalance})
+	if got, want := err.Error(), "leg 3: "+domain.ErrInsufficientBalance.Error(); got != want {
+		t.Errorf("Error = %q, want %q", got, want)
+	}
+	if !errors.Is(err, domain.ErrInsufficientBalance) {
+		t.Errorf("errors.Is(%v, ErrInsufficientBalance) = false", err)
+	}
+}
@@ -0,0 +1,318 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"github.com/maneeshsagar/tps/internal/core/ports"
+)
+
+const (
+	// DefaultEscrowTTL is how long an escrow waits for settlement before it is refunded
+	DefaultEscrowTTL = 30 * 24 * time.Hour
+	// escrowExpiryBatch is the number of expired escrows refunded per sweep
+	escrowExpiryBatch = 100
+	// escrowMetadataKey tags the transactions of an escrow with its id
+	escrowMetadataKey = "escrow_id"
+)
+
+// NewEscrow describes an escrow to open. Buyer, Seller and Arbiter are the ids of the
+// parties allowed to act on it. TTL defaults to DefaultEscrowTTL.
+type NewEscrow struct {
+	BuyerAccountID  int64
+	SellerAccountID int64
+	EscrowAccountID int64
+	Buyer           string
+	Seller          string
+	Arbiter         string
+	Amount          int64
+	Description     string
+	TTL             time.Duration
+}
+
+// CreateEscrow moves amount from the buyer into the escrow account. The buyer, seller
+// and escrow account all share one currency. The funding counts against the buyer's
+// transfer limits and approval threshold, but no money move of an escrow is charged a
+// fee: the escrow account holds exactly the principal of its escrows.
+func (s *TransferService) CreateEscrow(ctx context.Context, spec NewEscrow) (*domain.Escrow, error) {
+	if spec.Amount <= 0 {
+		return nil, domain.ErrInvalidAmount
+	}
+	if spec.BuyerAccountID == spec.SellerAccountID || spec.BuyerAccountID == spec.EscrowAccountID ||
+		spec.SellerAccountID == spec.EscrowAccountID {
+		return nil, domain.ErrSameAccount
+	}
+	if len(spec.Description) > domain.MaxDescriptionLength {
+		return nil, domain.ErrInvalidDetails
+	}
+	parties := domain.Escrow{Buyer: spec.Buyer, Seller: spec.Seller, Arbiter: spec.Arbiter}
+	if !parties.ValidParties() {
+		return nil, domain.ErrInvalidEscrowParty
+	}
+	if spec.TTL <= 0 {
+		spec.TTL = DefaultEscrowTTL
+	}
+
+	unlock, err := s.locks.LockAccounts(ctx, []int64{spec.BuyerAccountID, spec.EscrowAccountID}, lockTTL)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	now := time.Now()
+	escrow := &domain.Escrow{
+		ID:              uuid.New(),
+		BuyerAccountID:  spec.BuyerAccountID,
+		SellerAccountID: spec.SellerAccountID,
+		EscrowAccountID: spec.EscrowAccountID,
+		Buyer:           spec.Buyer,
+		Seller:          spec.Seller,
+		Arbiter:         spec.Arbiter,
+		Amount:          spec.Amount,
+		Status:          domain.EscrowFunded,
+		Description:     spec.Description,
+		ExpiresAt:       now.Add(spec.TTL),
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	err = s.db.WithTransaction(ctx, func(tx ports.Transaction) error {
+		acctRepo := s.accounts.WithTx(tx)
+
+		holding, err := acctRepo.GetByID(spec.EscrowAccountID)
+		if err != nil {
+			return err
+		}
+		if holding.Type != domain.AccountTypeEscrow {
+			return domain.ErrInvalidAccountType
+		}
+		for _, id := range []int64{spec.BuyerAccountID, spec.SellerAccountID} {
+			acc, err := acctRepo.GetByID(id)
+			if err != nil {
+				return err
+			}
+			if acc.Currency != holding.Currency {
+				return domain.ErrCurrencyMismatch
+			}
+		}
+		escrow.Currency = holding.Currency
+
+		buyer, err := acctRepo.GetByID(spec.BuyerAccountID)
+		if err != nil {
+			return err
+		}
+		if err := s.checkApprovalIn(buyer.Currency, spec.Amount); err != nil {
+			return err
+		}
+		if err := s.checkLimits(tx, buyer, spec.Amount); err != nil {
+			return err
+		}
+		rec, err := s.moveEscrowFunds(tx, spec.BuyerAccountID, spec.EscrowAccountID, spec.Amount, escrowDetails(escrow, "funding"))
+		if err != nil {
+			return err
+		}
+		escrow.FundingTransactionID = rec.ID
+		return s.escrows.WithTx(tx).Create(escrow)
+	})
+
+	if err != nil {
+		s.log.Error("create escrow failed", "buyer", spec.BuyerAccountID, "seller", spec.SellerAccountID, "err", err)
+		return nil, err
+	}
+
+	s.log.Info("escrow funded", "id", escrow.ID, "buyer", escrow.BuyerAccountID, "amount", escrow.Amount)
+	return escrow, nil
+}
+
+// GetEscrow returns an escrow by id
+func (s *TransferService) GetEscrow(ctx context.Context, id uuid.UUID) (*domain.Escrow, error) {
+	return s.escrows.GetByID(id)
+}
+
+// DisputeEscrow marks a funded escrow as disputed, which keeps it from being refunded
+// at its deadline until the arbiter settles it. Only the buyer or the seller can dispute.
+func (s *TransferService) DisputeEscrow(ctx context.Context, id uuid.UUID, party, reason string) (*domain.Escrow, error) {
+	escrow, err := s.escrows.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock, err := s.locks.LockAccounts(ctx, []int64{escrow.EscrowAccountID}, lockTTL)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	err = s.db.WithTransaction(ctx, func(tx ports.Transaction) error {
+		escrow, err = s.escrows.WithTx(tx).GetByID(id)
+		if err != nil {
+			return err
+		}
+		if err := escrow.CanDispute(party); err != nil {
+			return err
+		}
+		if err := escrow.Dispute(reason); err != nil {
+			return err
+		}
+		escrow.UpdatedAt = time.Now()
+		return s.escrows.WithTx(tx).Update(escrow)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("escrow disputed", "id", id, "party", party, "reason", reason)
+	return escrow, nil
+}
+
+// ReleaseEscrow pays amount from the escrow to the seller. A zero amount releases
+// whatever remains. The buyer or the arbiter can release, see domain.Escrow.CanSettle.
+func (s *TransferService) ReleaseEscrow(ctx context.Context, id uuid.UUID, party string, amount int64) (*domain.Escrow, error) {
+	return s.settleEscrow(ctx, id, party, amount, false)
+}
+
+// RefundEscrow returns amount from the escrow to the buyer. A zero amount refunds
+// whatever remains. The seller or the arbiter can refund, see domain.Escrow.CanSettle.
+func (s *TransferService) RefundEscrow(ctx context.Context, id uuid.UUID, party string, amount int64) (*domain.Escrow, error) {
+	return s.settleEscrow(ctx, id, party, amount, true)
+}
+
+func (s *TransferService) settleEscrow(ctx context.Context, id uuid.UUID, party string, amount int64, refund bool) (*domain.Escrow, error) {
+	if amount < 0 {
+		return nil, domain.ErrInvalidAmount
+	}
+	escrow, err := s.escrows.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock, err := s.locks.LockAccounts(ctx, []int64{escrow.BuyerAccountID, escrow.SellerAccountID, escrow.EscrowAccountID}, lockTTL)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	err = s.db.WithTransaction(ctx, func(tx ports.Transaction) error {
+		// re-read under the lock, the escrow may have been settled since the first read
+		escrow, err = s.escrows.WithTx(tx).GetByID(id)
+		if err != nil {
+			return err
+		}
+		if err := escrow.CanSettle(party, refund); err != nil {
+			return err
+		}
+		if amount == 0 {
+			amount = escrow.Remaining()
+		}
+		release, refunded := amount, int64(0)
+		if refund {
+			release, refunded = 0, amount
+		}
+		if err := escrow.Settle(release, refunded); err != nil {
+			return err
+		}
+		return s.payOutEscrow(tx, escrow, release, refunded)
+	})
+
+	if err != nil {
+		s.log.Error("settle escrow failed", "id", id, "party", party, "refund", refund, "err", err)
+		return nil, err
+	}
+
+	s.log.Info("escrow settled", "id", id, "party", party, "amount", amount, "refund", refund, "status", escrow.Status)
+	return escrow, nil
+}
+
+// ExpireEscrows refunds funded escrows that have passed their deadline. It is run
+// periodically; escrows that fail to refund are picked up by the next run.
+func (s *TransferService) ExpireEscrows(ctx context.Context) error {
+	expired, err := s.escrows.ListExpired(time.Now(), escrowExpiryBatch)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range expired {
+		if err := s.expireEscrow(ctx, e); err != nil {
+			s.log.Error("failed to expire escrow", "id", e.ID, "err", err)
+			continue
+		}
+		s.log.Info("escrow expired", "id", e.ID, "buyer", e.BuyerAccountID)
+	}
+	return nil
+}
+
+func (s *TransferService) expireEscrow(ctx context.Context, e *domain.Escrow) error {
+	unlock, err := s.locks.LockAccounts(ctx, []int64{e.BuyerAccountID, e.EscrowAccountID}, lockTTL)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return s.db.WithTransaction(ctx, func(tx ports.Transaction) error {
+		escrow, err := s.escrows.WithTx(tx).GetByID(e.ID)
+		if err != nil {
+			return err
+		}
+		// settled or disputed since it was listed
+		now := time.Now()
+		if !escrow.IsExpired(now) {
+			return nil
+		}
+		refund, err := escrow.Expire(now)
+		if err != nil {
+			return err
+		}
+		return s.payOutEscrow(tx, escrow, 0, refund)
+	})
+}
+
+// payOutEscrow moves what was just settled on the escrow, release to the seller and
+// refund to the buyer, and stores the escrow
+func (s *TransferService) payOutEscrow(tx ports.Transaction, escrow *domain.Escrow, release, refund int64) error {
+	if release > 0 {
+		if _, err := s.moveEscrowFunds(tx, escrow.EscrowAccountID, escrow.SellerAccountID, release, escrowDetails(escrow, "release")); err != nil {
+			return err
+		}
+	}
+	if refund > 0 {
+		if _, err := s.moveEscrowFunds(tx, escrow.EscrowAccountID, escrow.BuyerAccountID, refund, escrowDetails(escrow, "refund")); err != nil {
+			return err
+		}
+	}
+	escrow.UpdatedAt = time.Now()
+	return s.escrows.WithTx(tx).Update(escrow)
+}
+
+// moveEscrowFunds books amount into or out of an escrow account without fees or transfer
+// limits, so a payout takes exactly what was put in and limits can't block a refund
+func (s *TransferService) moveEscrowFunds(tx ports.Transaction, fromAccountID, toAccountID, amount int64, details domain.TransferDetails) (*domain.Transaction, error) {
+	acctRepo := s.accounts.WithTx(tx)
+	from, err := acctRepo.GetByID(fromAccountID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := acctRepo.GetByID(toAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	rec, err := s.newTransaction(from, to, amount)
+	if err != nil {
+		return nil, err
+	}
+	rec.TransferDetails = details
+	if err := s.book(tx, rec, from, to); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// escrowDetails describes a transaction of an escrow and tags it with the escrow's id,
+// so its transactions can be listed with metadata[escrow_id]
+func escrowDetails(escrow *domain.Escrow, step string) domain.TransferDetails {
+	return domain.TransferDetails{
+		Description: "escrow " + step,
+		Metadata:    map[string]string{escrowMetadataKey: escrow.ID.String(), "escrow_step": step},
+	}
+}
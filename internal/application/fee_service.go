@@ -0,0 +1,176 @@
+package application
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"github.com/maneeshsagar/tps/internal/core/ports"
+	"github.com/maneeshsagar/tps/pkg/currency"
+)
+
+// CreateFeeRule validates and stores a new active fee rule
+func (s *TransferService) CreateFeeRule(ctx context.Context, rule *domain.FeeRule) (*domain.FeeRule, error) {
+	rule.Name = strings.TrimSpace(rule.Name)
+	if rule.Name == "" || !validFeeAmounts(rule) {
+		return nil, domain.ErrInvalidFeeRule
+	}
+	cur, err := currency.Lookup(rule.Currency)
+	if err != nil {
+		return nil, domain.ErrUnsupportedCurrency
+	}
+	rule.Currency = cur.Code
+	if rule.AccountType != "" && !rule.AccountType.Valid() {
+		return nil, domain.ErrInvalidAccountType
+	}
+
+	// fees are credited in the rule's currency, so the revenue account must hold it
+	revenue, err := s.accounts.GetByID(rule.RevenueAccountID)
+	if err != nil {
+		return nil, err
+	}
+	if revenue.Currency != rule.Currency {
+		return nil, domain.ErrCurrencyMismatch
+	}
+
+	rule.ID = uuid.New()
+	rule.Active = true
+	rule.CreatedAt = time.Now()
+	if err := s.fees.Create(rule); err != nil {
+		s.log.Error("failed to create fee rule", "name", rule.Name, "err", err)
+		return nil, err
+	}
+
+	s.log.Info("fee rule created", "id", rule.ID, "name", rule.Name, "type", rule.Type)
+	return rule, nil
+}
+
+// ListFeeRules returns every fee rule, active ones first, in priority order
+func (s *TransferService) ListFeeRules(ctx context.Context) ([]*domain.FeeRule, error) {
+	return s.fees.List()
+}
+
+// DeactivateFeeRule stops a fee rule from applying to new transfers
+func (s *TransferService) DeactivateFeeRule(ctx context.Context, id uuid.UUID) (*domain.FeeRule, error) {
+	if err := s.fees.Deactivate(id); err != nil {
+		return nil, err
+	}
+	s.log.Info("fee rule deactivated", "id", id)
+	return s.fees.GetByID(id)
+}
+
+// applyFee sets the fee of a transfer out of from, using the first matching rule.
+// It runs inside the db transaction of the transfer.
+func (s *TransferService) applyFee(tx ports.Transaction, rec *domain.Transaction, from *domain.Account) error {
+	rules, err := s.fees.ListActive()
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+		if !rule.Matches(from) {
+			continue
+		}
+
+		fee, err := feeFor(rule, rec.Amount)
+		if err != nil {
+			return err
+		}
+		if fee == 0 {
+			return nil
+		}
+
+		// the revenue account is credited by increment rather than locked, but it
+		// still has to be able to receive the fee within its balance policy
+		revenue, err := s.accounts.WithTx(tx).GetByID(rule.RevenueAccountID)
+		if err != nil {
+			return err
+		}
+		if err := revenue.CanReceive(); err != nil {
+			return err
+		}
+		if err := revenue.CanCredit(fee); err != nil {
+			return err
+		}
+
+		rec.Fee = fee
+		rec.FeeAccountID = revenue.AccountID
+		return nil
+	}
+	return nil
+}
+
+// feeFor works out the fee a rule charges on amount, rounded half up to the minor unit
+func feeFor(rule *domain.FeeRule, amount int64) (int64, error) {
+	var fee int64
+	switch rule.Type {
+	case domain.FeeFlat:
+		fee = rule.Flat
+	case domain.FeePercentage:
+		f, err := currency.MulDiv(amount, rule.RateBps, currency.PercentScale)
+		if err != nil {
+			return 0, err
+		}
+		fee = f
+	case domain.FeeTiered:
+		for _, tier := range rule.Tiers {
+			if tier.UpTo != 0 && amount > tier.UpTo {
+				continue
+			}
+			f, err := currency.MulDiv(amount, tier.RateBps, currency.PercentScale)
+			if err != nil {
+				return 0, err
+			}
+			fee = tier.Flat + f
+			break
+		}
+	}
+
+	if rule.MinFee > 0 && fee < rule.MinFee {
+		fee = rule.MinFee
+	}
+	if rule.MaxFee > 0 && fee > rule.MaxFee {
+		fee = rule.MaxFee
+	}
+	return fee, nil
+}
+
+// validFeeAmounts checks the amounts, rates and tiers of a rule against its type
+func validFeeAmounts(rule *domain.FeeRule) bool {
+	if rule.Flat < 0 || rule.RateBps < 0 || rule.RateBps > currency.PercentScale ||
+		rule.MinFee < 0 || rule.MaxFee < 0 || (rule.MaxFee > 0 && rule.MinFee > rule.MaxFee) {
+		return false
+	}
+
+	switch rule.Type {
+	case domain.FeeFlat:
+		return rule.Flat > 0 && rule.RateBps == 0 && len(rule.Tiers) == 0
+	case domain.FeePercentage:
+		return rule.RateBps > 0 && rule.Flat == 0 && len(rule.Tiers) == 0
+	case domain.FeeTiered:
+		if len(rule.Tiers) == 0 || rule.Flat != 0 || rule.RateBps != 0 {
+			return false
+		}
+		// tiers go up in amount, and only the last may be unbounded
+		var prev int64
+		for i, t := range rule.Tiers {
+			if t.Flat < 0 || t.RateBps < 0 || t.RateBps > currency.PercentScale {
+				return false
+			}
+			if t.UpTo == 0 {
+				if i != len(rule.Tiers)-1 {
+					return false
+				}
+				continue
+			}
+			if t.UpTo <= prev {
+				return false
+			}
+			prev = t.UpTo
+		}
+		return true
+	}
+	return false
+}
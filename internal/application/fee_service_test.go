@@ -0,0 +1,133 @@
+package application
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"github.com/maneeshsagar/tps/internal/core/ports"
+)
+
+func TestFeeFor(t *testing.T) {
+	tiers := []domain.FeeTier{
+		{UpTo: 10_000, Flat: 50},
+		{UpTo: 100_000, Flat: 25, RateBps: 100},
+		{RateBps: 50},
+	}
+	cases := []struct {
+		name   string
+		rule   domain.FeeRule
+		amount int64
+		want   int64
+	}{
+		{"flat", domain.FeeRule{Type: domain.FeeFlat, Flat: 30}, 12_345, 30},
+		{"percentage", domain.FeeRule{Type: domain.FeePercentage, RateBps: 150}, 10_000, 150},
+		{"percentage rounds half up", domain.FeeRule{Type: domain.FeePercentage, RateBps: 250}, 1_002, 25},
+		{"percentage rounds down below half", domain.FeeRule{Type: domain.FeePercentage, RateBps: 250}, 1_001, 25},
+		{"percentage rounds up at half", domain.FeeRule{Type: domain.FeePercentage, RateBps: 250}, 1_020, 26},
+		{"percentage min clamp", domain.FeeRule{Type: domain.FeePercentage, RateBps: 100, MinFee: 20}, 1_000, 20},
+		{"percentage max clamp", domain.FeeRule{Type: domain.FeePercentage, RateBps: 100, MaxFee: 500}, 1_000_000, 500},
+		{"percentage between clamps", domain.FeeRule{Type: domain.FeePercentage, RateBps: 100, MinFee: 20, MaxFee: 500}, 10_000, 100},
+		{"tiered first tier", domain.FeeRule{Type: domain.FeeTiered, Tiers: tiers}, 5_000, 50},
+		{"tiered upper bound is inclusive", domain.FeeRule{Type: domain.FeeTiered, Tiers: tiers}, 10_000, 50},
+		{"tiered second tier", domain.FeeRule{Type: domain.FeeTiered, Tiers: tiers}, 10_001, 125},
+		{"tiered unbounded tier", domain.FeeRule{Type: domain.FeeTiered, Tiers: tiers}, 1_000_000, 5_000},
+		{"tiered max clamp", domain.FeeRule{Type: domain.FeeTiered, Tiers: tiers, MaxFee: 1_000}, 1_000_000, 1_000},
+	}
+
+	for _, tc := range cases {
+		got, err := feeFor(&tc.rule, tc.amount)
+		if err != nil {
+			t.Errorf("%s: feeFor(%d) error: %v", tc.name, tc.amount, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("%s: feeFor(%d) = %d, want %d", tc.name, tc.amount, got, tc.want)
+		}
+	}
+}
+
+func TestValidFeeAmounts(t *testing.T) {
+	cases := []struct {
+		name string
+		rule domain.FeeRule
+		want bool
+	}{
+		{"flat", domain.FeeRule{Type: domain.FeeFlat, Flat: 30}, true},
+		{"flat zero", domain.FeeRule{Type: domain.FeeFlat}, false},
+		{"flat with rate", domain.FeeRule{Type: domain.FeeFlat, Flat: 30, RateBps: 10}, false},
+		{"flat with tiers", domain.FeeRule{Type: domain.FeeFlat, Flat: 30, Tiers: []domain.FeeTier{{Flat: 1}}}, false},
+		{"percentage", domain.FeeRule{Type: domain.FeePercentage, RateBps: 100, MinFee: 10, MaxFee: 500}, true},
+		{"percentage zero rate", domain.FeeRule{Type: domain.FeePercentage}, false},
+		{"percentage above 100%", domain.FeeRule{Type: domain.FeePercentage, RateBps: 10_001}, false},
+		{"percentage with flat", domain.FeeRule{Type: domain.FeePercentage, RateBps: 100, Flat: 5}, false},
+		{"percentage min above max", domain.FeeRule{Type: domain.FeePercentage, RateBps: 100, MinFee: 600, MaxFee: 500}, false},
+		{"percentage negative min", domain.FeeRule{Type: domain.FeePercentage, RateBps: 100, MinFee: -1}, false},
+		{"negative flat", domain.FeeRule{Type: domain.FeeFlat, Flat: -5}, false},
+		{"tiered", domain.FeeRule{Type: domain.FeeTiered, Tiers: []domain.FeeTier{{UpTo: 100, Flat: 5}, {RateBps: 10}}}, true},
+		{"tiered all bounded", domain.FeeRule{Type: domain.FeeTiered, Tiers: []domain.FeeTier{{UpTo: 100, Flat: 5}, {UpTo: 200, Flat: 8}}}, true},
+		{"tiered no tiers", domain.FeeRule{Type: domain.FeeTiered}, false},
+		{"tiered with rule rate", domain.FeeRule{Type: domain.FeeTiered, RateBps: 10, Tiers: []domain.FeeTier{{Flat: 5}}}, false},
+		{"tiered not increasing", domain.FeeRule{Type: domain.FeeTiered, Tiers: []domain.FeeTier{{UpTo: 200, Flat: 5}, {UpTo: 200, Flat: 8}}}, false},
+		{"tiered unbounded not last", domain.FeeRule{Type: domain.FeeTiered, Tiers: []domain.FeeTier{{Flat: 5}, {UpTo: 200, Flat: 8}}}, false},
+		{"tiered negative tier", domain.FeeRule{Type: domain.FeeTiered, Tiers: []domain.FeeTier{{Flat: -5}}}, false},
+		{"tiered tier above 100%", domain.FeeRule{Type: domain.FeeTiered, Tiers: []domain.FeeTier{{RateBps: 10_001}}}, false},
+		{"unknown type", domain.FeeRule{Type: "weekly", Flat: 30}, false},
+	}
+
+	for _, tc := range cases {
+		if got := validFeeAmounts(&tc.rule); got != tc.want {
+			t.Errorf("%s: validFeeAmounts = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+// fakeFees serves a fixed list of active rules; any other repository method panics
+type fakeFees struct {
+	ports.FeeRuleRepository
+	active []*domain.FeeRule
+}
+
+func (f fakeFees) ListActive() ([]*domain.FeeRule, error) {
+	return f.active, nil
+}
+
+// TestApplyFee checks the rule picked for a transfer and the revenue account checks.
+// Split legs and every other transfer path are charged through applyFee.
+func TestApplyFee(t *testing.T) {
+	accounts := fakeAccounts{byID: map[int64]*domain.Account{
+		1: {AccountID: 1, Currency: "USD", Type: domain.AccountTypeCustomer, Status: domain.AccountActive},
+		9: {AccountID: 9, Currency: "USD", Type: domain.AccountTypeSystem, Status: domain.AccountActive},
+		8: {AccountID: 8, Currency: "USD", Type: domain.AccountTypeSystem, Status: domain.AccountClosed},
+	}}
+	eur := &domain.FeeRule{Type: domain.FeeFlat, Flat: 99, Currency: "EUR", RevenueAccountID: 9, Active: true}
+	flat := &domain.FeeRule{Type: domain.FeeFlat, Flat: 30, Currency: "USD", RevenueAccountID: 9, Active: true}
+	pct := &domain.FeeRule{Type: domain.FeePercentage, RateBps: 100, Currency: "USD", RevenueAccountID: 9, Active: true}
+	closed := &domain.FeeRule{Type: domain.FeeFlat, Flat: 30, Currency: "USD", RevenueAccountID: 8, Active: true}
+
+	cases := []struct {
+		name     string
+		rules    []*domain.FeeRule
+		wantFee  int64
+		wantAcct int64
+		wantErr  error
+	}{
+		{"no rules", nil, 0, 0, nil},
+		{"first matching rule wins", []*domain.FeeRule{eur, flat, pct}, 30, 9, nil},
+		{"percentage", []*domain.FeeRule{pct, flat}, 20, 9, nil},
+		{"closed revenue account", []*domain.FeeRule{closed}, 0, 0, domain.ErrAccountClosed},
+	}
+
+	for _, tc := range cases {
+		s := &TransferService{accounts: accounts, fees: fakeFees{active: tc.rules}}
+		rec := &domain.Transaction{Amount: 2_000}
+		err := s.applyFee(nil, rec, accounts.byID[1])
+		if !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: applyFee = %v, want %v", tc.name, err, tc.wantErr)
+			continue
+		}
+		if rec.Fee != tc.wantFee || rec.FeeAccountID != tc.wantAcct {
+			t.Errorf("%s: fee = %d to %d, want %d to %d", tc.name, rec.Fee, rec.FeeAccountID, tc.wantFee, tc.wantAcct)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"github.com/maneeshsagar/tps/internal/core/ports"
+)
+
+// Deposit brings money into the system: the account is credited from the treasury
+// account of its currency, which goes negative by the money in circulation
+func (s *TransferService) Deposit(ctx context.Context, accountID, amount int64, details domain.TransferDetails) (*domain.Transaction, error) {
+	return s.fund(ctx, domain.TxKindDeposit, accountID, amount, details)
+}
+
+// Withdraw takes money out of the system, from the account back to its treasury
+func (s *TransferService) Withdraw(ctx context.Context, accountID, amount int64, details domain.TransferDetails) (*domain.Transaction, error) {
+	return s.fund(ctx, domain.TxKindWithdrawal, accountID, amount, details)
+}
+
+// fund moves money between an account and the treasury of its currency. Deposits and
+// withdrawals are not charged fees and are refused above the approval threshold. A
+// withdrawal is money leaving the account, so it must fit its transfer limits.
+func (s *TransferService) fund(ctx context.Context, kind domain.TxKind, accountID, amount int64, details domain.TransferDetails) (*domain.Transaction, error) {
+	if amount <= 0 {
+		return nil, domain.ErrInvalidAmount
+	}
+	if err := details.Validate(); err != nil {
+		return nil, err
+	}
+	acc, err := s.accounts.GetByID(accountID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkApprovalIn(acc.Currency, amount); err != nil {
+		return nil, err
+	}
+	treasury, err := s.accounts.GetTreasury(acc.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if treasury.AccountID == acc.AccountID {
+		return nil, domain.ErrSameAccount
+	}
+
+	unlock, err := s.locks.LockAccounts(ctx, []int64{treasury.AccountID, accountID}, lockTTL)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	var rec *domain.Transaction
+	err = s.db.WithTransaction(ctx, func(tx ports.Transaction) error {
+		acctRepo := s.accounts.WithTx(tx)
+		treasury, err := acctRepo.GetByID(treasury.AccountID)
+		if err != nil {
+			return err
+		}
+		acc, err := acctRepo.GetByID(accountID)
+		if err != nil {
+			return err
+		}
+
+		from, to := treasury, acc
+		if kind == domain.TxKindWithdrawal {
+			from, to = acc, treasury
+			if err := s.checkLimits(tx, acc, amount); err != nil {
+				return err
+			}
+		}
+		rec = &domain.Transaction{
+			ID:                   uuid.New(),
+			Kind:                 kind,
+			SourceAccountID:      from.AccountID,
+			DestinationAccountID: to.AccountID,
+			Amount:               amount,
+			Currency:             acc.Currency,
+			DestinationAmount:    amount,
+			DestinationCurrency:  acc.Currency,
+			TransferDetails:      details,
+			CreatedAt:            time.Now(),
+		}
+		return s.book(tx, rec, from, to)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("funding completed", "kind", kind, "account", accountID, "treasury", treasury.AccountID, "amount", amount, "txn", rec.ID)
+	return rec, nil
+}
@@ -0,0 +1,65 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"github.com/maneeshsagar/tps/pkg/currency"
+)
+
+// SetFXRate creates or replaces the rate for converting base into quote
+func (s *TransferService) SetFXRate(ctx context.Context, base, quote string, rate int64) (*domain.FXRate, error) {
+	b, err := currency.Lookup(base)
+	if err != nil {
+		return nil, domain.ErrUnsupportedCurrency
+	}
+	q, err := currency.Lookup(quote)
+	if err != nil {
+		return nil, domain.ErrUnsupportedCurrency
+	}
+	if b.Code == q.Code || rate <= 0 {
+		return nil, domain.ErrInvalidFXRate
+	}
+
+	fx := &domain.FXRate{
+		Base:      b.Code,
+		Quote:     q.Code,
+		Rate:      rate,
+		UpdatedAt: time.Now(),
+	}
+	if err := s.fxrates.Upsert(fx); err != nil {
+		s.log.Error("failed to save fx rate", "base", b.Code, "quote", q.Code, "err", err)
+		return nil, err
+	}
+
+	s.log.Info("fx rate updated", "base", b.Code, "quote", q.Code, "rate", currency.FormatRate(rate))
+	return fx, nil
+}
+
+// ListFXRates returns every rate in the rate table
+func (s *TransferService) ListFXRates(ctx context.Context) ([]*domain.FXRate, error) {
+	return s.fxrates.List()
+}
+
+// convert applies a scaled rate between two currency codes, rounding half up
+// to the destination currency's minor unit
+func (s *TransferService) convert(amount int64, from, to string, rate int64) (int64, error) {
+	f, err := currency.Lookup(from)
+	if err != nil {
+		return 0, domain.ErrUnsupportedCurrency
+	}
+	t, err := currency.Lookup(to)
+	if err != nil {
+		return 0, domain.ErrUnsupportedCurrency
+	}
+	converted, err := currency.Convert(amount, f, t, rate)
+	if err != nil {
+		return 0, domain.ErrInvalidAmount
+	}
+	if converted <= 0 {
+		// too small to be represented in the destination currency
+		return 0, domain.ErrInvalidAmount
+	}
+	return converted, nil
+}
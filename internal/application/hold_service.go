@@ -0,0 +1,275 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"github.com/maneeshsagar/tps/internal/core/ports"
+)
+
+const (
+	// DefaultHoldTTL is how long a hold stays active when the caller does not set an expiry
+	DefaultHoldTTL = 7 * 24 * time.Hour
+	// holdExpiryBatch is the number of expired holds released per sweep
+	holdExpiryBatch = 100
+)
+
+// CreateHold reserves amount on the source account for a later capture to the destination.
+// The funds stay on the source account but are no longer available to spend. The hold
+// must fit within the source's transfer limits, which are checked again on capture, and
+// be within the approval threshold; capturing it needs no further approval.
+func (s *TransferService) CreateHold(ctx context.Context, fromAccountID, toAccountID, amount int64, ttl time.Duration) (*domain.Hold, error) {
+	if amount <= 0 {
+		return nil, domain.ErrInvalidAmount
+	}
+	if fromAccountID == toAccountID {
+		return nil, domain.ErrSameAccount
+	}
+	if ttl <= 0 {
+		ttl = DefaultHoldTTL
+	}
+	if err := s.checkApproval(fromAccountID, amount); err != nil {
+		return nil, err
+	}
+
+	unlock, err := s.locks.LockAccounts(ctx, []int64{fromAccountID}, lockTTL)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	var hold *domain.Hold
+	err = s.db.WithTransaction(ctx, func(tx ports.Transaction) error {
+		acctRepo := s.accounts.WithTx(tx)
+
+		from, err := acctRepo.GetByID(fromAccountID)
+		if err != nil {
+			return err
+		}
+		if _, err := acctRepo.GetByID(toAccountID); err != nil {
+			return err
+		}
+		if err := s.checkLimits(tx, from, amount); err != nil {
+			return err
+		}
+
+		if err := from.Reserve(amount); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		hold = &domain.Hold{
+			ID:                   uuid.New(),
+			AccountID:            fromAccountID,
+			DestinationAccountID: toAccountID,
+			Amount:               amount,
+			Currency:             from.Currency,
+			Status:               domain.HoldStatusActive,
+			ExpiresAt:            now.Add(ttl),
+			CreatedAt:            now,
+			UpdatedAt:            now,
+		}
+		if err := s.holds.WithTx(tx).Create(hold); err != nil {
+			return err
+		}
+		return acctRepo.Update(from)
+	})
+
+	if err != nil {
+		s.log.Error("create hold failed", "from", fromAccountID, "to", toAccountID, "err", err)
+		return nil, err
+	}
+
+	s.log.Info("hold created", "id", hold.ID, "account", fromAccountID, "amount", amount)
+	return hold, nil
+}
+
+// GetHold returns a hold by id
+func (s *TransferService) GetHold(ctx context.Context, id uuid.UUID) (*domain.Hold, error) {
+	return s.holds.GetByID(id)
+}
+
+// CaptureHold turns an active hold into a real transfer of amount to the hold's destination.
+// A zero amount captures the full hold. Any uncaptured remainder is released. The capture
+// is charged fees and checked against transfer limits like any transfer.
+func (s *TransferService) CaptureHold(ctx context.Context, id uuid.UUID, amount int64) (*domain.Hold, error) {
+	if amount < 0 {
+		return nil, domain.ErrInvalidAmount
+	}
+
+	hold, err := s.holds.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock, err := s.locks.LockAccounts(ctx, []int64{hold.AccountID, hold.DestinationAccountID}, lockTTL)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	expired := false
+	err = s.db.WithTransaction(ctx, func(tx ports.Transaction) error {
+		acctRepo := s.accounts.WithTx(tx)
+
+		// re-read under the lock, the hold may have changed since the first read
+		hold, err = s.holds.WithTx(tx).GetByID(id)
+		if err != nil {
+			return err
+		}
+		if hold.Status != domain.HoldStatusActive {
+			return domain.ErrHoldNotActive
+		}
+		if amount == 0 {
+			amount = hold.Amount
+		}
+		if amount > hold.Amount {
+			return domain.ErrHoldAmountExceeded
+		}
+
+		from, err := acctRepo.GetByID(hold.AccountID)
+		if err != nil {
+			return err
+		}
+
+		if hold.IsExpired(time.Now()) {
+			expired = true
+			return s.endHold(tx, hold, from, domain.HoldStatusExpired)
+		}
+
+		to, err := acctRepo.GetByID(hold.DestinationAccountID)
+		if err != nil {
+			return err
+		}
+
+		if err := from.Release(hold.Amount); err != nil {
+			return err
+		}
+		rec, err := s.newTransaction(from, to, amount)
+		if err != nil {
+			return err
+		}
+
+		// the hold ends before the limits are checked, so it doesn't count next to its capture
+		hold.Status = domain.HoldStatusCaptured
+		hold.CapturedAmount = amount
+		hold.TransactionID = rec.ID
+		hold.UpdatedAt = time.Now()
+		if err := s.holds.WithTx(tx).Update(hold); err != nil {
+			return err
+		}
+
+		if err := s.checkLimits(tx, from, amount); err != nil {
+			return err
+		}
+		if err := s.applyFee(tx, rec, from); err != nil {
+			return err
+		}
+		return s.book(tx, rec, from, to)
+	})
+
+	if err != nil {
+		s.log.Error("capture hold failed", "id", id, "err", err)
+		return nil, err
+	}
+	if expired {
+		return nil, domain.ErrHoldExpired
+	}
+
+	s.log.Info("hold captured", "id", id, "amount", amount, "transaction", hold.TransactionID)
+	return hold, nil
+}
+
+// VoidHold cancels an active hold and releases the reserved funds
+func (s *TransferService) VoidHold(ctx context.Context, id uuid.UUID) (*domain.Hold, error) {
+	hold, err := s.holds.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock, err := s.locks.LockAccounts(ctx, []int64{hold.AccountID}, lockTTL)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	err = s.db.WithTransaction(ctx, func(tx ports.Transaction) error {
+		hold, err = s.holds.WithTx(tx).GetByID(id)
+		if err != nil {
+			return err
+		}
+		if hold.Status != domain.HoldStatusActive {
+			return domain.ErrHoldNotActive
+		}
+		from, err := s.accounts.WithTx(tx).GetByID(hold.AccountID)
+		if err != nil {
+			return err
+		}
+		return s.endHold(tx, hold, from, domain.HoldStatusVoided)
+	})
+
+	if err != nil {
+		s.log.Error("void hold failed", "id", id, "err", err)
+		return nil, err
+	}
+
+	s.log.Info("hold voided", "id", id)
+	return hold, nil
+}
+
+// ExpireHolds releases active holds that have passed their expiry time.
+// It is run periodically; holds that fail to expire are picked up by the next run.
+func (s *TransferService) ExpireHolds(ctx context.Context) error {
+	expired, err := s.holds.ListExpired(time.Now(), holdExpiryBatch)
+	if err != nil {
+		return err
+	}
+
+	for _, h := range expired {
+		if err := s.expireHold(ctx, h); err != nil {
+			s.log.Error("failed to expire hold", "id", h.ID, "err", err)
+			continue
+		}
+		s.log.Info("hold expired", "id", h.ID, "account", h.AccountID)
+	}
+	return nil
+}
+
+func (s *TransferService) expireHold(ctx context.Context, h *domain.Hold) error {
+	unlock, err := s.locks.LockAccounts(ctx, []int64{h.AccountID}, lockTTL)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return s.db.WithTransaction(ctx, func(tx ports.Transaction) error {
+		hold, err := s.holds.WithTx(tx).GetByID(h.ID)
+		if err != nil {
+			return err
+		}
+		// captured or voided since it was listed
+		if hold.Status != domain.HoldStatusActive || !hold.IsExpired(time.Now()) {
+			return nil
+		}
+		from, err := s.accounts.WithTx(tx).GetByID(hold.AccountID)
+		if err != nil {
+			return err
+		}
+		return s.endHold(tx, hold, from, domain.HoldStatusExpired)
+	})
+}
+
+// endHold releases the full held amount back to the account and closes the hold
+func (s *TransferService) endHold(tx ports.Transaction, hold *domain.Hold, acct *domain.Account, status domain.HoldStatus) error {
+	if err := acct.Release(hold.Amount); err != nil {
+		return err
+	}
+	if err := s.accounts.WithTx(tx).Update(acct); err != nil {
+		return err
+	}
+	hold.Status = status
+	hold.UpdatedAt = time.Now()
+	return s.holds.WithTx(tx).Update(hold)
+}
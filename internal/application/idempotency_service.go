@@ -0,0 +1,85 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/maneeshsagar/tps/internal/core/domain"
+)
+
+const (
+	// MaxIdempotencyKeyLength bounds the Idempotency-Key a client may send
+	MaxIdempotencyKeyLength = 255
+	// IdempotencyLease is how long a claimed key stays in progress. It is well above the
+	// time any request takes, and only a request that crashed holds its key that long.
+	IdempotencyLease = time.Minute
+)
+
+// BeginIdempotent claims an idempotency key for a request, identified by fingerprint.
+// It returns nil when the caller holds the key and should process the request, or the
+// completed key whose stored response must be replayed instead. A key already used
+// for a different request, or still being processed, is an error. An in-progress key
+// is held for IdempotencyLease, after which a retry takes it over.
+func (s *TransferService) BeginIdempotent(ctx context.Context, key, fingerprint string, ttl time.Duration) (*domain.IdempotencyKey, error) {
+	if key == "" || len(key) > MaxIdempotencyKeyLength {
+		return nil, domain.ErrInvalidIdempotencyKey
+	}
+
+	// a second attempt covers a key released between our insert and read
+	for attempt := 0; attempt < 2; attempt++ {
+		now := time.Now()
+		acquired, err := s.idempotency.Acquire(&domain.IdempotencyKey{
+			Key:         key,
+			Fingerprint: fingerprint,
+			Status:      domain.IdempotencyInProgress,
+			CreatedAt:   now,
+			LockedUntil: now.Add(IdempotencyLease),
+			ExpiresAt:   now.Add(ttl),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if acquired {
+			return nil, nil
+		}
+
+		rec, err := s.idempotency.Get(key)
+		if errors.Is(err, domain.ErrIdempotencyNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if rec.Fingerprint != fingerprint {
+			return nil, domain.ErrIdempotencyMismatch
+		}
+		if rec.Status != domain.IdempotencyCompleted {
+			return nil, domain.ErrIdempotencyInProgress
+		}
+		return rec, nil
+	}
+	return nil, domain.ErrIdempotencyInProgress
+}
+
+// CompleteIdempotent stores the response of a request holding an idempotency key
+func (s *TransferService) CompleteIdempotent(ctx context.Context, key string, status int, body []byte) error {
+	return s.idempotency.Complete(key, status, body)
+}
+
+// ReleaseIdempotent gives up a key whose request failed in a way worth retrying
+func (s *TransferService) ReleaseIdempotent(ctx context.Context, key string) error {
+	return s.idempotency.Release(key)
+}
+
+// PurgeIdempotencyKeys deletes expired keys; an expired key can also be reused before it is purged
+func (s *TransferService) PurgeIdempotencyKeys(ctx context.Context) error {
+	n, err := s.idempotency.DeleteExpired(time.Now())
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		s.log.Info("purged idempotency keys", "count", n)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"github.com/maneeshsagar/tps/internal/core/ports"
+)
+
+// maxAccrualDays bounds how many days one account catches up per run
+const maxAccrualDays = 366
+
+// RunInterest accrues interest on every savings account for each UTC day that has ended
+// and not been accrued yet, then pays out the interest of the previous month from the
+// interest-expense account. Both steps are keyed by day and month, so a rerun after a
+// crash picks up where the last run stopped and never pays twice.
+func (s *TransferService) RunInterest(ctx context.Context, rateBps, expenseAccountID int64) error {
+	if rateBps <= 0 {
+		return domain.ErrInvalidAmount
+	}
+	accounts, err := s.accounts.ListByType(domain.AccountTypeSavings)
+	if err != nil {
+		return err
+	}
+
+	today := startOfDay(time.Now().UTC().Add(-settleDelay))
+	month := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for _, acc := range accounts {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if acc.Status == domain.AccountClosed {
+			continue
+		}
+		next, err := s.accrueInterest(acc, rateBps, today)
+		if err != nil {
+			s.log.Error("interest accrual failed", "account", acc.AccountID, "err", err)
+			continue
+		}
+		// a month is only paid out once all of its days are accrued
+		if next.Before(month) {
+			continue
+		}
+		if err := s.postInterest(ctx, acc.AccountID, expenseAccountID, month); err != nil {
+			s.log.Error("interest posting failed", "account", acc.AccountID, "err", err)
+		}
+	}
+	return nil
+}
+
+// accrueInterest records the accrual of each day from the one after the last accrued
+// (or the day the account was opened) up to, but not including, today. It returns the
+// first day still to be accrued.
+func (s *TransferService) accrueInterest(acc *domain.Account, rateBps int64, today time.Time) (time.Time, error) {
+	last, err := s.interest.LastAccrualDate(acc.AccountID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	day := startOfDay(acc.CreatedAt.UTC())
+	if !last.IsZero() {
+		day = last.AddDate(0, 0, 1)
+	}
+
+	for n := 0; day.Before(today) && n < maxAccrualDays; n++ {
+		next := day.AddDate(0, 0, 1)
+		// the ledger balance at the end of the day, so a late run accrues what a timely one would have
+		balance, err := s.balanceAt(acc, next)
+		if err != nil {
+			return day, err
+		}
+		accrued, err := domain.DailyInterest(balance, rateBps)
+		if err != nil {
+			return day, err
+		}
+		_, err = s.interest.CreateAccrual(&domain.InterestAccrual{
+			AccountID: acc.AccountID,
+			Date:      day,
+			Balance:   balance,
+			RateBps:   rateBps,
+			Accrued:   accrued,
+			CreatedAt: time.Now(),
+		})
+		if err != nil {
+			return day, err
+		}
+		day = next
+	}
+	return day, nil
+}
+
+// postInterest pays an account the whole minor units of interest it has accrued before
+// month and not been paid yet, and marks the previous month as posted
+func (s *TransferService) postInterest(ctx context.Context, accountID, expenseAccountID int64, month time.Time) error {
+	period := month.AddDate(0, -1, 0).Format("2006-01")
+	posted, err := s.interest.HasPosting(accountID, period)
+	if err != nil || posted {
+		return err
+	}
+	if accountID == expenseAccountID {
+		return domain.ErrSameAccount
+	}
+
+	unlock, err := s.locks.LockAccounts(ctx, []int64{expenseAccountID, accountID}, lockTTL)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return s.db.WithTransaction(ctx, func(tx ports.Transaction) error {
+		acctRepo := s.accounts.WithTx(tx)
+		interestRepo := s.interest.WithTx(tx)
+
+		expense, err := acctRepo.GetByID(expenseAccountID)
+		if err != nil {
+			return err
+		}
+		if expense.Type != domain.AccountTypeSystem {
+			return domain.ErrInvalidAccountType
+		}
+		acc, err := acctRepo.GetByID(accountID)
+		if err != nil {
+			return err
+		}
+		if acc.Currency != expense.Currency {
+			return domain.ErrCurrencyMismatch
+		}
+
+		accrued, err := interestRepo.SumAccrued(accountID, month)
+		if err != nil {
+			return err
+		}
+		paid, err := interestRepo.SumPosted(accountID)
+		if err != nil {
+			return err
+		}
+
+		posting := &domain.InterestPosting{
+			AccountID: accountID,
+			Period:    period,
+			Amount:    domain.InterestDue(accrued, paid),
+			CreatedAt: time.Now(),
+		}
+		var rec *domain.Transaction
+		if posting.Amount > 0 {
+			rec = &domain.Transaction{
+				ID:                   uuid.New(),
+				Kind:                 domain.TxKindInterest,
+				SourceAccountID:      expense.AccountID,
+				DestinationAccountID: acc.AccountID,
+				Amount:               posting.Amount,
+				Currency:             expense.Currency,
+				DestinationAmount:    posting.Amount,
+				DestinationCurrency:  acc.Currency,
+				CreatedAt:            posting.CreatedAt,
+			}
+			posting.TransactionID = rec.ID
+		}
+
+		created, err := interestRepo.CreatePosting(posting)
+		if err != nil || !created {
+			// another run posted this month first
+			return err
+		}
+		if rec == nil {
+			return nil
+		}
+		return s.book(tx, rec, expense, acc)
+	})
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
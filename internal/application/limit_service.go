@@ -0,0 +1,148 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"github.com/maneeshsagar/tps/internal/core/ports"
+	"github.com/maneeshsagar/tps/pkg/currency"
+)
+
+// SetAccountLimit sets the limits of one account, replacing those of its tier
+func (s *TransferService) SetAccountLimit(ctx context.Context, limit *domain.TransferLimit) (*domain.TransferLimit, error) {
+	acc, err := s.accounts.GetByID(limit.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	limit.AccountType = ""
+	limit.Currency = acc.Currency
+	return s.saveLimit(limit)
+}
+
+// SetTierLimit sets the limits of every account of a type in a currency
+func (s *TransferService) SetTierLimit(ctx context.Context, limit *domain.TransferLimit) (*domain.TransferLimit, error) {
+	if !limit.AccountType.Valid() {
+		return nil, domain.ErrInvalidAccountType
+	}
+	cur, err := currency.Lookup(limit.Currency)
+	if err != nil {
+		return nil, domain.ErrUnsupportedCurrency
+	}
+	limit.AccountID = 0
+	limit.Currency = cur.Code
+	return s.saveLimit(limit)
+}
+
+func (s *TransferService) saveLimit(limit *domain.TransferLimit) (*domain.TransferLimit, error) {
+	if limit.MaxPerTransaction < 0 || limit.DailyOutgoing < 0 || limit.MonthlyOutgoing < 0 || limit.HourlyCount < 0 {
+		return nil, domain.ErrInvalidLimit
+	}
+	limit.UpdatedAt = time.Now()
+	if err := s.limits.Upsert(limit); err != nil {
+		s.log.Error("failed to save limit", "account", limit.AccountID, "type", limit.AccountType, "err", err)
+		return nil, err
+	}
+	s.log.Info("limit saved", "account", limit.AccountID, "type", limit.AccountType, "currency", limit.Currency)
+	return limit, nil
+}
+
+// DeleteAccountLimit removes an account's own limits, so its tier's apply again
+func (s *TransferService) DeleteAccountLimit(ctx context.Context, accountID int64) error {
+	return s.limits.Delete(&domain.TransferLimit{AccountID: accountID})
+}
+
+// DeleteTierLimit removes the limits of an account type in a currency
+func (s *TransferService) DeleteTierLimit(ctx context.Context, accountType domain.AccountType, currencyCode string) error {
+	return s.limits.Delete(&domain.TransferLimit{AccountType: accountType, Currency: currencyCode})
+}
+
+// ListLimits returns every account and tier limit
+func (s *TransferService) ListLimits(ctx context.Context) ([]*domain.TransferLimit, error) {
+	return s.limits.List()
+}
+
+// GetAccountLimits returns the limits that apply to an account and what it has used of them.
+// The limit is nil when none applies.
+func (s *TransferService) GetAccountLimits(ctx context.Context, accountID int64) (*domain.TransferLimit, *domain.LimitUsage, error) {
+	acc, err := s.accounts.GetByID(accountID)
+	if err != nil {
+		return nil, nil, err
+	}
+	limit, err := s.limitFor(acc)
+	if err != nil || limit == nil {
+		return nil, nil, err
+	}
+	usage, err := s.limitUsage(s.txns, s.holds, accountID, time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+	return limit, usage, nil
+}
+
+// checkLimits verifies that sending amount from an account stays within its limits.
+// It runs inside the db transaction of the transfer while the account is locked,
+// so concurrent transfers from the account see each other's usage.
+func (s *TransferService) checkLimits(tx ports.Transaction, from *domain.Account, amount int64) error {
+	limit, err := s.limitFor(from)
+	if err != nil || limit == nil {
+		return err
+	}
+	usage, err := s.limitUsage(s.txns.WithTx(tx), s.holds.WithTx(tx), from.AccountID, time.Now())
+	if err != nil {
+		return err
+	}
+	return limit.Check(amount, *usage)
+}
+
+// limitFor returns the account's own limit, or else its tier's, or nil
+func (s *TransferService) limitFor(acc *domain.Account) (*domain.TransferLimit, error) {
+	limit, err := s.limits.GetForAccount(acc.AccountID)
+	if err == nil {
+		return limit, nil
+	}
+	if !errors.Is(err, domain.ErrLimitNotFound) {
+		return nil, err
+	}
+
+	limit, err = s.limits.GetForTier(acc.Type, acc.Currency)
+	if errors.Is(err, domain.ErrLimitNotFound) {
+		return nil, nil
+	}
+	return limit, err
+}
+
+// limitUsage sums what an account has sent in the current UTC day and month and the last hour.
+// Active holds count as sent from when they were placed: a capture books a transfer that
+// takes their place, and a void or expiry gives the headroom back.
+func (s *TransferService) limitUsage(txns ports.TransactionRepository, holds ports.HoldRepository, accountID int64, now time.Time) (*domain.LimitUsage, error) {
+	windows := domain.LimitWindowsAt(now)
+
+	var usage domain.LimitUsage
+	var err error
+	if usage.Daily, _, err = sentSince(txns, holds, accountID, windows.Day); err != nil {
+		return nil, err
+	}
+	if usage.Monthly, _, err = sentSince(txns, holds, accountID, windows.Month); err != nil {
+		return nil, err
+	}
+	if _, usage.LastHour, err = sentSince(txns, holds, accountID, windows.Hour); err != nil {
+		return nil, err
+	}
+	return &usage, nil
+}
+
+// sentSince returns the amount and number of transfers, withdrawals and active holds
+// out of an account since a time
+func sentSince(txns ports.TransactionRepository, holds ports.HoldRepository, accountID int64, since time.Time) (int64, int64, error) {
+	amount, count, err := txns.SumOutgoing(accountID, since)
+	if err != nil {
+		return 0, 0, err
+	}
+	held, holdCount, err := holds.SumActive(accountID, since)
+	if err != nil {
+		return 0, 0, err
+	}
+	return amount + held, count + holdCount, nil
+}
@@ -0,0 +1,78 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"github.com/maneeshsagar/tps/internal/core/ports"
+)
+
+// sentAt is an amount sent, or held, at a time
+type sentAt struct {
+	at     time.Time
+	amount int64
+}
+
+type fakeOutgoing struct {
+	ports.TransactionRepository
+	sent []sentAt
+}
+
+func (f fakeOutgoing) SumOutgoing(accountID int64, since time.Time) (int64, int64, error) {
+	return sumSince(f.sent, since)
+}
+
+type fakeActiveHolds struct {
+	ports.HoldRepository
+	held []sentAt
+}
+
+func (f fakeActiveHolds) SumActive(accountID int64, since time.Time) (int64, int64, error) {
+	return sumSince(f.held, since)
+}
+
+func sumSince(items []sentAt, since time.Time) (amount, count int64, err error) {
+	for _, it := range items {
+		if !it.at.Before(since) {
+			amount += it.amount
+			count++
+		}
+	}
+	return amount, count, nil
+}
+
+func TestLimitUsageCountsActiveHolds(t *testing.T) {
+	now := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+	yesterday := now.Add(-24 * time.Hour)
+	earlier := now.Add(-3 * time.Hour)
+	recent := now.Add(-10 * time.Minute)
+	lastMonth := now.AddDate(0, -1, 0)
+
+	cases := []struct {
+		name  string
+		sent  []sentAt
+		held  []sentAt
+		usage domain.LimitUsage
+	}{
+		{"nothing", nil, nil, domain.LimitUsage{}},
+		{"transfers only", []sentAt{{yesterday, 100}, {earlier, 200}, {recent, 50}}, nil,
+			domain.LimitUsage{Daily: 250, Monthly: 350, LastHour: 1}},
+		{"holds only", nil, []sentAt{{yesterday, 70}, {recent, 30}},
+			domain.LimitUsage{Daily: 30, Monthly: 100, LastHour: 1}},
+		{"transfers and holds", []sentAt{{earlier, 200}, {recent, 50}}, []sentAt{{recent, 30}, {lastMonth, 999}},
+			domain.LimitUsage{Daily: 280, Monthly: 280, LastHour: 2}},
+	}
+
+	for _, tc := range cases {
+		s := &TransferService{}
+		usage, err := s.limitUsage(fakeOutgoing{sent: tc.sent}, fakeActiveHolds{held: tc.held}, 1, now)
+		if err != nil {
+			t.Errorf("%s: limitUsage error: %v", tc.name, err)
+			continue
+		}
+		if *usage != tc.usage {
+			t.Errorf("%s: limitUsage = %+v, want %+v", tc.name, *usage, tc.usage)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+)
+
+// maxDiscrepancies bounds how many accounts a run records per check, so a widespread
+// corruption still yields a run of a sane size
+const maxDiscrepancies = 1000
+
+const (
+	defaultReconciliationRuns = 20
+	maxReconciliationRuns     = 100
+)
+
+// RunReconciliation checks the invariants of the ledger and records the outcome as a
+// run. Discrepancies are logged as errors so they surface without polling the report.
+func (s *TransferService) RunReconciliation(ctx context.Context) error {
+	run := &domain.ReconciliationRun{
+		ID:        uuid.New(),
+		StartedAt: time.Now(),
+	}
+
+	var err error
+	if run.AccountsChecked, err = s.reconciliation.CountAccounts(); err != nil {
+		return err
+	}
+	checks := []func() ([]domain.Discrepancy, error){
+		s.reconciliation.FundMismatches,
+		func() ([]domain.Discrepancy, error) { return s.reconciliation.FlowMismatches(maxDiscrepancies) },
+		func() ([]domain.Discrepancy, error) {
+			return s.reconciliation.NegativeBalances(negativeBalanceTypes(), maxDiscrepancies)
+		},
+	}
+	for _, check := range checks {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		found, err := check()
+		if err != nil {
+			return err
+		}
+		run.Discrepancies = append(run.Discrepancies, found...)
+	}
+
+	run.Status = domain.ReconciliationBalanced
+	if len(run.Discrepancies) > 0 {
+		run.Status = domain.ReconciliationDiscrepancies
+	}
+	run.FinishedAt = time.Now()
+	if err := s.reconciliation.CreateRun(run); err != nil {
+		return err
+	}
+
+	for _, d := range run.Discrepancies {
+		s.log.Error("reconciliation discrepancy", "run", run.ID, "check", d.Check, "account", d.AccountID,
+			"currency", d.Currency, "expected", d.Expected, "actual", d.Actual)
+	}
+	s.log.Info("reconciliation finished", "run", run.ID, "status", run.Status,
+		"accounts", run.AccountsChecked, "discrepancies", len(run.Discrepancies))
+	return nil
+}
+
+// ListReconciliationRuns returns the most recent runs first
+func (s *TransferService) ListReconciliationRuns(ctx context.Context, limit int) ([]*domain.ReconciliationRun, error) {
+	if limit <= 0 {
+		limit = defaultReconciliationRuns
+	}
+	if limit > maxReconciliationRuns {
+		limit = maxReconciliationRuns
+	}
+	return s.reconciliation.ListRuns(limit)
+}
+
+// negativeBalanceTypes are the account types allowed below zero
+func negativeBalanceTypes() []domain.AccountType {
+	var types []domain.AccountType
+	for _, t := range []domain.AccountType{
+		domain.AccountTypeCustomer, domain.AccountTypeMerchant, domain.AccountTypeSavings,
+		domain.AccountTypeSystem, domain.AccountTypeSuspense, domain.AccountTypeEscrow, domain.AccountTypeTreasury,
+	} {
+		if t.AllowsNegative() {
+			types = append(types, t)
+		}
+	}
+	return types
+}
@@ -0,0 +1,153 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"github.com/maneeshsagar/tps/internal/core/ports"
+)
+
+// nopLog discards everything
+type nopLog struct{}
+
+func (nopLog) Debug(msg string, fields ...any) {}
+func (nopLog) Info(msg string, fields ...any)  {}
+func (nopLog) Warn(msg string, fields ...any)  {}
+func (nopLog) Error(msg string, fields ...any) {}
+func (nopLog) Fatal(msg string, fields ...any) {}
+
+// fakeReconciliation returns fixed findings for each check and records the run
+type fakeReconciliation struct {
+	ports.ReconciliationRepository
+	funds, flows, negative []domain.Discrepancy
+	flowsErr               error
+	allowed                []domain.AccountType
+	limits                 []int
+	runs                   []*domain.ReconciliationRun
+	listLimit              int
+}
+
+func (f *fakeReconciliation) CountAccounts() (int64, error) { return 7, nil }
+
+func (f *fakeReconciliation) FundMismatches() ([]domain.Discrepancy, error) { return f.funds, nil }
+
+func (f *fakeReconciliation) FlowMismatches(limit int) ([]domain.Discrepancy, error) {
+	f.limits = append(f.limits, limit)
+	return f.flows, f.flowsErr
+}
+
+func (f *fakeReconciliation) NegativeBalances(allowed []domain.AccountType, limit int) ([]domain.Discrepancy, error) {
+	f.allowed = allowed
+	f.limits = append(f.limits, limit)
+	return f.negative, nil
+}
+
+func (f *fakeReconciliation) CreateRun(run *domain.ReconciliationRun) error {
+	f.runs = append(f.runs, run)
+	return nil
+}
+
+func (f *fakeReconciliation) ListRuns(limit int) ([]*domain.ReconciliationRun, error) {
+	f.listLimit = limit
+	return nil, nil
+}
+
+func TestRunReconciliation(t *testing.T) {
+	errDown := errors.New("db down")
+	fund := domain.Discrepancy{Check: domain.CheckTotalFunds, Currency: "USD", Expected: 1000, Actual: 990}
+	flow := domain.Discrepancy{Check: domain.CheckAccountFlows, AccountID: 4, Currency: "USD", Expected: 50, Actual: 60}
+	negative := domain.Discrepancy{Check: domain.CheckNegativeBalance, AccountID: 5, Currency: "INR", Expected: 0, Actual: -10}
+
+	cases := []struct {
+		name       string
+		repo       *fakeReconciliation
+		wantErr    error
+		wantStatus domain.ReconciliationStatus
+		want       []domain.Discrepancy
+	}{
+		{"balanced", &fakeReconciliation{}, nil, domain.ReconciliationBalanced, nil},
+		{"every check reports", &fakeReconciliation{funds: []domain.Discrepancy{fund}, flows: []domain.Discrepancy{flow}, negative: []domain.Discrepancy{negative}},
+			nil, domain.ReconciliationDiscrepancies, []domain.Discrepancy{fund, flow, negative}},
+		{"one check reports", &fakeReconciliation{negative: []domain.Discrepancy{negative}}, nil, domain.ReconciliationDiscrepancies, []domain.Discrepancy{negative}},
+		{"failed check records no run", &fakeReconciliation{flowsErr: errDown}, errDown, "", nil},
+	}
+
+	for _, tc := range cases {
+		s := &TransferService{reconciliation: tc.repo, log: nopLog{}}
+		err := s.RunReconciliation(context.Background())
+		if !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: RunReconciliation = %v, want %v", tc.name, err, tc.wantErr)
+			continue
+		}
+		if tc.wantErr != nil {
+			if len(tc.repo.runs) != 0 {
+				t.Errorf("%s: recorded %d runs, want none", tc.name, len(tc.repo.runs))
+			}
+			continue
+		}
+		if len(tc.repo.runs) != 1 {
+			t.Errorf("%s: recorded %d runs, want 1", tc.name, len(tc.repo.runs))
+			continue
+		}
+		run := tc.repo.runs[0]
+		if run.Status != tc.wantStatus || run.AccountsChecked != 7 || !slices.Equal(run.Discrepancies, tc.want) {
+			t.Errorf("%s: run %s, %d accounts, %v, want %s, 7 accounts, %v",
+				tc.name, run.Status, run.AccountsChecked, run.Discrepancies, tc.wantStatus, tc.want)
+		}
+		if run.StartedAt.IsZero() || run.FinishedAt.Before(run.StartedAt) {
+			t.Errorf("%s: run started %v, finished %v", tc.name, run.StartedAt, run.FinishedAt)
+		}
+		if !slices.Equal(tc.repo.limits, []int{maxDiscrepancies, maxDiscrepancies}) {
+			t.Errorf("%s: checks limited to %v, want %d each", tc.name, tc.repo.limits, maxDiscrepancies)
+		}
+	}
+}
+
+func TestRunReconciliationCancelled(t *testing.T) {
+	repo := &fakeReconciliation{}
+	s := &TransferService{reconciliation: repo, log: nopLog{}}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := s.RunReconciliation(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("RunReconciliation = %v, want %v", err, context.Canceled)
+	}
+	if len(repo.runs) != 0 {
+		t.Errorf("recorded %d runs, want none", len(repo.runs))
+	}
+}
+
+func TestNegativeBalanceTypes(t *testing.T) {
+	want := []domain.AccountType{domain.AccountTypeSystem, domain.AccountTypeSuspense, domain.AccountTypeTreasury}
+	if got := negativeBalanceTypes(); !slices.Equal(got, want) {
+		t.Errorf("negativeBalanceTypes = %v, want %v", got, want)
+	}
+}
+
+func TestListReconciliationRunsLimit(t *testing.T) {
+	cases := []struct {
+		limit int
+		want  int
+	}{
+		{0, defaultReconciliationRuns},
+		{-5, defaultReconciliationRuns},
+		{10, 10},
+		{maxReconciliationRuns, maxReconciliationRuns},
+		{maxReconciliationRuns + 1, maxReconciliationRuns},
+	}
+
+	for _, tc := range cases {
+		repo := &fakeReconciliation{}
+		s := &TransferService{reconciliation: repo}
+		if _, err := s.ListReconciliationRuns(context.Background(), tc.limit); err != nil {
+			t.Errorf("ListReconciliationRuns(%d) error: %v", tc.limit, err)
+			continue
+		}
+		if repo.listLimit != tc.want {
+			t.Errorf("ListReconciliationRuns(%d) asked for %d runs, want %d", tc.limit, repo.listLimit, tc.want)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"github.com/maneeshsagar/tps/internal/core/ports"
+	"github.com/maneeshsagar/tps/pkg/currency"
+)
+
+// ReverseTransaction books a compensating transaction that moves amount back from the
+// original destination to the original source. amount is in the original destination's
+// currency and zero reverses everything not reversed yet. Cross-currency transfers are
+// reversed at their original rate: each part returns the prorated share of the running
+// total reversed, rounded down, so the parts always add up to the original amount and
+// the last one returns whatever is left. The original fee is not refunded. A reversal
+// above the approval threshold is refused; reverse such a transfer in parts.
+func (s *TransferService) ReverseTransaction(ctx context.Context, id uuid.UUID, amount int64) (*domain.Transaction, error) {
+	if amount < 0 {
+		return nil, domain.ErrInvalidAmount
+	}
+
+	orig, err := s.txns.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	// a split is reversed leg by leg
+	if orig.IsReversal() || orig.IsSplit() {
+		return nil, domain.ErrNotReversible
+	}
+
+	// the same account pair is locked by every reversal of this transaction,
+	// so the reversed total cannot change until we commit
+	unlock, err := s.locks.LockAccounts(ctx, []int64{orig.SourceAccountID, orig.DestinationAccountID}, lockTTL)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	var rec *domain.Transaction
+	err = s.db.WithTransaction(ctx, func(tx ports.Transaction) error {
+		reversed, refunded, err := s.txns.WithTx(tx).SumReversals(orig.ID)
+		if err != nil {
+			return err
+		}
+
+		remaining := orig.DestinationAmount - reversed
+		if amount == 0 {
+			amount = remaining
+		}
+		if amount <= 0 || amount > remaining {
+			return domain.ErrReversalExceeded
+		}
+		if err := s.checkApprovalIn(orig.DestinationCurrency, amount); err != nil {
+			return err
+		}
+
+		// the amount returned to the original source, in its currency. A part too small
+		// to return a minor unit is refused; the rest of it is returned by the next part.
+		target := orig.Amount
+		if amount < remaining {
+			target, err = currency.MulDivDown(reversed+amount, orig.Amount, orig.DestinationAmount)
+			if err != nil {
+				return domain.ErrInvalidAmount
+			}
+		}
+		back := target - refunded
+		if back <= 0 {
+			return domain.ErrInvalidAmount
+		}
+
+		acctRepo := s.accounts.WithTx(tx)
+		from, err := acctRepo.GetByID(orig.DestinationAccountID)
+		if err != nil {
+			return err
+		}
+		to, err := acctRepo.GetByID(orig.SourceAccountID)
+		if err != nil {
+			return err
+		}
+
+		rec = &domain.Transaction{
+			ID:                   uuid.New(),
+			Kind:                 domain.TxKindReversal,
+			ReversalOf:           orig.ID,
+			SourceAccountID:      orig.DestinationAccountID,
+			DestinationAccountID: orig.SourceAccountID,
+			Amount:               amount,
+			Currency:             orig.DestinationCurrency,
+			DestinationAmount:    back,
+			DestinationCurrency:  orig.Currency,
+			CreatedAt:            time.Now(),
+		}
+		if orig.IsCrossCurrency() {
+			rec.FXRate, _ = currency.MulDiv(currency.RateScale, currency.RateScale, orig.FXRate)
+			rec.FXRateAt = orig.FXRateAt
+		}
+		return s.book(tx, rec, from, to)
+	})
+
+	if err != nil {
+		s.log.Error("reversal failed", "original", id, "err", err)
+		return nil, err
+	}
+
+	s.log.Info("transaction reversed", "original", id, "reversal", rec.ID, "amount", rec.Amount)
+	return rec, nil
+}
+
+// GetTransaction returns a completed transaction by id
+func (s *TransferService) GetTransaction(ctx context.Context, id uuid.UUID) (*domain.Transaction, error) {
+	return s.txns.GetByID(id)
+}
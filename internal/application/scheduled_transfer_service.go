@@ -0,0 +1,90 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+)
+
+// scheduledDispatchBatch is the number of due transfers claimed per dispatcher run
+const scheduledDispatchBatch = 100
+
+// ScheduleTransfer records a transfer to be executed at executeAt.
+// Nothing is queued until the dispatcher picks it up once it is due.
+func (s *TransferService) ScheduleTransfer(ctx context.Context, from, to, amount int64, currencyCode string, executeAt time.Time, details domain.TransferDetails) (uuid.UUID, error) {
+	now := time.Now()
+	if !executeAt.After(now) {
+		return uuid.Nil, domain.ErrInvalidExecuteAt
+	}
+	if err := details.Validate(); err != nil {
+		return uuid.Nil, err
+	}
+	if err := s.checkApproval(from, amount); err != nil {
+		return uuid.Nil, err
+	}
+	if err := s.checkExternalRef(from, details); err != nil {
+		return uuid.Nil, err
+	}
+
+	tx := &domain.AsyncTransaction{
+		ID:              uuid.New(),
+		FromAccount:     from,
+		ToAccount:       to,
+		Amount:          amount,
+		Currency:        currencyCode,
+		Status:          domain.TxStatusScheduled,
+		ExecuteAt:       executeAt,
+		TransferDetails: details,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if err := s.asynctxns.Create(tx); err != nil {
+		s.log.Error("failed to create scheduled transaction", "id", tx.ID, "err", err)
+		return uuid.Nil, err
+	}
+
+	s.log.Info("scheduled transfer", "id", tx.ID, "from", from, "to", to, "amount", amount, "execute_at", executeAt)
+	return tx.ID, nil
+}
+
+// CancelScheduledTransfer cancels a scheduled transfer that has not started executing
+func (s *TransferService) CancelScheduledTransfer(ctx context.Context, id uuid.UUID) (*domain.AsyncTransaction, error) {
+	ok, err := s.asynctxns.TransitionStatus(id, domain.TxStatusScheduled, domain.TxStatusCancelled)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.asynctxns.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, domain.ErrNotCancellable
+	}
+
+	s.log.Info("scheduled transfer cancelled", "id", id)
+	return tx, nil
+}
+
+// DispatchScheduledTransfers hands due scheduled transfers to the async pipeline.
+// Claimed transfers move to executing and are processed by ProcessTransfer like any
+// other async transfer. If queueing fails they go back to scheduled for the next run.
+func (s *TransferService) DispatchScheduledTransfers(ctx context.Context) error {
+	due, err := s.asynctxns.ClaimDue(time.Now(), scheduledDispatchBatch)
+	if err != nil {
+		return err
+	}
+
+	for _, tx := range due {
+		if err := s.enqueue(ctx, tx); err != nil {
+			if _, rerr := s.asynctxns.TransitionStatus(tx.ID, domain.TxStatusExecuting, domain.TxStatusScheduled); rerr != nil {
+				s.log.Error("failed to reschedule transfer", "id", tx.ID, "err", rerr)
+			}
+			continue
+		}
+		s.log.Info("dispatched scheduled transfer", "id", tx.ID, "execute_at", tx.ExecuteAt)
+	}
+	return nil
+}
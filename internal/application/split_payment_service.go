@@ -0,0 +1,185 @@
+package application
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"github.com/maneeshsagar/tps/internal/core/ports"
+	"github.com/maneeshsagar/tps/pkg/currency"
+)
+
+// MaxSplitLegs is the largest number of destinations of one split payment
+const MaxSplitLegs = 100
+
+// SplitLeg is one destination of a split payment. Either Amount, in the source
+// currency, or Share, in basis points of the total, is set.
+type SplitLeg struct {
+	ToAccount int64
+	Amount    int64
+	Share     int64
+}
+
+type SplitResult struct {
+	Parent *domain.Transaction
+	Legs   []*domain.Transaction
+}
+
+// SplitTransfer debits amount from one account and fans it out to several destinations,
+// either by fixed amounts or by shares of the total. With fixed amounts, a zero amount
+// means their sum. Shares must add up to 100% and are allocated with currency.Allocate,
+// so no minor unit is lost to rounding. The parent and all legs commit together. A total
+// above the approval threshold is refused with domain.ErrApprovalRequired. details go
+// on the parent; the legs carry its description and metadata but not its external_ref,
+// which is unique per source account. Each leg is charged fees like a transfer of its
+// amount, so the source is debited the total plus the fees of the legs.
+func (s *TransferService) SplitTransfer(ctx context.Context, from, amount int64, legs []SplitLeg, details domain.TransferDetails) (*SplitResult, error) {
+	if err := details.Validate(); err != nil {
+		return nil, err
+	}
+	amounts, err := splitAmounts(amount, legs)
+	if err != nil {
+		return nil, err
+	}
+	var total int64
+	for _, a := range amounts {
+		total += a
+	}
+	// the legs commit together, so a split can't be held for approval
+	if err := s.checkApproval(from, total); err != nil {
+		return nil, err
+	}
+
+	ids := []int64{from}
+	seen := map[int64]bool{from: true}
+	for _, leg := range legs {
+		if leg.ToAccount == from {
+			return nil, domain.ErrSameAccount
+		}
+		if !seen[leg.ToAccount] {
+			seen[leg.ToAccount] = true
+			ids = append(ids, leg.ToAccount)
+		}
+	}
+
+	unlock, err := s.locks.LockAccounts(ctx, ids, lockTTL)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	result := &SplitResult{}
+	err = s.db.WithTransaction(ctx, func(tx ports.Transaction) error {
+		acctRepo := s.accounts.WithTx(tx)
+		src, err := acctRepo.GetByID(from)
+		if err != nil {
+			return err
+		}
+
+		if err := s.checkLimits(tx, src, total); err != nil {
+			return err
+		}
+		now := time.Now()
+		result.Parent = &domain.Transaction{
+			ID:                  uuid.New(),
+			Kind:                domain.TxKindSplit,
+			SourceAccountID:     from,
+			Amount:              total,
+			Currency:            src.Currency,
+			DestinationAmount:   total,
+			DestinationCurrency: src.Currency,
+			TransferDetails:     details,
+			CreatedAt:           now,
+		}
+		if err := s.txns.WithTx(tx).Create(result.Parent); err != nil {
+			return err
+		}
+
+		// destinations are reloaded for every leg: a fee is credited to its revenue
+		// account by increment, and that account may also be a leg destination
+		for i, leg := range legs {
+			to, err := acctRepo.GetByID(leg.ToAccount)
+			if err != nil {
+				return err
+			}
+
+			rec, err := s.newTransaction(src, to, amounts[i])
+			if err != nil {
+				return err
+			}
+			rec.ParentID = result.Parent.ID
+			rec.Description = details.Description
+			rec.Metadata = details.Metadata
+			rec.CreatedAt = now
+			if err := s.applyFee(tx, rec, src); err != nil {
+				return err
+			}
+			if err := s.book(tx, rec, src, to); err != nil {
+				return err
+			}
+			result.Legs = append(result.Legs, rec)
+		}
+		return nil
+	})
+	if err != nil {
+		s.log.Error("split transfer failed", "from", from, "err", err)
+		return nil, err
+	}
+
+	s.log.Info("split transfer completed", "id", result.Parent.ID, "from", from, "legs", len(legs))
+	return result, nil
+}
+
+// splitAmounts works out the amount of every leg
+func splitAmounts(amount int64, legs []SplitLeg) ([]int64, error) {
+	if len(legs) == 0 || len(legs) > MaxSplitLegs || amount < 0 {
+		return nil, domain.ErrInvalidSplit
+	}
+
+	byShare := legs[0].Share > 0
+	amounts := make([]int64, len(legs))
+	shares := make([]int64, len(legs))
+	var sum int64
+	for i, leg := range legs {
+		if byShare {
+			if leg.Share <= 0 || leg.Amount != 0 {
+				return nil, domain.ErrInvalidSplit
+			}
+			shares[i] = leg.Share
+			sum += leg.Share
+			continue
+		}
+		if leg.Amount <= 0 || leg.Share != 0 {
+			return nil, domain.ErrInvalidSplit
+		}
+		if sum > math.MaxInt64-leg.Amount {
+			return nil, domain.ErrInvalidAmount
+		}
+		amounts[i] = leg.Amount
+		sum += leg.Amount
+	}
+
+	if !byShare {
+		if amount != 0 && amount != sum {
+			return nil, domain.ErrInvalidSplit
+		}
+		return amounts, nil
+	}
+
+	if sum != currency.PercentScale || amount == 0 {
+		return nil, domain.ErrInvalidSplit
+	}
+	amounts, err := currency.Allocate(amount, shares)
+	if err != nil {
+		return nil, domain.ErrInvalidSplit
+	}
+	// every leg has to receive something to be booked
+	for _, a := range amounts {
+		if a <= 0 {
+			return nil, domain.ErrInvalidSplit
+		}
+	}
+	return amounts, nil
+}
@@ -0,0 +1,75 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/maneeshsagar/tps/internal/core/domain"
+)
+
+func TestSplitTransferDetails(t *testing.T) {
+	s := &TransferService{}
+	legs := []SplitLeg{{ToAccount: 2, Amount: 100}}
+	cases := []struct {
+		name    string
+		details domain.TransferDetails
+	}{
+		{"long description", domain.TransferDetails{Description: strings.Repeat("x", domain.MaxDescriptionLength+1)}},
+		{"long external_ref", domain.TransferDetails{ExternalRef: strings.Repeat("x", domain.MaxExternalRefLength+1)}},
+		{"empty metadata key", domain.TransferDetails{Metadata: map[string]string{"": "web"}}},
+	}
+
+	for _, tc := range cases {
+		if _, err := s.SplitTransfer(context.Background(), 1, 0, legs, tc.details); !errors.Is(err, domain.ErrInvalidDetails) {
+			t.Errorf("%s: SplitTransfer = %v, want %v", tc.name, err, domain.ErrInvalidDetails)
+		}
+	}
+}
+
+func TestSplitAmounts(t *testing.T) {
+	tooMany := make([]SplitLeg, MaxSplitLegs+1)
+	for i := range tooMany {
+		tooMany[i] = SplitLeg{ToAccount: int64(i + 2), Amount: 1}
+	}
+	cases := []struct {
+		name    string
+		amount  int64
+		legs    []SplitLeg
+		want    []int64
+		wantErr error
+	}{
+		{"amounts", 0, []SplitLeg{{Amount: 300}, {Amount: 700}}, []int64{300, 700}, nil},
+		{"amounts matching the total", 1000, []SplitLeg{{Amount: 300}, {Amount: 700}}, []int64{300, 700}, nil},
+		{"amounts not matching the total", 999, []SplitLeg{{Amount: 300}, {Amount: 700}}, nil, domain.ErrInvalidSplit},
+		{"shares", 1000, []SplitLeg{{Share: 2500}, {Share: 7500}}, []int64{250, 750}, nil},
+		{"shares keep every unit", 100, []SplitLeg{{Share: 3333}, {Share: 3333}, {Share: 3334}}, []int64{33, 33, 34}, nil},
+		{"shares under 100%", 1000, []SplitLeg{{Share: 2500}, {Share: 7000}}, nil, domain.ErrInvalidSplit},
+		{"shares over 100%", 1000, []SplitLeg{{Share: 5000}, {Share: 5001}}, nil, domain.ErrInvalidSplit},
+		{"shares without a total", 0, []SplitLeg{{Share: 5000}, {Share: 5000}}, nil, domain.ErrInvalidSplit},
+		{"share too small for a unit", 10, []SplitLeg{{Share: 1}, {Share: 9999}}, nil, domain.ErrInvalidSplit},
+		{"no legs", 1000, nil, nil, domain.ErrInvalidSplit},
+		{"too many legs", 0, tooMany, nil, domain.ErrInvalidSplit},
+		{"zero leg", 0, []SplitLeg{{Amount: 300}, {}}, nil, domain.ErrInvalidSplit},
+		{"negative leg", 0, []SplitLeg{{Amount: 300}, {Amount: -1}}, nil, domain.ErrInvalidSplit},
+		{"negative total", -1, []SplitLeg{{Amount: 300}}, nil, domain.ErrInvalidSplit},
+		{"amount then share", 0, []SplitLeg{{Amount: 300}, {Share: 5000}}, nil, domain.ErrInvalidSplit},
+		{"share then amount", 1000, []SplitLeg{{Share: 5000}, {Amount: 500}}, nil, domain.ErrInvalidSplit},
+		{"amount and share on one leg", 1000, []SplitLeg{{Amount: 500, Share: 5000}}, nil, domain.ErrInvalidSplit},
+		{"amounts overflow", 0, []SplitLeg{{Amount: math.MaxInt64}, {Amount: 1}}, nil, domain.ErrInvalidAmount},
+	}
+
+	for _, tc := range cases {
+		got, err := splitAmounts(tc.amount, tc.legs)
+		if !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: splitAmounts = %v, want %v", tc.name, err, tc.wantErr)
+			continue
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("%s: splitAmounts = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,378 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"github.com/maneeshsagar/tps/internal/core/ports"
+	"github.com/maneeshsagar/tps/pkg/schedule"
+)
+
+const (
+	// StandingOrderRetryDelay is how long a run waits before retrying after insufficient funds
+	StandingOrderRetryDelay = time.Hour
+	// standingOrderBatch is the number of due orders executed per run of the job
+	standingOrderBatch = 100
+)
+
+// StandingOrderUpdate holds the changes to apply to a standing order.
+// Nil fields are left unchanged.
+type StandingOrderUpdate struct {
+	Amount              *int64
+	EndAt               *time.Time
+	MaxRuns             *int
+	OnInsufficientFunds *domain.InsufficientFundsPolicy
+	// Status suspends an active order or resumes a suspended one
+	Status *domain.StandingOrderStatus
+}
+
+// CreateStandingOrder sets up a recurring transfer. The first run is the first
+// occurrence of the schedule at or after StartAt.
+func (s *TransferService) CreateStandingOrder(ctx context.Context, order *domain.StandingOrder) (*domain.StandingOrder, error) {
+	if order.Amount <= 0 {
+		return nil, domain.ErrInvalidAmount
+	}
+	if order.FromAccount == order.ToAccount {
+		return nil, domain.ErrSameAccount
+	}
+	if order.OnInsufficientFunds == "" {
+		order.OnInsufficientFunds = domain.OnInsufficientSkip
+	}
+	if !validPolicy(order.OnInsufficientFunds) || order.MaxRuns < 0 {
+		return nil, domain.ErrInvalidSchedule
+	}
+
+	now := time.Now()
+	if order.StartAt.IsZero() {
+		order.StartAt = now
+	}
+	if !order.EndAt.IsZero() && order.EndAt.Before(order.StartAt) {
+		return nil, domain.ErrInvalidSchedule
+	}
+
+	sched, err := scheduleOf(order)
+	if err != nil {
+		return nil, err
+	}
+	first := sched.Next(order.StartAt.Add(-time.Nanosecond))
+	if first.IsZero() || (!order.EndAt.IsZero() && first.After(order.EndAt)) {
+		return nil, domain.ErrInvalidSchedule
+	}
+
+	from, err := s.accounts.GetByID(order.FromAccount)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.accounts.GetByID(order.ToAccount); err != nil {
+		return nil, err
+	}
+	// runs can't wait for approval, so an order must stay within the threshold
+	if err := s.checkApprovalIn(from.Currency, order.Amount); err != nil {
+		return nil, err
+	}
+
+	order.ID = uuid.New()
+	order.Currency = from.Currency
+	order.RunCount = 0
+	order.ScheduledFor = first
+	order.NextRunAt = first
+	order.Status = domain.StandingOrderActive
+	order.CreatedAt = now
+	order.UpdatedAt = now
+
+	if err := s.standingOrders.Create(order); err != nil {
+		s.log.Error("failed to create standing order", "from", order.FromAccount, "err", err)
+		return nil, err
+	}
+
+	s.log.Info("standing order created", "id", order.ID, "from", order.FromAccount, "to", order.ToAccount, "first_run", first)
+	return order, nil
+}
+
+// GetStandingOrder returns a standing order by id
+func (s *TransferService) GetStandingOrder(ctx context.Context, id uuid.UUID) (*domain.StandingOrder, error) {
+	return s.standingOrders.GetByID(id)
+}
+
+// ListStandingOrders returns the standing orders paying out of an account
+func (s *TransferService) ListStandingOrders(ctx context.Context, accountID int64) ([]*domain.StandingOrder, error) {
+	return s.standingOrders.ListByAccount(accountID)
+}
+
+// ListStandingOrderRuns returns the run history of a standing order, oldest first
+func (s *TransferService) ListStandingOrderRuns(ctx context.Context, id uuid.UUID) ([]*domain.StandingOrderRun, error) {
+	if _, err := s.standingOrders.GetByID(id); err != nil {
+		return nil, err
+	}
+	return s.standingOrders.ListRuns(id)
+}
+
+// UpdateStandingOrder changes the amount, end, run limit, policy or status of an order.
+// Completed and cancelled orders can no longer be changed.
+func (s *TransferService) UpdateStandingOrder(ctx context.Context, id uuid.UUID, upd StandingOrderUpdate) (*domain.StandingOrder, error) {
+	if upd.Amount != nil && *upd.Amount <= 0 {
+		return nil, domain.ErrInvalidAmount
+	}
+	if (upd.MaxRuns != nil && *upd.MaxRuns < 0) ||
+		(upd.OnInsufficientFunds != nil && !validPolicy(*upd.OnInsufficientFunds)) {
+		return nil, domain.ErrInvalidSchedule
+	}
+
+	return s.changeStandingOrder(ctx, id, func(order *domain.StandingOrder) error {
+		if upd.Amount != nil {
+			if err := s.checkApprovalIn(order.Currency, *upd.Amount); err != nil {
+				return err
+			}
+			order.Amount = *upd.Amount
+		}
+		if upd.EndAt != nil {
+			if upd.EndAt.Before(order.StartAt) {
+				return domain.ErrInvalidSchedule
+			}
+			order.EndAt = *upd.EndAt
+		}
+		if upd.MaxRuns != nil {
+			order.MaxRuns = *upd.MaxRuns
+		}
+		if upd.OnInsufficientFunds != nil {
+			order.OnInsufficientFunds = *upd.OnInsufficientFunds
+		}
+
+		if upd.Status != nil && *upd.Status != order.Status {
+			switch {
+			case *upd.Status == domain.StandingOrderSuspended && order.Status == domain.StandingOrderActive:
+				order.Status = domain.StandingOrderSuspended
+			case *upd.Status == domain.StandingOrderActive && order.Status == domain.StandingOrderSuspended:
+				// occurrences missed while suspended are not paid out
+				order.Status = domain.StandingOrderActive
+				if order.ScheduledFor.Before(time.Now()) {
+					sched, err := scheduleOf(order)
+					if err != nil {
+						return err
+					}
+					order.Advance(sched.Next(time.Now()))
+				}
+			default:
+				return domain.ErrInvalidStatusChange
+			}
+		}
+
+		// a lowered run limit or earlier end date can finish the order straight away;
+		// a pending retry keeps its time
+		retryAt := order.NextRunAt
+		order.Advance(order.ScheduledFor)
+		if retryAt.After(order.NextRunAt) {
+			order.NextRunAt = retryAt
+		}
+		return nil
+	})
+}
+
+// CancelStandingOrder stops a standing order for good
+func (s *TransferService) CancelStandingOrder(ctx context.Context, id uuid.UUID) (*domain.StandingOrder, error) {
+	return s.changeStandingOrder(ctx, id, func(order *domain.StandingOrder) error {
+		order.Status = domain.StandingOrderCancelled
+		return nil
+	})
+}
+
+// changeStandingOrder applies fn to an order under its source account lock,
+// which is the same lock a run takes, so changes never interleave with a run
+func (s *TransferService) changeStandingOrder(ctx context.Context, id uuid.UUID, fn func(order *domain.StandingOrder) error) (*domain.StandingOrder, error) {
+	order, err := s.standingOrders.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock, err := s.locks.LockAccounts(ctx, []int64{order.FromAccount}, lockTTL)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	err = s.db.WithTransaction(ctx, func(tx ports.Transaction) error {
+		repo := s.standingOrders.WithTx(tx)
+		order, err = repo.GetByID(id)
+		if err != nil {
+			return err
+		}
+		if order.Status == domain.StandingOrderCompleted || order.Status == domain.StandingOrderCancelled {
+			return domain.ErrInvalidStatusChange
+		}
+		if err := fn(order); err != nil {
+			return err
+		}
+		order.UpdatedAt = time.Now()
+		return repo.Update(order)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("standing order updated", "id", id, "status", order.Status)
+	return order, nil
+}
+
+// RunStandingOrders executes every standing order that is due.
+// It is run periodically; a run that fails with a transient error is retried on the next tick.
+func (s *TransferService) RunStandingOrders(ctx context.Context) error {
+	due, err := s.standingOrders.ListDue(time.Now(), standingOrderBatch)
+	if err != nil {
+		return err
+	}
+
+	for _, order := range due {
+		if err := s.runStandingOrder(ctx, order.ID, order.FromAccount, order.ToAccount); err != nil {
+			s.log.Error("standing order run failed", "id", order.ID, "err", err)
+		}
+	}
+	return nil
+}
+
+// runStandingOrder pays out the current occurrence of an order. The transfer, the run
+// record and the move to the next occurrence commit together, so a crash never pays twice.
+func (s *TransferService) runStandingOrder(ctx context.Context, id uuid.UUID, from, to int64) error {
+	unlock, err := s.locks.LockAccounts(ctx, []int64{from, to}, lockTTL)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	now := time.Now()
+	var rec *domain.Transaction
+	err = s.db.WithTransaction(ctx, func(tx ports.Transaction) error {
+		order, err := s.standingOrders.WithTx(tx).GetByID(id)
+		if err != nil {
+			return err
+		}
+		// another replica already ran it, or it was changed since it was listed
+		if !isDue(order, now) {
+			return nil
+		}
+		// the threshold may have been lowered since the order was set up
+		if err := s.checkApprovalIn(order.Currency, order.Amount); err != nil {
+			return err
+		}
+
+		rec, err = s.transfer(tx, order.FromAccount, order.ToAccount, order.Amount, domain.TransferDetails{})
+		if err != nil {
+			return err
+		}
+		order.RunCount++
+		return s.finishRun(tx, order, domain.RunStatusCompleted, rec.ID, "")
+	})
+	if err == nil {
+		if rec != nil {
+			s.log.Info("standing order paid", "id", id, "transaction", rec.ID)
+		}
+		return nil
+	}
+	if !isBusinessError(err) {
+		return err
+	}
+
+	// the transfer was rolled back, record the outcome and decide when to run next
+	runErr := err
+	return s.db.WithTransaction(ctx, func(tx ports.Transaction) error {
+		repo := s.standingOrders.WithTx(tx)
+		order, err := repo.GetByID(id)
+		if err != nil {
+			return err
+		}
+		if !isDue(order, now) {
+			return nil
+		}
+
+		status := domain.RunStatusFailed
+		if insufficientFunds(runErr) {
+			switch order.OnInsufficientFunds {
+			case domain.OnInsufficientRetry:
+				sched, err := scheduleOf(order)
+				if err != nil {
+					return err
+				}
+				retryAt := now.Add(StandingOrderRetryDelay)
+				if next := sched.Next(order.ScheduledFor); next.IsZero() || retryAt.Before(next) {
+					s.log.Warn("standing order will retry", "id", id, "retry_at", retryAt)
+					order.NextRunAt = retryAt
+					order.UpdatedAt = now
+					if err := s.recordRun(tx, order, domain.RunStatusRetrying, uuid.Nil, runErr.Error()); err != nil {
+						return err
+					}
+					return repo.Update(order)
+				}
+				// out of time before the next occurrence, give this one up
+				status = domain.RunStatusSkipped
+			case domain.OnInsufficientSuspend:
+				s.log.Warn("standing order suspended", "id", id)
+				order.Status = domain.StandingOrderSuspended
+				order.UpdatedAt = now
+				if err := s.recordRun(tx, order, domain.RunStatusFailed, uuid.Nil, runErr.Error()); err != nil {
+					return err
+				}
+				return repo.Update(order)
+			default:
+				status = domain.RunStatusSkipped
+			}
+		}
+
+		s.log.Warn("standing order run not paid", "id", id, "status", status, "err", runErr)
+		return s.finishRun(tx, order, status, uuid.Nil, runErr.Error())
+	})
+}
+
+// finishRun records the run of the current occurrence and moves the order to the next one
+func (s *TransferService) finishRun(tx ports.Transaction, order *domain.StandingOrder, status domain.StandingOrderRunStatus, txnID uuid.UUID, errMsg string) error {
+	if err := s.recordRun(tx, order, status, txnID, errMsg); err != nil {
+		return err
+	}
+
+	sched, err := scheduleOf(order)
+	if err != nil {
+		return err
+	}
+	order.Advance(sched.Next(order.ScheduledFor))
+	order.UpdatedAt = time.Now()
+	return s.standingOrders.WithTx(tx).Update(order)
+}
+
+func (s *TransferService) recordRun(tx ports.Transaction, order *domain.StandingOrder, status domain.StandingOrderRunStatus, txnID uuid.UUID, errMsg string) error {
+	return s.standingOrders.WithTx(tx).CreateRun(&domain.StandingOrderRun{
+		ID:            uuid.New(),
+		OrderID:       order.ID,
+		ScheduledFor:  order.ScheduledFor,
+		Status:        status,
+		TransactionID: txnID,
+		Error:         errMsg,
+		CreatedAt:     time.Now(),
+	})
+}
+
+// scheduleOf returns the recurrence of a standing order
+func scheduleOf(order *domain.StandingOrder) (schedule.Schedule, error) {
+	switch order.Frequency {
+	case domain.FrequencyDaily:
+		return schedule.Daily(order.StartAt), nil
+	case domain.FrequencyWeekly:
+		return schedule.Weekly(order.StartAt), nil
+	case domain.FrequencyMonthly:
+		return schedule.Monthly(order.StartAt), nil
+	case domain.FrequencyCron:
+		sched, err := schedule.ParseCron(order.CronExpr, time.UTC)
+		if err != nil {
+			return nil, domain.ErrInvalidSchedule
+		}
+		return sched, nil
+	}
+	return nil, domain.ErrInvalidSchedule
+}
+
+func isDue(order *domain.StandingOrder, now time.Time) bool {
+	return order.Status == domain.StandingOrderActive && !order.NextRunAt.After(now)
+}
+
+func validPolicy(p domain.InsufficientFundsPolicy) bool {
+	return p == domain.OnInsufficientSkip || p == domain.OnInsufficientRetry || p == domain.OnInsufficientSuspend
+}
@@ -0,0 +1,102 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/maneeshsagar/tps/internal/core/domain"
+)
+
+// Statement is the summary of an account over [From, To). The closing balance and
+// totals are only known once every line has been written.
+type Statement struct {
+	Account        *domain.Account
+	From           time.Time
+	To             time.Time
+	OpeningBalance int64
+	ClosingBalance int64
+	TotalDebits    int64
+	TotalCredits   int64
+}
+
+// StatementLine is one transaction seen from the statement's account, with the
+// balance after it. Debit includes any fee the account paid.
+type StatementLine struct {
+	Transaction  *domain.Transaction
+	Counterparty int64
+	Debit        int64
+	Credit       int64
+	Balance      int64
+}
+
+// StatementWriter receives a statement as it is built, so a long range is never
+// held in memory
+type StatementWriter interface {
+	Begin(st *Statement) error
+	Line(line *StatementLine) error
+	End(st *Statement) error
+}
+
+// WriteStatement streams the statement of an account over [from, to) to w: the opening
+// balance, every debit and credit with the running balance, then the closing balance.
+// A to in the future is cut at the current time.
+func (s *TransferService) WriteStatement(ctx context.Context, id int64, from, to time.Time, w StatementWriter) error {
+	if now := time.Now(); to.After(now) {
+		to = now
+	}
+	if !from.Before(to) {
+		return domain.ErrInvalidStatementRange
+	}
+	acc, err := s.accounts.GetByID(id)
+	if err != nil {
+		return err
+	}
+	opening, err := s.balanceAt(acc, from)
+	if err != nil {
+		return err
+	}
+
+	st := &Statement{
+		Account:        acc,
+		From:           from,
+		To:             to,
+		OpeningBalance: opening,
+		ClosingBalance: opening,
+	}
+	if err := w.Begin(st); err != nil {
+		return err
+	}
+
+	err = s.txns.StreamForAccount(id, from, to, func(rec *domain.Transaction) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		line := statementLine(id, rec)
+		st.TotalDebits += line.Debit
+		st.TotalCredits += line.Credit
+		st.ClosingBalance += line.Credit - line.Debit
+		line.Balance = st.ClosingBalance
+		return w.Line(line)
+	})
+	if err != nil {
+		return err
+	}
+	return w.End(st)
+}
+
+// statementLine works out what a transaction moved on one account, the same way
+// its ledger postings do
+func statementLine(accountID int64, rec *domain.Transaction) *StatementLine {
+	line := &StatementLine{Transaction: rec, Counterparty: rec.SourceAccountID}
+	if rec.SourceAccountID == accountID {
+		line.Debit = rec.Amount + rec.Fee
+		line.Counterparty = rec.DestinationAccountID
+	}
+	if rec.DestinationAccountID == accountID {
+		line.Credit += rec.DestinationAmount
+	}
+	if rec.Fee > 0 && rec.FeeAccountID == accountID {
+		line.Credit += rec.Fee
+	}
+	return line
+}
@@ -0,0 +1,59 @@
+package application
+
+import (
+	"testing"
+
+	"github.com/maneeshsagar/tps/internal/core/domain"
+)
+
+func TestStatementLine(t *testing.T) {
+	transfer := domain.Transaction{SourceAccountID: 1, DestinationAccountID: 2, Amount: 500, Currency: "USD", DestinationAmount: 500, DestinationCurrency: "USD"}
+	withFee := transfer
+	withFee.Fee, withFee.FeeAccountID = 25, 9
+	fx := domain.Transaction{SourceAccountID: 1, DestinationAccountID: 3, Amount: 1000, Currency: "USD", DestinationAmount: 83250, DestinationCurrency: "INR", Fee: 10, FeeAccountID: 9}
+
+	cases := []struct {
+		name         string
+		account      int64
+		txn          domain.Transaction
+		debit        int64
+		credit       int64
+		counterparty int64
+	}{
+		{"sent", 1, transfer, 500, 0, 2},
+		{"received", 2, transfer, 0, 500, 1},
+		{"sent with a fee", 1, withFee, 525, 0, 2},
+		{"received, the sender paid the fee", 2, withFee, 0, 500, 1},
+		{"fee collected", 9, withFee, 0, 25, 1},
+		{"cross currency sent", 1, fx, 1010, 0, 3},
+		{"cross currency received", 3, fx, 0, 83250, 1},
+		{"cross currency fee collected", 9, fx, 0, 10, 1},
+	}
+
+	for _, tc := range cases {
+		line := statementLine(tc.account, &tc.txn)
+		if line.Debit != tc.debit || line.Credit != tc.credit || line.Counterparty != tc.counterparty {
+			t.Errorf("%s: debit %d, credit %d, counterparty %d, want %d, %d, %d",
+				tc.name, line.Debit, line.Credit, line.Counterparty, tc.debit, tc.credit, tc.counterparty)
+		}
+		if line.Transaction != &tc.txn {
+			t.Errorf("%s: line is not for the transaction", tc.name)
+		}
+
+		// a line moves the account exactly as the transaction's postings do
+		var posted int64
+		for _, e := range domain.TransferPostings(&tc.txn) {
+			if e.AccountID != tc.account {
+				continue
+			}
+			if e.Direction == domain.EntryDebit {
+				posted -= e.Amount
+			} else {
+				posted += e.Amount
+			}
+		}
+		if got := line.Credit - line.Debit; got != posted {
+			t.Errorf("%s: line nets %d, postings net %d", tc.name, got, posted)
+		}
+	}
+}
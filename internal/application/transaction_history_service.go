@@ -0,0 +1,95 @@
+package application
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+)
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+// TransactionPage is one page of a listing. Next is nil on the last page.
+type TransactionPage struct {
+	Transactions []*domain.Transaction
+	Next         *domain.TxCursor
+}
+
+// ListTransactions lists completed transactions, newest first, a page at a time
+func (s *TransferService) ListTransactions(ctx context.Context, filter domain.TransactionFilter, after *domain.TxCursor, limit int) (*TransactionPage, error) {
+	if filter.AccountID != 0 || filter.Direction != "" {
+		return nil, domain.ErrInvalidFilter
+	}
+	return s.listTransactions(filter, after, limit)
+}
+
+// ListAccountTransactions lists the transactions that moved money on one account,
+// newest first, a page at a time. Amounts are filtered in the account's currency.
+func (s *TransferService) ListAccountTransactions(ctx context.Context, accountID int64, filter domain.TransactionFilter, after *domain.TxCursor, limit int) (*TransactionPage, error) {
+	if _, err := s.accounts.GetByID(accountID); err != nil {
+		return nil, err
+	}
+	if filter.Direction != "" && filter.Direction != domain.EntryDebit && filter.Direction != domain.EntryCredit {
+		return nil, domain.ErrInvalidFilter
+	}
+	filter.AccountID = accountID
+	filter.Currency = ""
+	return s.listTransactions(filter, after, limit)
+}
+
+func (s *TransferService) listTransactions(filter domain.TransactionFilter, after *domain.TxCursor, limit int) (*TransactionPage, error) {
+	if filter.MinAmount < 0 || filter.MaxAmount < 0 ||
+		(filter.MaxAmount > 0 && filter.MinAmount > filter.MaxAmount) ||
+		(!filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To)) {
+		return nil, domain.ErrInvalidFilter
+	}
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	if limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+
+	// one extra row tells whether there is a next page
+	txns, err := s.txns.List(filter, after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	page := &TransactionPage{Transactions: txns}
+	if len(txns) > limit {
+		page.Transactions = txns[:limit]
+		last := txns[limit-1]
+		page.Next = &domain.TxCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+	return page, nil
+}
+
+// TransactionDetails is a completed transaction with the records linked to it.
+// Submission is nil unless the transaction came from an async or scheduled transfer.
+type TransactionDetails struct {
+	Transaction *domain.Transaction
+	Reversals   []*domain.Transaction
+	Submission  *domain.AsyncTransaction
+}
+
+// GetTransactionDetails returns a completed transaction with its reversals and the
+// async submission it came from
+func (s *TransferService) GetTransactionDetails(ctx context.Context, id uuid.UUID) (*TransactionDetails, error) {
+	rec, err := s.txns.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	reversals, err := s.txns.ListReversals(id)
+	if err != nil {
+		return nil, err
+	}
+	submission, err := s.asynctxns.GetByTransactionID(id)
+	if err != nil && !errors.Is(err, domain.ErrTransactionNotFound) {
+		return nil, err
+	}
+	return &TransactionDetails{Transaction: rec, Reversals: reversals, Submission: submission}, nil
+}
@@ -2,62 +2,182 @@ package application
 
 import (
 	"context"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/maneeshsagar/tps/internal/core/domain"
 	"github.com/maneeshsagar/tps/internal/core/ports"
 	"github.com/maneeshsagar/tps/logger"
+	"github.com/maneeshsagar/tps/pkg/currency"
 )
 
+// lockTTL bounds how long account locks are waited for
+const lockTTL = 10 * time.Second
+
 type TransferResult struct {
-	TransactionID uuid.UUID
+	TransactionID       uuid.UUID
+	DestinationAmount   int64
+	DestinationCurrency string
+	FXRate              int64
+	FXRateAt            time.Time
+	Fee                 int64
+	Details             domain.TransferDetails
 }
 
 type TransferServiceIntf interface {
-	CreateAccount(ctx context.Context, id, balance int64) error
+	CreateAccount(ctx context.Context, spec NewAccount) (*domain.Account, error)
 	GetAccount(ctx context.Context, id int64) (*domain.Account, error)
-	Transfer(ctx context.Context, from, to, amount int64) (*TransferResult, error)
-	SubmitTransfer(ctx context.Context, from, to, amount int64) (uuid.UUID, error)
+	GetBalanceAsOf(ctx context.Context, id int64, asOf time.Time) (*domain.BalanceSnapshot, error)
+	TakeBalanceSnapshots(ctx context.Context) error
+	WriteStatement(ctx context.Context, id int64, from, to time.Time, w StatementWriter) error
+	ChangeAccountStatus(ctx context.Context, id int64, status domain.AccountStatus, scope domain.FreezeScope, reason string) (*domain.Account, error)
+	ListAccountStatusChanges(ctx context.Context, id int64) ([]*domain.AccountStatusChange, error)
+	SetBalancePolicy(ctx context.Context, id int64, policy domain.BalancePolicy) (*domain.Account, error)
+	BeginIdempotent(ctx context.Context, key, fingerprint string, ttl time.Duration) (*domain.IdempotencyKey, error)
+	CompleteIdempotent(ctx context.Context, key string, status int, body []byte) error
+	ReleaseIdempotent(ctx context.Context, key string) error
+	PurgeIdempotencyKeys(ctx context.Context) error
+	Transfer(ctx context.Context, from, to, amount int64, details domain.TransferDetails) (*TransferResult, error)
+	Deposit(ctx context.Context, accountID, amount int64, details domain.TransferDetails) (*domain.Transaction, error)
+	Withdraw(ctx context.Context, accountID, amount int64, details domain.TransferDetails) (*domain.Transaction, error)
+	TransferBatch(ctx context.Context, legs []TransferLeg) ([]*TransferResult, error)
+	SplitTransfer(ctx context.Context, from, amount int64, legs []SplitLeg, details domain.TransferDetails) (*SplitResult, error)
+	SubmitTransfer(ctx context.Context, from, to, amount int64, currencyCode string, details domain.TransferDetails) (uuid.UUID, error)
 	GetStatus(ctx context.Context, id uuid.UUID) (*domain.AsyncTransaction, error)
+	ScheduleTransfer(ctx context.Context, from, to, amount int64, currencyCode string, executeAt time.Time, details domain.TransferDetails) (uuid.UUID, error)
+	CancelScheduledTransfer(ctx context.Context, id uuid.UUID) (*domain.AsyncTransaction, error)
+	DispatchScheduledTransfers(ctx context.Context) error
+	RequestTransferApproval(ctx context.Context, from, to, amount int64, currencyCode string, executeAt time.Time, details domain.TransferDetails, requestedBy string) (uuid.UUID, error)
+	ApproveTransfer(ctx context.Context, id uuid.UUID, operator, reason string) (*domain.AsyncTransaction, error)
+	RejectTransfer(ctx context.Context, id uuid.UUID, operator, reason string) (*domain.AsyncTransaction, error)
+	ListPendingApprovals(ctx context.Context, limit int) ([]*domain.AsyncTransaction, error)
 	ProcessTransfer(ctx context.Context, msg TransferMessage) error
+	SetFXRate(ctx context.Context, base, quote string, rate int64) (*domain.FXRate, error)
+	ListFXRates(ctx context.Context) ([]*domain.FXRate, error)
+	CreateFeeRule(ctx context.Context, rule *domain.FeeRule) (*domain.FeeRule, error)
+	ListFeeRules(ctx context.Context) ([]*domain.FeeRule, error)
+	DeactivateFeeRule(ctx context.Context, id uuid.UUID) (*domain.FeeRule, error)
+	SetAccountLimit(ctx context.Context, limit *domain.TransferLimit) (*domain.TransferLimit, error)
+	SetTierLimit(ctx context.Context, limit *domain.TransferLimit) (*domain.TransferLimit, error)
+	DeleteAccountLimit(ctx context.Context, accountID int64) error
+	DeleteTierLimit(ctx context.Context, accountType domain.AccountType, currencyCode string) error
+	ListLimits(ctx context.Context) ([]*domain.TransferLimit, error)
+	GetAccountLimits(ctx context.Context, accountID int64) (*domain.TransferLimit, *domain.LimitUsage, error)
+	CreateHold(ctx context.Context, from, to, amount int64, ttl time.Duration) (*domain.Hold, error)
+	GetHold(ctx context.Context, id uuid.UUID) (*domain.Hold, error)
+	CaptureHold(ctx context.Context, id uuid.UUID, amount int64) (*domain.Hold, error)
+	VoidHold(ctx context.Context, id uuid.UUID) (*domain.Hold, error)
+	ExpireHolds(ctx context.Context) error
+	CreateEscrow(ctx context.Context, spec NewEscrow) (*domain.Escrow, error)
+	GetEscrow(ctx context.Context, id uuid.UUID) (*domain.Escrow, error)
+	DisputeEscrow(ctx context.Context, id uuid.UUID, party, reason string) (*domain.Escrow, error)
+	ReleaseEscrow(ctx context.Context, id uuid.UUID, party string, amount int64) (*domain.Escrow, error)
+	RefundEscrow(ctx context.Context, id uuid.UUID, party string, amount int64) (*domain.Escrow, error)
+	ExpireEscrows(ctx context.Context) error
+	GetTransaction(ctx context.Context, id uuid.UUID) (*domain.Transaction, error)
+	GetTransactionDetails(ctx context.Context, id uuid.UUID) (*TransactionDetails, error)
+	ListTransactions(ctx context.Context, filter domain.TransactionFilter, after *domain.TxCursor, limit int) (*TransactionPage, error)
+	ListAccountTransactions(ctx context.Context, accountID int64, filter domain.TransactionFilter, after *domain.TxCursor, limit int) (*TransactionPage, error)
+	ReverseTransaction(ctx context.Context, id uuid.UUID, amount int64) (*domain.Transaction, error)
+	CreateStandingOrder(ctx context.Context, order *domain.StandingOrder) (*domain.StandingOrder, error)
+	GetStandingOrder(ctx context.Context, id uuid.UUID) (*domain.StandingOrder, error)
+	ListStandingOrders(ctx context.Context, accountID int64) ([]*domain.StandingOrder, error)
+	UpdateStandingOrder(ctx context.Context, id uuid.UUID, upd StandingOrderUpdate) (*domain.StandingOrder, error)
+	CancelStandingOrder(ctx context.Context, id uuid.UUID) (*domain.StandingOrder, error)
+	ListStandingOrderRuns(ctx context.Context, id uuid.UUID) ([]*domain.StandingOrderRun, error)
+	RunStandingOrders(ctx context.Context) error
+	RunInterest(ctx context.Context, rateBps, expenseAccountID int64) error
+	RunReconciliation(ctx context.Context) error
+	ListReconciliationRuns(ctx context.Context, limit int) ([]*domain.ReconciliationRun, error)
 }
 
 type TransferService struct {
-	accounts  ports.AccountRepository
-	txns      ports.TransactionRepository
-	asynctxns ports.AsyncTransactionRepository
-	db        ports.TransactionManager
-	locks     ports.LockManager
-	producer  ports.MessageProducer
-	log       logger.Logger
+	accounts       ports.AccountRepository
+	txns           ports.TransactionRepository
+	asynctxns      ports.AsyncTransactionRepository
+	ledger         ports.LedgerRepository
+	fxrates        ports.FXRateRepository
+	holds          ports.HoldRepository
+	fees           ports.FeeRuleRepository
+	limits         ports.LimitRepository
+	standingOrders ports.StandingOrderRepository
+	interest       ports.InterestRepository
+	snapshots      ports.BalanceSnapshotRepository
+	idempotency    ports.IdempotencyRepository
+	reconciliation ports.ReconciliationRepository
+	escrows        ports.EscrowRepository
+	db             ports.TransactionManager
+	locks          ports.LockManager
+	producer       ports.MessageProducer
+	// approvalThresholds is the largest amount per currency, in minor units, that
+	// moves without being approved first
+	approvalThresholds map[string]int64
+	log                logger.Logger
 }
 
 func NewTransferService(
 	accounts ports.AccountRepository,
 	txns ports.TransactionRepository,
 	asynctxns ports.AsyncTransactionRepository,
+	ledger ports.LedgerRepository,
+	fxrates ports.FXRateRepository,
+	holds ports.HoldRepository,
+	fees ports.FeeRuleRepository,
+	limits ports.LimitRepository,
+	standingOrders ports.StandingOrderRepository,
+	interest ports.InterestRepository,
+	snapshots ports.BalanceSnapshotRepository,
+	idempotency ports.IdempotencyRepository,
+	reconciliation ports.ReconciliationRepository,
+	escrows ports.EscrowRepository,
 	db ports.TransactionManager,
 	locks ports.LockManager,
 	producer ports.MessageProducer,
+	approvalThresholds map[string]int64,
 	log logger.Logger,
 ) TransferServiceIntf {
-	return &TransferService{accounts, txns, asynctxns, db, locks, producer, log}
+	return &TransferService{accounts, txns, asynctxns, ledger, fxrates, holds, fees, limits, standingOrders, interest, snapshots, idempotency, reconciliation, escrows, db, locks, producer, approvalThresholds, log}
 }
 
 // transfer money between two accounts
-func (s *TransferService) Transfer(ctx context.Context, fromAccountID, toAccountID, amount int64) (*TransferResult, error) {
+func (s *TransferService) Transfer(ctx context.Context, fromAccountID, toAccountID, amount int64, details domain.TransferDetails) (*TransferResult, error) {
+	if err := validateTransfer(fromAccountID, toAccountID, amount, details); err != nil {
+		return nil, err
+	}
+	// large transfers must go through RequestTransferApproval instead
+	if err := s.checkApproval(fromAccountID, amount); err != nil {
+		return nil, err
+	}
+	return s.runTransfer(ctx, fromAccountID, toAccountID, amount, details)
+}
+
+// validateTransfer checks the parts of a transfer that need no account
+func validateTransfer(fromAccountID, toAccountID, amount int64, details domain.TransferDetails) error {
 	// check if transfer amount is zero
 	if amount <= 0 {
-		return nil, domain.ErrInvalidAmount
+		return domain.ErrInvalidAmount
+	}
+	if err := details.Validate(); err != nil {
+		return err
 	}
 	// check if souce and destination accounts are same
 	if fromAccountID == toAccountID {
-		return nil, domain.ErrSameAccount
+		return domain.ErrSameAccount
+	}
+	return nil
+}
+
+// runTransfer books a transfer that is already cleared of the approval threshold: it was
+// checked when it was submitted, or approved since
+func (s *TransferService) runTransfer(ctx context.Context, fromAccountID, toAccountID, amount int64, details domain.TransferDetails) (*TransferResult, error) {
+	if err := validateTransfer(fromAccountID, toAccountID, amount, details); err != nil {
+		return nil, err
 	}
 
 	// acquire locks on both accounts to prevent concurrent modifications
-	unlock, err := s.locks.LockAccounts(ctx, []int64{fromAccountID, toAccountID}, 10*time.Second)
+	unlock, err := s.locks.LockAccounts(ctx, []int64{fromAccountID, toAccountID}, lockTTL)
 	if err != nil {
 		return nil, err
 	}
@@ -65,64 +185,189 @@ func (s *TransferService) Transfer(ctx context.Context, fromAccountID, toAccount
 	// Release locks after transfer attempt (success or failure) on function exit
 	defer unlock()
 
-	txID := uuid.New()
+	var rec *domain.Transaction
 	err = s.db.WithTransaction(ctx, func(tx ports.Transaction) error {
+		rec, err = s.transfer(tx, fromAccountID, toAccountID, amount, details)
+		return err
+	})
 
-		// started the transaction and got a transactional context, now get transactional repositories
-		acctRepo := s.accounts.WithTx(tx)
-		txnRepo := s.txns.WithTx(tx)
+	if err != nil {
+		s.log.Error("transfer failed", "err", err)
+		return nil, err
+	}
 
-		from, err := acctRepo.GetByID(fromAccountID)
-		if err != nil {
-			return err
-		}
-		to, err := acctRepo.GetByID(toAccountID)
-		if err != nil {
-			return err
-		}
+	return transferResult(rec), nil
+}
 
-		if err := from.Debit(amount); err != nil {
-			return err
-		}
-		if err := to.Credit(amount); err != nil {
-			return err
-		}
+func transferResult(rec *domain.Transaction) *TransferResult {
+	return &TransferResult{
+		TransactionID:       rec.ID,
+		DestinationAmount:   rec.DestinationAmount,
+		DestinationCurrency: rec.DestinationCurrency,
+		FXRate:              rec.FXRate,
+		FXRateAt:            rec.FXRateAt,
+		Fee:                 rec.Fee,
+		Details:             rec.TransferDetails,
+	}
+}
 
-		rec := &domain.Transaction{
-			ID:                   txID,
-			SourceAccountID:      fromAccountID,
-			DestinationAccountID: toAccountID,
-			Amount:               amount,
-			CreatedAt:            time.Now(),
-		}
-		if err := txnRepo.Create(rec); err != nil {
-			return err
-		}
-		if err := acctRepo.Update(from); err != nil {
-			return err
-		}
-		return acctRepo.Update(to)
-	})
+// transfer moves amount between two accounts inside an open db transaction.
+// Callers must already hold the locks of both accounts.
+func (s *TransferService) transfer(tx ports.Transaction, fromAccountID, toAccountID, amount int64, details domain.TransferDetails) (*domain.Transaction, error) {
+	// started the transaction and got a transactional context, now get transactional repositories
+	acctRepo := s.accounts.WithTx(tx)
 
+	from, err := acctRepo.GetByID(fromAccountID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := acctRepo.GetByID(toAccountID)
 	if err != nil {
-		s.log.Error("transfer failed", "err", err)
 		return nil, err
 	}
 
-	return &TransferResult{TransactionID: txID}, nil
+	rec, err := s.newTransaction(from, to, amount)
+	if err != nil {
+		return nil, err
+	}
+	rec.TransferDetails = details
+	if err := s.checkLimits(tx, from, amount); err != nil {
+		return nil, err
+	}
+	if err := s.applyFee(tx, rec, from); err != nil {
+		return nil, err
+	}
+	if err := s.book(tx, rec, from, to); err != nil {
+		return nil, err
+	}
+	return rec, nil
 }
 
-// create a new account
-func (s *TransferService) CreateAccount(ctx context.Context, id, balance int64) error {
-	if id <= 0 {
-		return domain.ErrInvalidAccountID
+// newTransaction builds the record for moving amount (in the source currency) between
+// two accounts. If the currencies differ, the destination amount is converted with
+// the current rate from the rate table.
+func (s *TransferService) newTransaction(from, to *domain.Account, amount int64) (*domain.Transaction, error) {
+	rec := &domain.Transaction{
+		ID:                   uuid.New(),
+		Kind:                 domain.TxKindTransfer,
+		SourceAccountID:      from.AccountID,
+		DestinationAccountID: to.AccountID,
+		Amount:               amount,
+		Currency:             from.Currency,
+		DestinationAmount:    amount,
+		DestinationCurrency:  to.Currency,
+		CreatedAt:            time.Now(),
+	}
+	if !rec.IsCrossCurrency() {
+		return rec, nil
+	}
+
+	rate, err := s.fxrates.Get(from.Currency, to.Currency)
+	if err != nil {
+		return nil, err
+	}
+	converted, err := s.convert(amount, from.Currency, to.Currency, rate.Rate)
+	if err != nil {
+		return nil, err
 	}
-	if balance < 0 {
+
+	rec.DestinationAmount = converted
+	rec.FXRate = rate.Rate
+	rec.FXRateAt = rate.UpdatedAt
+	return rec, nil
+}
+
+// book applies a transaction to two loaded accounts inside an open db transaction.
+// It writes the transaction record, its balanced ledger postings and the resulting
+// balances, so accounts.balance never moves without a matching pair of entries.
+// A fee is debited from the source together with the amount.
+func (s *TransferService) book(tx ports.Transaction, rec *domain.Transaction, from, to *domain.Account) error {
+	if rec.Fee < 0 || rec.Fee > math.MaxInt64-rec.Amount {
 		return domain.ErrInvalidAmount
 	}
+	if err := from.Debit(rec.Amount + rec.Fee); err != nil {
+		return err
+	}
+	if err := to.Credit(rec.DestinationAmount); err != nil {
+		return err
+	}
+
+	entries := domain.TransferPostings(rec)
+	if !domain.Balanced(entries) {
+		return domain.ErrUnbalancedPostings
+	}
+
+	acctRepo := s.accounts.WithTx(tx)
+	if err := s.txns.WithTx(tx).Create(rec); err != nil {
+		return err
+	}
+	if err := s.ledger.WithTx(tx).Create(entries); err != nil {
+		return err
+	}
+	if err := acctRepo.Update(from); err != nil {
+		return err
+	}
+	if err := acctRepo.Update(to); err != nil {
+		return err
+	}
+	if rec.Fee > 0 {
+		return acctRepo.AddToBalance(rec.FeeAccountID, rec.Fee)
+	}
+	return nil
+}
+
+// maxLabels bounds the number of free-form labels on an account
+const maxLabels = 50
+
+// NewAccount describes an account to open. Type defaults to a customer wallet.
+// Accounts always open at zero; money comes in through Deposit.
+type NewAccount struct {
+	ID          int64
+	Currency    string
+	Type        domain.AccountType
+	OwnerName   string
+	CustomerRef string
+	Labels      map[string]string
+}
+
+// create a new account
+func (s *TransferService) CreateAccount(ctx context.Context, spec NewAccount) (*domain.Account, error) {
+	if spec.ID <= 0 {
+		return nil, domain.ErrInvalidAccountID
+	}
+	cur, err := currency.Lookup(spec.Currency)
+	if err != nil {
+		return nil, domain.ErrUnsupportedCurrency
+	}
+	if spec.Type == "" {
+		spec.Type = domain.AccountTypeCustomer
+	}
+	if !spec.Type.Valid() {
+		return nil, domain.ErrInvalidAccountType
+	}
+	if len(spec.Labels) > maxLabels {
+		return nil, domain.ErrInvalidLabels
+	}
+	for k := range spec.Labels {
+		if strings.TrimSpace(k) == "" {
+			return nil, domain.ErrInvalidLabels
+		}
+	}
 
-	acct := &domain.Account{AccountID: id, Balance: balance}
-	return s.accounts.Create(acct)
+	acct := &domain.Account{
+		AccountID:   spec.ID,
+		Currency:    cur.Code,
+		Type:        spec.Type,
+		OwnerName:   strings.TrimSpace(spec.OwnerName),
+		CustomerRef: strings.TrimSpace(spec.CustomerRef),
+		Labels:      spec.Labels,
+		Status:      domain.AccountActive,
+		CreatedAt:   time.Now(),
+	}
+	if err := s.accounts.Create(acct); err != nil {
+		return nil, err
+	}
+	return acct, nil
 }
 
 // get an account by id
@@ -0,0 +1,83 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/maneeshsagar/tps/internal/core/domain"
+)
+
+func TestCreateAccountValidation(t *testing.T) {
+	tooMany := make(map[string]string, maxLabels+1)
+	for i := 0; i <= maxLabels; i++ {
+		tooMany[fmt.Sprintf("k%d", i)] = "v"
+	}
+	cases := []struct {
+		name string
+		spec NewAccount
+		want error
+	}{
+		{"no id", NewAccount{Currency: "USD"}, domain.ErrInvalidAccountID},
+		{"negative id", NewAccount{ID: -1, Currency: "USD"}, domain.ErrInvalidAccountID},
+		{"unsupported currency", NewAccount{ID: 1, Currency: "XYZ"}, domain.ErrUnsupportedCurrency},
+		{"unknown type", NewAccount{ID: 1, Currency: "USD", Type: "checking"}, domain.ErrInvalidAccountType},
+		{"too many labels", NewAccount{ID: 1, Currency: "USD", Labels: tooMany}, domain.ErrInvalidLabels},
+		{"blank label key", NewAccount{ID: 1, Currency: "USD", Labels: map[string]string{" ": "x"}}, domain.ErrInvalidLabels},
+	}
+
+	for _, tc := range cases {
+		s := &TransferService{accounts: fakeAccounts{byID: map[int64]*domain.Account{}}}
+		if _, err := s.CreateAccount(context.Background(), tc.spec); !errors.Is(err, tc.want) {
+			t.Errorf("%s: CreateAccount = %v, want %v", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestCreateAccountProfile(t *testing.T) {
+	accounts := fakeAccounts{byID: map[int64]*domain.Account{}}
+	s := &TransferService{accounts: accounts}
+	cases := []struct {
+		name string
+		spec NewAccount
+		want domain.Account
+	}{
+		{
+			"defaults to a customer wallet",
+			NewAccount{ID: 1, Currency: "usd"},
+			domain.Account{AccountID: 1, Currency: "USD", Type: domain.AccountTypeCustomer, Status: domain.AccountActive},
+		},
+		{
+			"profile is trimmed",
+			NewAccount{ID: 2, Currency: "INR", Type: domain.AccountTypeMerchant, OwnerName: "  Acme Traders ", CustomerRef: " crm-42 "},
+			domain.Account{AccountID: 2, Currency: "INR", Type: domain.AccountTypeMerchant, OwnerName: "Acme Traders", CustomerRef: "crm-42", Status: domain.AccountActive},
+		},
+		{
+			"system account",
+			NewAccount{ID: 3, Currency: "EUR", Type: domain.AccountTypeSystem, Labels: map[string]string{"purpose": "fees"}},
+			domain.Account{AccountID: 3, Currency: "EUR", Type: domain.AccountTypeSystem, Status: domain.AccountActive},
+		},
+	}
+
+	for _, tc := range cases {
+		acc, err := s.CreateAccount(context.Background(), tc.spec)
+		if err != nil {
+			t.Errorf("%s: CreateAccount error: %v", tc.name, err)
+			continue
+		}
+		if accounts.byID[tc.spec.ID] != acc {
+			t.Errorf("%s: account was not stored", tc.name)
+		}
+		if acc.AccountID != tc.want.AccountID || acc.Currency != tc.want.Currency || acc.Type != tc.want.Type ||
+			acc.OwnerName != tc.want.OwnerName || acc.CustomerRef != tc.want.CustomerRef || acc.Status != tc.want.Status {
+			t.Errorf("%s: CreateAccount = %+v, want %+v", tc.name, *acc, tc.want)
+		}
+		if acc.Balance != 0 || acc.CreatedAt.IsZero() {
+			t.Errorf("%s: balance %d, created at %v, want 0 and set", tc.name, acc.Balance, acc.CreatedAt)
+		}
+		if len(acc.Labels) != len(tc.spec.Labels) {
+			t.Errorf("%s: labels = %v, want %v", tc.name, acc.Labels, tc.spec.Labels)
+		}
+	}
+}
@@ -1,21 +1,266 @@
 package domain
 
-// Account represents a bank account in the domain
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AccountStatus string
+
+const (
+	AccountActive AccountStatus = "active"
+	// AccountFrozen blocks debits, and credits too when the freeze scope is FreezeAll
+	AccountFrozen AccountStatus = "frozen"
+	// AccountClosed is final; a closed account can neither send nor receive
+	AccountClosed AccountStatus = "closed"
+)
+
+// AccountType is what an account is used for; it decides the balance rules
+type AccountType string
+
+const (
+	AccountTypeCustomer AccountType = "customer_wallet"
+	AccountTypeMerchant AccountType = "merchant"
+	// AccountTypeSavings is a customer account that earns interest
+	AccountTypeSavings AccountType = "savings"
+	// AccountTypeSystem is an internal account of the platform, e.g. fee revenue
+	AccountTypeSystem AccountType = "system"
+	// AccountTypeSuspense parks funds that cannot be attributed yet
+	AccountTypeSuspense AccountType = "suspense"
+	// AccountTypeEscrow holds buyers' money for escrows until it is released or refunded
+	AccountTypeEscrow AccountType = "escrow"
+	// AccountTypeTreasury is where money enters and leaves the system: deposits are paid
+	// from it and withdrawals into it, so its negated balance is the money in circulation.
+	// There is at most one per currency.
+	AccountTypeTreasury AccountType = "treasury"
+)
+
+// Valid reports whether t is a known account type
+func (t AccountType) Valid() bool {
+	switch t {
+	case AccountTypeCustomer, AccountTypeMerchant, AccountTypeSavings, AccountTypeSystem, AccountTypeSuspense,
+		AccountTypeEscrow, AccountTypeTreasury:
+		return true
+	}
+	return false
+}
+
+// AllowsNegative reports whether accounts of this type may be debited below zero.
+// Only internal accounts may; customer and merchant funds must always be covered.
+func (t AccountType) AllowsNegative() bool {
+	return t == AccountTypeSystem || t == AccountTypeSuspense || t == AccountTypeTreasury
+}
+
+// FreezeScope is what a frozen account is blocked from doing
+type FreezeScope string
+
+const (
+	FreezeDebits FreezeScope = "debits"
+	FreezeAll    FreezeScope = "all"
+)
+
+// Account represents a bank account in the domain.
+// Balance is the ledger balance, materialized from the ledger: it always equals
+// OpeningBalance plus the net of all ledger entries posted against the account.
+// Accounts open at zero and are funded by deposits; only accounts opened before
+// funding went through the treasury have an OpeningBalance.
+// HeldBalance is the total of active holds, which is reserved but not yet moved.
+// BalancePolicy bounds how far debits and credits may move the balance.
 type Account struct {
-	AccountID int64
-	Balance   int64
+	AccountID      int64
+	Currency       string
+	Balance        int64
+	OpeningBalance int64
+	HeldBalance    int64
+	Type           AccountType
+	OwnerName      string
+	CustomerRef    string
+	Labels         map[string]string
+	Status         AccountStatus
+	FreezeScope    FreezeScope
+	StatusReason   string
+	BalancePolicy
+	CreatedAt time.Time
+}
+
+// BalancePolicy bounds the balance of an account. OverdraftLimit lets debits take the
+// balance down to -OverdraftLimit, MinimumBalance is kept untouched by debits and
+// MaximumBalance caps the balance credits can reach, e.g. for a KYC tier. A zero
+// field leaves that bound off; an account has an overdraft or a minimum, not both.
+type BalancePolicy struct {
+	OverdraftLimit int64
+	MinimumBalance int64
+	MaximumBalance int64
+}
+
+func (p BalancePolicy) Validate() error {
+	if p.OverdraftLimit < 0 || p.MinimumBalance < 0 || p.MaximumBalance < 0 {
+		return ErrInvalidBalancePolicy
+	}
+	if p.OverdraftLimit > 0 && p.MinimumBalance > 0 {
+		return ErrInvalidBalancePolicy
+	}
+	if p.MaximumBalance > 0 && p.MaximumBalance < p.MinimumBalance {
+		return ErrInvalidBalancePolicy
+	}
+	return nil
+}
+
+// floor is the lowest balance debits may leave
+func (p BalancePolicy) floor() int64 {
+	if p.MinimumBalance > 0 {
+		return p.MinimumBalance
+	}
+	return -p.OverdraftLimit
+}
+
+// CanSend reports whether the account's status allows money to leave it
+func (a *Account) CanSend() error {
+	switch a.Status {
+	case AccountClosed:
+		return ErrAccountClosed
+	case AccountFrozen:
+		return ErrAccountFrozen
+	}
+	return nil
+}
+
+// CanReceive reports whether the account's status allows money to come in
+func (a *Account) CanReceive() error {
+	switch {
+	case a.Status == AccountClosed:
+		return ErrAccountClosed
+	case a.Status == AccountFrozen && a.FreezeScope == FreezeAll:
+		return ErrAccountFrozen
+	}
+	return nil
+}
+
+// ChangeStatus moves the account to a new status. Closing needs a zero ledger
+// balance and no active holds, and a closed account stays closed.
+func (a *Account) ChangeStatus(status AccountStatus, scope FreezeScope, reason string) error {
+	if a.Status == AccountClosed {
+		return ErrInvalidStatusChange
+	}
+	switch status {
+	case AccountActive:
+		if a.Status == AccountActive {
+			return ErrInvalidStatusChange
+		}
+		scope = ""
+	case AccountFrozen:
+		if scope == "" {
+			scope = FreezeDebits
+		}
+		if scope != FreezeDebits && scope != FreezeAll {
+			return ErrInvalidStatusChange
+		}
+		if a.Status == AccountFrozen && a.FreezeScope == scope {
+			return ErrInvalidStatusChange
+		}
+	case AccountClosed:
+		if a.Balance != 0 || a.HeldBalance != 0 {
+			return ErrAccountNotEmpty
+		}
+		scope = ""
+	default:
+		return ErrInvalidStatusChange
+	}
+
+	a.Status = status
+	a.FreezeScope = scope
+	a.StatusReason = reason
+	return nil
+}
+
+// AvailableBalance is the part of the ledger balance not reserved by holds
+func (a *Account) AvailableBalance() int64 {
+	return a.Balance - a.HeldBalance
+}
+
+// AvailableToSpend is what debits may take: the available balance above the minimum
+// balance, plus the overdraft. It is never negative, and is unbounded for account
+// types that allow a negative balance.
+func (a *Account) AvailableToSpend() int64 {
+	if a.Type.AllowsNegative() {
+		return math.MaxInt64
+	}
+	if spend := a.AvailableBalance() - a.floor(); spend > 0 {
+		return spend
+	}
+	return 0
+}
+
+// CanDebit reports why amount cannot be debited, naming the bound of the balance
+// policy that would be broken
+func (a *Account) CanDebit(amount int64) error {
+	switch {
+	case amount <= a.AvailableToSpend():
+		return nil
+	case a.OverdraftLimit > 0:
+		return ErrOverdraftExceeded
+	case a.MinimumBalance > 0 && amount <= a.AvailableBalance():
+		return ErrMinimumBalance
+	}
+	return ErrInsufficientBalance
+}
+
+// CanCredit reports whether amount can be credited without going over the maximum balance
+func (a *Account) CanCredit(amount int64) error {
+	if a.MaximumBalance > 0 && amount > a.MaximumBalance-a.Balance {
+		return ErrMaximumBalance
+	}
+	return nil
+}
+
+// SetBalancePolicy replaces the balance policy. An account already overdrawn keeps
+// at least the overdraft it is using.
+func (a *Account) SetBalancePolicy(p BalancePolicy) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	if !a.Type.AllowsNegative() && a.Balance < -p.OverdraftLimit {
+		return ErrInvalidBalancePolicy
+	}
+	a.BalancePolicy = p
+	return nil
+}
+
+// Reserve places a hold of amount on the available balance
+func (a *Account) Reserve(amount int64) error {
+	if amount <= 0 {
+		return ErrInvalidAmount
+	}
+	if err := a.CanSend(); err != nil {
+		return err
+	}
+	if err := a.CanDebit(amount); err != nil {
+		return err
+	}
+	a.HeldBalance += amount
+	return nil
 }
 
-func (a *Account) CanDebit(amount int64) bool {
-	return a.Balance >= amount
+// Release returns a previously reserved amount to the available balance
+func (a *Account) Release(amount int64) error {
+	if amount <= 0 || amount > a.HeldBalance {
+		return ErrInvalidAmount
+	}
+	a.HeldBalance -= amount
+	return nil
 }
 
 func (a *Account) Debit(amount int64) error {
 	if amount <= 0 {
 		return ErrInvalidAmount
 	}
-	if !a.CanDebit(amount) {
-		return ErrInsufficientBalance
+	if err := a.CanSend(); err != nil {
+		return err
+	}
+	if err := a.CanDebit(amount); err != nil {
+		return err
 	}
 	a.Balance -= amount
 	return nil
@@ -25,6 +270,23 @@ func (a *Account) Credit(amount int64) error {
 	if amount <= 0 {
 		return ErrInvalidAmount
 	}
+	if err := a.CanReceive(); err != nil {
+		return err
+	}
+	if err := a.CanCredit(amount); err != nil {
+		return err
+	}
 	a.Balance += amount
 	return nil
 }
+
+// AccountStatusChange records a status change of an account and why it was made
+type AccountStatusChange struct {
+	ID          uuid.UUID
+	AccountID   int64
+	From        AccountStatus
+	To          AccountStatus
+	FreezeScope FreezeScope
+	Reason      string
+	CreatedAt   time.Time
+}
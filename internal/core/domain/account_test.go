@@ -0,0 +1,308 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestAccountCanDebit(t *testing.T) {
+	cases := []struct {
+		name    string
+		account Account
+		amount  int64
+		want    error
+	}{
+		{"covered", Account{Balance: 1000}, 1000, nil},
+		{"insufficient", Account{Balance: 1000}, 1001, ErrInsufficientBalance},
+		{"holds reduce what can be spent", Account{Balance: 1000, HeldBalance: 400}, 601, ErrInsufficientBalance},
+		{"within overdraft", Account{Balance: 100, BalancePolicy: BalancePolicy{OverdraftLimit: 500}}, 600, nil},
+		{"overdraft exceeded", Account{Balance: 100, BalancePolicy: BalancePolicy{OverdraftLimit: 500}}, 601, ErrOverdraftExceeded},
+		{"overdraft already in use", Account{Balance: -300, BalancePolicy: BalancePolicy{OverdraftLimit: 500}}, 201, ErrOverdraftExceeded},
+		{"above minimum", Account{Balance: 1000, BalancePolicy: BalancePolicy{MinimumBalance: 200}}, 800, nil},
+		{"would break minimum", Account{Balance: 1000, BalancePolicy: BalancePolicy{MinimumBalance: 200}}, 801, ErrMinimumBalance},
+		{"more than the balance with a minimum", Account{Balance: 1000, BalancePolicy: BalancePolicy{MinimumBalance: 200}}, 1001, ErrInsufficientBalance},
+		{"already below minimum", Account{Balance: 100, BalancePolicy: BalancePolicy{MinimumBalance: 200}}, 1, ErrMinimumBalance},
+		{"system account goes negative", Account{Type: AccountTypeSystem}, 5000, nil},
+		{"treasury goes negative", Account{Type: AccountTypeTreasury, Balance: -100}, 5000, nil},
+	}
+
+	for _, tc := range cases {
+		if err := tc.account.CanDebit(tc.amount); !errors.Is(err, tc.want) {
+			t.Errorf("%s: CanDebit(%d) = %v, want %v", tc.name, tc.amount, err, tc.want)
+		}
+	}
+}
+
+func TestAccountAvailableToSpend(t *testing.T) {
+	cases := []struct {
+		name    string
+		account Account
+		want    int64
+	}{
+		{"plain", Account{Balance: 1000, HeldBalance: 250}, 750},
+		{"overdraft adds to it", Account{Balance: 1000, BalancePolicy: BalancePolicy{OverdraftLimit: 500}}, 1500},
+		{"overdrawn", Account{Balance: -200, BalancePolicy: BalancePolicy{OverdraftLimit: 500}}, 300},
+		{"minimum is kept back", Account{Balance: 1000, HeldBalance: 100, BalancePolicy: BalancePolicy{MinimumBalance: 200}}, 700},
+		{"never negative", Account{Balance: 100, BalancePolicy: BalancePolicy{MinimumBalance: 200}}, 0},
+		{"suspense is unbounded", Account{Type: AccountTypeSuspense}, math.MaxInt64},
+	}
+
+	for _, tc := range cases {
+		if got := tc.account.AvailableToSpend(); got != tc.want {
+			t.Errorf("%s: AvailableToSpend() = %d, want %d", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestAccountCredit(t *testing.T) {
+	cases := []struct {
+		name    string
+		account Account
+		amount  int64
+		want    error
+	}{
+		{"no maximum", Account{Balance: 1000}, 1 << 40, nil},
+		{"up to the maximum", Account{Balance: 1000, BalancePolicy: BalancePolicy{MaximumBalance: 5000}}, 4000, nil},
+		{"over the maximum", Account{Balance: 1000, BalancePolicy: BalancePolicy{MaximumBalance: 5000}}, 4001, ErrMaximumBalance},
+		{"already at the maximum", Account{Balance: 5000, BalancePolicy: BalancePolicy{MaximumBalance: 5000}}, 1, ErrMaximumBalance},
+		{"overdrawn below the maximum", Account{Balance: -500, BalancePolicy: BalancePolicy{OverdraftLimit: 500, MaximumBalance: 5000}}, 5500, nil},
+		{"zero", Account{}, 0, ErrInvalidAmount},
+		{"frozen for everything", Account{Status: AccountFrozen, FreezeScope: FreezeAll}, 1, ErrAccountFrozen},
+		{"frozen for debits only", Account{Status: AccountFrozen, FreezeScope: FreezeDebits}, 1, nil},
+	}
+
+	for _, tc := range cases {
+		before := tc.account.Balance
+		err := tc.account.Credit(tc.amount)
+		if !errors.Is(err, tc.want) {
+			t.Errorf("%s: Credit(%d) = %v, want %v", tc.name, tc.amount, err, tc.want)
+			continue
+		}
+		want := before
+		if err == nil {
+			want += tc.amount
+		}
+		if tc.account.Balance != want {
+			t.Errorf("%s: balance = %d, want %d", tc.name, tc.account.Balance, want)
+		}
+	}
+}
+
+func TestAccountSetBalancePolicy(t *testing.T) {
+	cases := []struct {
+		name    string
+		account Account
+		policy  BalancePolicy
+		want    error
+	}{
+		{"overdraft", Account{Balance: 100}, BalancePolicy{OverdraftLimit: 500}, nil},
+		{"minimum and maximum", Account{Balance: 100}, BalancePolicy{MinimumBalance: 200, MaximumBalance: 1000}, nil},
+		{"all off", Account{Balance: 100, BalancePolicy: BalancePolicy{OverdraftLimit: 500}}, BalancePolicy{}, nil},
+		{"negative field", Account{}, BalancePolicy{OverdraftLimit: -1}, ErrInvalidBalancePolicy},
+		{"overdraft and minimum", Account{}, BalancePolicy{OverdraftLimit: 100, MinimumBalance: 100}, ErrInvalidBalancePolicy},
+		{"maximum below minimum", Account{}, BalancePolicy{MinimumBalance: 500, MaximumBalance: 400}, ErrInvalidBalancePolicy},
+		{"keeps the overdraft in use", Account{Balance: -300, BalancePolicy: BalancePolicy{OverdraftLimit: 500}}, BalancePolicy{OverdraftLimit: 300}, nil},
+		{"overdraft below the debt", Account{Balance: -300, BalancePolicy: BalancePolicy{OverdraftLimit: 500}}, BalancePolicy{OverdraftLimit: 299}, ErrInvalidBalancePolicy},
+		{"internal account may stay negative", Account{Type: AccountTypeSystem, Balance: -300}, BalancePolicy{}, nil},
+	}
+
+	for _, tc := range cases {
+		before := tc.account.BalancePolicy
+		err := tc.account.SetBalancePolicy(tc.policy)
+		if !errors.Is(err, tc.want) {
+			t.Errorf("%s: SetBalancePolicy(%+v) = %v, want %v", tc.name, tc.policy, err, tc.want)
+			continue
+		}
+		want := before
+		if err == nil {
+			want = tc.policy
+		}
+		if tc.account.BalancePolicy != want {
+			t.Errorf("%s: policy = %+v, want %+v", tc.name, tc.account.BalancePolicy, want)
+		}
+	}
+}
+
+func TestAccountReserve(t *testing.T) {
+	cases := []struct {
+		name          string
+		account       Account
+		amount        int64
+		wantErr       error
+		wantHeld      int64
+		wantAvailable int64
+	}{
+		{"within available", Account{Balance: 1000}, 400, nil, 400, 600},
+		{"whole balance", Account{Balance: 1000}, 1000, nil, 1000, 0},
+		{"on top of a hold", Account{Balance: 1000, HeldBalance: 300}, 700, nil, 1000, 0},
+		{"more than available", Account{Balance: 1000, HeldBalance: 300}, 701, ErrInsufficientBalance, 300, 700},
+		{"into the overdraft", Account{Balance: 100, BalancePolicy: BalancePolicy{OverdraftLimit: 500}}, 600, nil, 600, -500},
+		{"breaks the minimum", Account{Balance: 1000, BalancePolicy: BalancePolicy{MinimumBalance: 200}}, 801, ErrMinimumBalance, 0, 1000},
+		{"zero", Account{Balance: 1000}, 0, ErrInvalidAmount, 0, 1000},
+		{"negative", Account{Balance: 1000}, -1, ErrInvalidAmount, 0, 1000},
+		{"frozen for debits", Account{Balance: 1000, Status: AccountFrozen, FreezeScope: FreezeDebits}, 1, ErrAccountFrozen, 0, 1000},
+		{"closed", Account{Status: AccountClosed}, 1, ErrAccountClosed, 0, 0},
+	}
+
+	for _, tc := range cases {
+		a := tc.account
+		if err := a.Reserve(tc.amount); !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: Reserve(%d) = %v, want %v", tc.name, tc.amount, err, tc.wantErr)
+			continue
+		}
+		if a.HeldBalance != tc.wantHeld || a.AvailableBalance() != tc.wantAvailable {
+			t.Errorf("%s: held %d, available %d, want %d, %d", tc.name, a.HeldBalance, a.AvailableBalance(), tc.wantHeld, tc.wantAvailable)
+		}
+		if a.Balance != tc.account.Balance {
+			t.Errorf("%s: Reserve moved the balance to %d", tc.name, a.Balance)
+		}
+	}
+}
+
+func TestAccountRelease(t *testing.T) {
+	cases := []struct {
+		name          string
+		held          int64
+		amount        int64
+		wantErr       error
+		wantHeld      int64
+		wantAvailable int64
+	}{
+		{"part of the hold", 400, 150, nil, 250, 750},
+		{"whole hold", 400, 400, nil, 0, 1000},
+		{"more than held", 400, 401, ErrInvalidAmount, 400, 600},
+		{"nothing held", 0, 1, ErrInvalidAmount, 0, 1000},
+		{"zero", 400, 0, ErrInvalidAmount, 400, 600},
+	}
+
+	for _, tc := range cases {
+		a := Account{Balance: 1000, HeldBalance: tc.held}
+		if err := a.Release(tc.amount); !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: Release(%d) = %v, want %v", tc.name, tc.amount, err, tc.wantErr)
+			continue
+		}
+		if a.HeldBalance != tc.wantHeld || a.AvailableBalance() != tc.wantAvailable {
+			t.Errorf("%s: held %d, available %d, want %d, %d", tc.name, a.HeldBalance, a.AvailableBalance(), tc.wantHeld, tc.wantAvailable)
+		}
+	}
+}
+
+func TestAccountReserveThenRelease(t *testing.T) {
+	a := Account{Balance: 1000}
+	if err := a.Reserve(600); err != nil {
+		t.Fatalf("Reserve: %v", err)
+	}
+	if err := a.Debit(401); !errors.Is(err, ErrInsufficientBalance) {
+		t.Errorf("Debit past the hold = %v, want %v", err, ErrInsufficientBalance)
+	}
+	if err := a.Release(600); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if a.AvailableBalance() != 1000 || a.HeldBalance != 0 {
+		t.Errorf("after release: available %d, held %d, want 1000, 0", a.AvailableBalance(), a.HeldBalance)
+	}
+}
+
+func TestAccountChangeStatus(t *testing.T) {
+	active := Account{Status: AccountActive}
+	frozenDebits := Account{Status: AccountFrozen, FreezeScope: FreezeDebits}
+	frozenAll := Account{Status: AccountFrozen, FreezeScope: FreezeAll}
+	closed := Account{Status: AccountClosed}
+
+	cases := []struct {
+		name      string
+		account   Account
+		status    AccountStatus
+		scope     FreezeScope
+		wantErr   error
+		wantScope FreezeScope
+	}{
+		{"freeze defaults to debits", active, AccountFrozen, "", nil, FreezeDebits},
+		{"freeze everything", active, AccountFrozen, FreezeAll, nil, FreezeAll},
+		{"widen a freeze", frozenDebits, AccountFrozen, FreezeAll, nil, FreezeAll},
+		{"narrow a freeze", frozenAll, AccountFrozen, FreezeDebits, nil, FreezeDebits},
+		{"same freeze again", frozenDebits, AccountFrozen, FreezeDebits, ErrInvalidStatusChange, FreezeDebits},
+		{"unknown scope", active, AccountFrozen, "credits", ErrInvalidStatusChange, ""},
+		{"unfreeze", frozenAll, AccountActive, FreezeAll, nil, ""},
+		{"activate an active account", active, AccountActive, "", ErrInvalidStatusChange, ""},
+		{"close an empty account", active, AccountClosed, "", nil, ""},
+		{"close a frozen empty account", frozenAll, AccountClosed, "", nil, ""},
+		{"close with a balance", Account{Status: AccountActive, Balance: 1}, AccountClosed, "", ErrAccountNotEmpty, ""},
+		{"close overdrawn", Account{Status: AccountActive, Balance: -1}, AccountClosed, "", ErrAccountNotEmpty, ""},
+		{"close with a hold", Account{Status: AccountActive, HeldBalance: 1}, AccountClosed, "", ErrAccountNotEmpty, ""},
+		{"reopen a closed account", closed, AccountActive, "", ErrInvalidStatusChange, ""},
+		{"freeze a closed account", closed, AccountFrozen, FreezeAll, ErrInvalidStatusChange, ""},
+		{"close a closed account", closed, AccountClosed, "", ErrInvalidStatusChange, ""},
+		{"unknown status", active, "dormant", "", ErrInvalidStatusChange, ""},
+	}
+
+	for _, tc := range cases {
+		a := tc.account
+		err := a.ChangeStatus(tc.status, tc.scope, "review")
+		if !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: ChangeStatus = %v, want %v", tc.name, err, tc.wantErr)
+			continue
+		}
+		if err != nil {
+			if a.Status != tc.account.Status || a.FreezeScope != tc.account.FreezeScope {
+				t.Errorf("%s: refused change left %s/%s", tc.name, a.Status, a.FreezeScope)
+			}
+			continue
+		}
+		if a.Status != tc.status || a.FreezeScope != tc.wantScope || a.StatusReason != "review" {
+			t.Errorf("%s: got %s/%s %q, want %s/%s", tc.name, a.Status, a.FreezeScope, a.StatusReason, tc.status, tc.wantScope)
+		}
+	}
+}
+
+func TestAccountCanSendAndReceive(t *testing.T) {
+	cases := []struct {
+		name        string
+		account     Account
+		wantSend    error
+		wantReceive error
+	}{
+		{"active", Account{Status: AccountActive}, nil, nil},
+		{"frozen for debits", Account{Status: AccountFrozen, FreezeScope: FreezeDebits}, ErrAccountFrozen, nil},
+		{"frozen for everything", Account{Status: AccountFrozen, FreezeScope: FreezeAll}, ErrAccountFrozen, ErrAccountFrozen},
+		{"closed", Account{Status: AccountClosed}, ErrAccountClosed, ErrAccountClosed},
+	}
+
+	for _, tc := range cases {
+		if err := tc.account.CanSend(); !errors.Is(err, tc.wantSend) {
+			t.Errorf("%s: CanSend = %v, want %v", tc.name, err, tc.wantSend)
+		}
+		if err := tc.account.CanReceive(); !errors.Is(err, tc.wantReceive) {
+			t.Errorf("%s: CanReceive = %v, want %v", tc.name, err, tc.wantReceive)
+		}
+	}
+}
+
+func TestAccountType(t *testing.T) {
+	cases := []struct {
+		accountType    AccountType
+		valid          bool
+		allowsNegative bool
+	}{
+		{AccountTypeCustomer, true, false},
+		{AccountTypeMerchant, true, false},
+		{AccountTypeSavings, true, false},
+		{AccountTypeEscrow, true, false},
+		{AccountTypeSystem, true, true},
+		{AccountTypeSuspense, true, true},
+		{AccountTypeTreasury, true, true},
+		{"", false, false},
+		{"checking", false, false},
+		{"SYSTEM", false, false},
+	}
+
+	for _, tc := range cases {
+		if got := tc.accountType.Valid(); got != tc.valid {
+			t.Errorf("%q: Valid = %v, want %v", tc.accountType, got, tc.valid)
+		}
+		if got := tc.accountType.AllowsNegative(); got != tc.allowsNegative {
+			t.Errorf("%q: AllowsNegative = %v, want %v", tc.accountType, got, tc.allowsNegative)
+		}
+	}
+}
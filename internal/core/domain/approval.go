@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+type ApprovalDecision string
+
+const (
+	ApprovalApproved ApprovalDecision = "approved"
+	ApprovalRejected ApprovalDecision = "rejected"
+)
+
+// Approval is the maker-checker record of a transfer held for approval. RequestedAt is
+// zero for transfers that never needed one, and RequestedBy is empty when the transfer
+// was not submitted by an operator.
+type Approval struct {
+	RequestedBy string
+	RequestedAt time.Time
+	Decision    ApprovalDecision
+	DecidedBy   string
+	DecidedAt   time.Time
+	Reason      string
+}
+
+// NeedsApproval reports whether a transfer of amount has to be approved before it
+// runs. thresholds maps a currency code to the largest amount, in minor units, that
+// runs without approval; currencies without a threshold never need one.
+func NeedsApproval(thresholds map[string]int64, currency string, amount int64) bool {
+	threshold, ok := thresholds[currency]
+	return ok && amount > threshold
+}
+
+// Decide approves or rejects a transfer pending approval. The checker must not be the
+// operator who requested it, so a transfer with no requesting operator can only be
+// rejected. An approved transfer goes on as scheduled if its
+// execution time is still ahead, or pending to be queued now.
+func (t *AsyncTransaction) Decide(decision ApprovalDecision, operator, reason string, now time.Time) error {
+	operator = strings.TrimSpace(operator)
+	if operator == "" {
+		return ErrInvalidOperator
+	}
+	if t.Status != TxStatusPendingApproval {
+		return ErrNotPendingApproval
+	}
+	if decision == ApprovalApproved && strings.TrimSpace(t.RequestedBy) == "" {
+		return ErrUnknownRequester
+	}
+	if operator == t.RequestedBy {
+		return ErrSameOperator
+	}
+
+	switch decision {
+	case ApprovalApproved:
+		t.Status = TxStatusPending
+		if t.ExecuteAt.After(now) {
+			t.Status = TxStatusScheduled
+		}
+	case ApprovalRejected:
+		t.Status = TxStatusRejected
+	default:
+		return ErrInvalidStatusChange
+	}
+	t.Decision = decision
+	t.DecidedBy = operator
+	t.DecidedAt = now
+	t.Reason = strings.TrimSpace(reason)
+	t.UpdatedAt = now
+	return nil
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNeedsApproval(t *testing.T) {
+	thresholds := map[string]int64{"USD": 10000, "INR": 0}
+	cases := []struct {
+		currency string
+		amount   int64
+		want     bool
+	}{
+		{"USD", 9999, false},
+		{"USD", 10000, false},
+		{"USD", 10001, true},
+		{"INR", 1, true},
+		{"EUR", 1 << 40, false},
+	}
+
+	for _, tc := range cases {
+		if got := NeedsApproval(thresholds, tc.currency, tc.amount); got != tc.want {
+			t.Errorf("NeedsApproval(%s, %d) = %v, want %v", tc.currency, tc.amount, got, tc.want)
+		}
+	}
+	if NeedsApproval(nil, "USD", 1<<40) {
+		t.Errorf("NeedsApproval without thresholds = true, want false")
+	}
+}
+
+func TestAsyncTransactionDecide(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name        string
+		status      TxStatus
+		requestedBy string
+		executeAt   time.Time
+		decision    ApprovalDecision
+		operator    string
+		wantErr     error
+		wantStatus  TxStatus
+	}{
+		{"approved runs now", TxStatusPendingApproval, "alice", time.Time{}, ApprovalApproved, "bob", nil, TxStatusPending},
+		{"approved before execute_at", TxStatusPendingApproval, "alice", now.Add(time.Hour), ApprovalApproved, "bob", nil, TxStatusScheduled},
+		{"approved after execute_at", TxStatusPendingApproval, "alice", now.Add(-time.Hour), ApprovalApproved, "bob", nil, TxStatusPending},
+		{"rejected", TxStatusPendingApproval, "alice", time.Time{}, ApprovalRejected, "bob", nil, TxStatusRejected},
+		{"maker approves", TxStatusPendingApproval, "alice", time.Time{}, ApprovalApproved, "alice", ErrSameOperator, TxStatusPendingApproval},
+		{"maker approves with padding", TxStatusPendingApproval, "alice", time.Time{}, ApprovalApproved, " alice ", ErrSameOperator, TxStatusPendingApproval},
+		{"maker rejects", TxStatusPendingApproval, "alice", time.Time{}, ApprovalRejected, "alice", ErrSameOperator, TxStatusPendingApproval},
+		{"no maker to approve", TxStatusPendingApproval, "", time.Time{}, ApprovalApproved, "bob", ErrUnknownRequester, TxStatusPendingApproval},
+		{"no maker can be rejected", TxStatusPendingApproval, "", time.Time{}, ApprovalRejected, "bob", nil, TxStatusRejected},
+		{"no checker", TxStatusPendingApproval, "alice", time.Time{}, ApprovalApproved, "  ", ErrInvalidOperator, TxStatusPendingApproval},
+		{"already approved", TxStatusPending, "alice", time.Time{}, ApprovalApproved, "bob", ErrNotPendingApproval, TxStatusPending},
+		{"already rejected", TxStatusRejected, "alice", time.Time{}, ApprovalApproved, "bob", ErrNotPendingApproval, TxStatusRejected},
+		{"unknown decision", TxStatusPendingApproval, "alice", time.Time{}, "maybe", "bob", ErrInvalidStatusChange, TxStatusPendingApproval},
+	}
+
+	for _, tc := range cases {
+		tx := &AsyncTransaction{Status: tc.status, ExecuteAt: tc.executeAt, Approval: Approval{RequestedBy: tc.requestedBy}}
+		err := tx.Decide(tc.decision, tc.operator, " checked ", now)
+		if !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: Decide = %v, want %v", tc.name, err, tc.wantErr)
+			continue
+		}
+		if tx.Status != tc.wantStatus {
+			t.Errorf("%s: status = %s, want %s", tc.name, tx.Status, tc.wantStatus)
+		}
+		if err != nil {
+			if tx.DecidedBy != "" || !tx.DecidedAt.IsZero() {
+				t.Errorf("%s: refused decision recorded by %q at %v", tc.name, tx.DecidedBy, tx.DecidedAt)
+			}
+			continue
+		}
+		if tx.Decision != tc.decision || tx.DecidedBy != "bob" || !tx.DecidedAt.Equal(now) || tx.Reason != "checked" {
+			t.Errorf("%s: recorded %s by %q at %v (%q)", tc.name, tx.Decision, tx.DecidedBy, tx.DecidedAt, tx.Reason)
+		}
+	}
+}
@@ -12,6 +12,17 @@ const (
 	TxStatusPending   TxStatus = "pending"
 	TxStatusCompleted TxStatus = "completed"
 	TxStatusFailed    TxStatus = "failed"
+
+	// scheduled transfers wait for ExecuteAt, then move to executing once handed to the
+	// queue and finish as completed or failed like any other async transfer
+	TxStatusScheduled TxStatus = "scheduled"
+	TxStatusExecuting TxStatus = "executing"
+	TxStatusCancelled TxStatus = "cancelled"
+
+	// transfers above the approval threshold wait in pending_approval until an operator
+	// approves them, then carry on as pending or scheduled, or end as rejected
+	TxStatusPendingApproval TxStatus = "pending_approval"
+	TxStatusRejected        TxStatus = "rejected"
 )
 
 type AsyncTransaction struct {
@@ -19,8 +30,14 @@ type AsyncTransaction struct {
 	FromAccount int64
 	ToAccount   int64
 	Amount      int64
+	Currency    string
 	Status      TxStatus
 	Error       string
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	TransferDetails
+	Approval
+	// TransactionID is the completed transaction, once the transfer has gone through
+	TransactionID uuid.UUID
+	ExecuteAt     time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
 }
@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// BalanceSnapshot is the ledger balance of an account at a point in time, with every
+// entry posted before At. Snapshots are taken daily so a past balance is rebuilt from
+// the nearest one rather than from the account's whole ledger.
+type BalanceSnapshot struct {
+	AccountID int64
+	Currency  string
+	Balance   int64
+	At        time.Time
+	CreatedAt time.Time
+}
@@ -11,4 +11,59 @@ var (
 	ErrSameAccount           = errors.New("same account")
 	ErrLockAcquisitionFailed = errors.New("lock acquisition failed")
 	ErrTransactionNotFound   = errors.New("transaction not found")
+	ErrUnbalancedPostings    = errors.New("unbalanced ledger postings")
+	ErrUnsupportedCurrency   = errors.New("unsupported currency")
+	ErrCurrencyMismatch      = errors.New("currency mismatch")
+	ErrFXRateNotFound        = errors.New("fx rate not found")
+	ErrInvalidFXRate         = errors.New("invalid fx rate")
+	ErrHoldNotFound          = errors.New("hold not found")
+	ErrHoldNotActive         = errors.New("hold not active")
+	ErrHoldExpired           = errors.New("hold expired")
+	ErrHoldAmountExceeded    = errors.New("capture exceeds held amount")
+	ErrNotReversible         = errors.New("transaction cannot be reversed")
+	ErrReversalExceeded      = errors.New("reversal exceeds original amount")
+	ErrInvalidExecuteAt      = errors.New("execute_at must be in the future")
+	ErrNotCancellable        = errors.New("transaction cannot be cancelled")
+	ErrStandingOrderNotFound = errors.New("standing order not found")
+	ErrInvalidSchedule       = errors.New("invalid schedule")
+	ErrInvalidStatusChange   = errors.New("invalid status change")
+	ErrInvalidBatch          = errors.New("batch must have between 1 and 100 legs")
+	ErrInvalidSplit          = errors.New("invalid split")
+	ErrAccountFrozen         = errors.New("account frozen")
+	ErrAccountClosed         = errors.New("account closed")
+	ErrAccountNotEmpty       = errors.New("account balance must be zero to close")
+	ErrInvalidAccountType    = errors.New("invalid account type")
+	ErrInvalidLabels         = errors.New("invalid labels")
+	ErrFeeRuleNotFound       = errors.New("fee rule not found")
+	ErrInvalidFeeRule        = errors.New("invalid fee rule")
+	ErrLimitExceeded         = errors.New("limit exceeded")
+	ErrLimitNotFound         = errors.New("limit not found")
+	ErrInvalidLimit          = errors.New("invalid limit")
+	ErrSnapshotNotFound      = errors.New("balance snapshot not found")
+	ErrInvalidAsOf           = errors.New("as_of must not be in the future")
+	ErrInvalidStatementRange = errors.New("from must be before to")
+	ErrInvalidFilter         = errors.New("invalid filter")
+	ErrInvalidIdempotencyKey = errors.New("invalid idempotency key")
+	ErrIdempotencyMismatch   = errors.New("idempotency key already used with a different request")
+	ErrIdempotencyInProgress = errors.New("a request with this idempotency key is in progress")
+	ErrIdempotencyNotFound   = errors.New("idempotency key not found")
+	ErrInvalidDetails        = errors.New("invalid description, external_ref or metadata")
+	ErrDuplicateRef          = errors.New("external_ref already used for a transfer from this account")
+	ErrTreasuryNotFound      = errors.New("no treasury account for this currency")
+	ErrTreasuryExists        = errors.New("a treasury account already exists for this currency")
+	ErrEscrowNotFound        = errors.New("escrow not found")
+	ErrEscrowNotOpen         = errors.New("escrow is not open")
+	ErrEscrowAmountExceeded  = errors.New("settlement exceeds the amount in escrow")
+	ErrInvalidEscrowParty    = errors.New("escrow buyer, seller and arbiter must be set and distinct")
+	ErrNotEscrowParty        = errors.New("caller is not allowed to do this on the escrow")
+	ErrOverdraftExceeded     = errors.New("overdraft limit exceeded")
+	ErrMinimumBalance        = errors.New("debit would breach the minimum balance")
+	ErrMaximumBalance        = errors.New("credit would exceed the maximum balance")
+	ErrInvalidBalancePolicy  = errors.New("invalid balance policy")
+	ErrNotPendingApproval    = errors.New("transfer is not pending approval")
+	ErrSameOperator          = errors.New("a transfer must be decided by another operator than the one who requested it")
+	ErrInvalidOperator       = errors.New("operator id is required")
+	ErrApprovalRequired      = errors.New("amount is above the approval threshold; only single transfers can be submitted for approval")
+	ErrOperatorRequired      = errors.New("transfers above the approval threshold must be requested by an operator")
+	ErrUnknownRequester      = errors.New("transfer has no requesting operator to check the approver against")
 )
@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EscrowStatus string
+
+const (
+	// EscrowFunded holds the buyer's money in the escrow account until it is settled
+	EscrowFunded EscrowStatus = "funded"
+	// EscrowDisputed is contested by a party: it can still be settled, but no longer expires
+	EscrowDisputed EscrowStatus = "disputed"
+	// EscrowReleased is fully settled with at least part of it paid to the seller
+	EscrowReleased EscrowStatus = "released"
+	// EscrowRefunded is fully settled back to the buyer
+	EscrowRefunded EscrowStatus = "refunded"
+	// EscrowExpired was refunded to the buyer automatically at its deadline
+	EscrowExpired EscrowStatus = "expired"
+)
+
+// MaxPartyLength bounds the id of a party to an escrow
+const MaxPartyLength = 64
+
+// Escrow is money moved from a buyer into an escrow account, to be released to the
+// seller or refunded to the buyer, in one go or in parts. Released and Refunded are
+// the amounts settled so far; the escrow closes once nothing remains. Buyer, Seller
+// and Arbiter are the ids of the parties allowed to act on it.
+type Escrow struct {
+	ID                   uuid.UUID
+	BuyerAccountID       int64
+	SellerAccountID      int64
+	EscrowAccountID      int64
+	Buyer                string
+	Seller               string
+	Arbiter              string
+	Amount               int64
+	Currency             string
+	Released             int64
+	Refunded             int64
+	Status               EscrowStatus
+	Description          string
+	DisputeReason        string
+	FundingTransactionID uuid.UUID
+	ExpiresAt            time.Time
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+}
+
+// Remaining is what is still held in escrow
+func (e *Escrow) Remaining() int64 {
+	return e.Amount - e.Released - e.Refunded
+}
+
+// IsOpen reports whether the escrow still holds money
+func (e *Escrow) IsOpen() bool {
+	return e.Status == EscrowFunded || e.Status == EscrowDisputed
+}
+
+// IsExpired reports whether an undisputed escrow has passed its deadline
+func (e *Escrow) IsExpired(now time.Time) bool {
+	return e.Status == EscrowFunded && !now.Before(e.ExpiresAt)
+}
+
+// Settle records release to the seller and refund to the buyer, closing the escrow
+// once the whole amount is settled
+func (e *Escrow) Settle(release, refund int64) error {
+	if !e.IsOpen() {
+		return ErrEscrowNotOpen
+	}
+	if release < 0 || refund < 0 || release+refund <= 0 {
+		return ErrInvalidAmount
+	}
+	if release > e.Remaining() || refund > e.Remaining()-release {
+		return ErrEscrowAmountExceeded
+	}
+
+	e.Released += release
+	e.Refunded += refund
+	if e.Remaining() == 0 {
+		e.Status = EscrowRefunded
+		if e.Released > 0 {
+			e.Status = EscrowReleased
+		}
+	}
+	return nil
+}
+
+// Expire refunds whatever remains of an undisputed escrow past its deadline, returning the refund
+func (e *Escrow) Expire(now time.Time) (int64, error) {
+	if !e.IsExpired(now) {
+		return 0, ErrEscrowNotOpen
+	}
+	refund := e.Remaining()
+	e.Refunded += refund
+	e.Status = EscrowExpired
+	return refund, nil
+}
+
+// ValidParties reports whether the buyer, seller and arbiter are all set and distinct
+func (e *Escrow) ValidParties() bool {
+	for _, p := range []string{e.Buyer, e.Seller, e.Arbiter} {
+		if p == "" || len(p) > MaxPartyLength {
+			return false
+		}
+	}
+	return e.Buyer != e.Seller && e.Buyer != e.Arbiter && e.Seller != e.Arbiter
+}
+
+// CanDispute checks that the buyer or the seller is raising the dispute
+func (e *Escrow) CanDispute(party string) error {
+	if party == "" || (party != e.Buyer && party != e.Seller) {
+		return ErrNotEscrowParty
+	}
+	return nil
+}
+
+// CanSettle checks that a party may release the escrow to the seller, or refund it to
+// the buyer: each side can give up its own claim, the buyer by releasing and the seller
+// by refunding, and the arbiter can do both. A disputed escrow is settled by the arbiter only.
+func (e *Escrow) CanSettle(party string, refund bool) error {
+	if party == "" {
+		return ErrNotEscrowParty
+	}
+	if party == e.Arbiter {
+		return nil
+	}
+	if e.Status == EscrowDisputed {
+		return ErrNotEscrowParty
+	}
+	if (!refund && party == e.Buyer) || (refund && party == e.Seller) {
+		return nil
+	}
+	return ErrNotEscrowParty
+}
+
+// Dispute stops a funded escrow from expiring until it is settled
+func (e *Escrow) Dispute(reason string) error {
+	if e.Status != EscrowFunded {
+		return ErrEscrowNotOpen
+	}
+	e.Status = EscrowDisputed
+	e.DisputeReason = reason
+	return nil
+}
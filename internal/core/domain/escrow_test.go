@@ -0,0 +1,197 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEscrowSettle(t *testing.T) {
+	cases := []struct {
+		name            string
+		status          EscrowStatus
+		released        int64
+		refunded        int64
+		release, refund int64
+		wantErr         error
+		wantStatus      EscrowStatus
+	}{
+		{"partial release", EscrowFunded, 0, 0, 300, 0, nil, EscrowFunded},
+		{"full release", EscrowFunded, 0, 0, 1000, 0, nil, EscrowReleased},
+		{"full refund", EscrowFunded, 0, 0, 0, 1000, nil, EscrowRefunded},
+		{"split settles as released", EscrowFunded, 0, 0, 400, 600, nil, EscrowReleased},
+		{"rest refunded after a release", EscrowFunded, 200, 0, 0, 800, nil, EscrowReleased},
+		{"disputed can be settled", EscrowDisputed, 0, 0, 0, 1000, nil, EscrowRefunded},
+		{"release exceeds remaining", EscrowFunded, 500, 0, 501, 0, ErrEscrowAmountExceeded, EscrowFunded},
+		{"release and refund exceed remaining", EscrowFunded, 0, 0, 600, 401, ErrEscrowAmountExceeded, EscrowFunded},
+		{"nothing settled", EscrowFunded, 0, 0, 0, 0, ErrInvalidAmount, EscrowFunded},
+		{"negative part", EscrowFunded, 0, 0, 500, -1, ErrInvalidAmount, EscrowFunded},
+		{"already settled", EscrowReleased, 1000, 0, 1, 0, ErrEscrowNotOpen, EscrowReleased},
+		{"expired", EscrowExpired, 0, 1000, 0, 1, ErrEscrowNotOpen, EscrowExpired},
+	}
+
+	for _, tc := range cases {
+		e := &Escrow{Amount: 1000, Status: tc.status, Released: tc.released, Refunded: tc.refunded}
+		err := e.Settle(tc.release, tc.refund)
+		if !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: Settle(%d, %d) = %v, want %v", tc.name, tc.release, tc.refund, err, tc.wantErr)
+			continue
+		}
+		if e.Status != tc.wantStatus {
+			t.Errorf("%s: status = %s, want %s", tc.name, e.Status, tc.wantStatus)
+		}
+		wantReleased, wantRefunded := tc.released, tc.refunded
+		if tc.wantErr == nil {
+			wantReleased += tc.release
+			wantRefunded += tc.refund
+		}
+		if e.Released != wantReleased || e.Refunded != wantRefunded {
+			t.Errorf("%s: settled %d/%d, want %d/%d", tc.name, e.Released, e.Refunded, wantReleased, wantRefunded)
+		}
+	}
+}
+
+func TestEscrowExpire(t *testing.T) {
+	deadline := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name       string
+		status     EscrowStatus
+		released   int64
+		now        time.Time
+		wantRefund int64
+		wantErr    error
+		wantStatus EscrowStatus
+	}{
+		{"before the deadline", EscrowFunded, 0, deadline.Add(-time.Second), 0, ErrEscrowNotOpen, EscrowFunded},
+		{"at the deadline", EscrowFunded, 0, deadline, 1000, nil, EscrowExpired},
+		{"refunds only what remains", EscrowFunded, 300, deadline.Add(time.Hour), 700, nil, EscrowExpired},
+		{"disputed never expires", EscrowDisputed, 0, deadline.Add(time.Hour), 0, ErrEscrowNotOpen, EscrowDisputed},
+		{"already settled", EscrowReleased, 1000, deadline.Add(time.Hour), 0, ErrEscrowNotOpen, EscrowReleased},
+	}
+
+	for _, tc := range cases {
+		e := &Escrow{Amount: 1000, Released: tc.released, Status: tc.status, ExpiresAt: deadline}
+		refund, err := e.Expire(tc.now)
+		if !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: Expire = %v, want %v", tc.name, err, tc.wantErr)
+			continue
+		}
+		if refund != tc.wantRefund {
+			t.Errorf("%s: Expire = %d, want %d", tc.name, refund, tc.wantRefund)
+		}
+		if e.Status != tc.wantStatus {
+			t.Errorf("%s: status = %s, want %s", tc.name, e.Status, tc.wantStatus)
+		}
+		if tc.wantErr == nil && e.Remaining() != 0 {
+			t.Errorf("%s: Remaining = %d, want 0", tc.name, e.Remaining())
+		}
+	}
+}
+
+func TestEscrowDispute(t *testing.T) {
+	cases := []struct {
+		status  EscrowStatus
+		wantErr error
+	}{
+		{EscrowFunded, nil},
+		{EscrowDisputed, ErrEscrowNotOpen},
+		{EscrowReleased, ErrEscrowNotOpen},
+		{EscrowExpired, ErrEscrowNotOpen},
+	}
+
+	for _, tc := range cases {
+		e := &Escrow{Amount: 1000, Status: tc.status}
+		if err := e.Dispute("not delivered"); !errors.Is(err, tc.wantErr) {
+			t.Errorf("Dispute(%s) = %v, want %v", tc.status, err, tc.wantErr)
+			continue
+		}
+		if tc.wantErr == nil && (e.Status != EscrowDisputed || e.DisputeReason != "not delivered") {
+			t.Errorf("Dispute(%s) left status %s, reason %q", tc.status, e.Status, e.DisputeReason)
+		}
+	}
+}
+
+func TestEscrowValidParties(t *testing.T) {
+	cases := []struct {
+		name                   string
+		buyer, seller, arbiter string
+		want                   bool
+	}{
+		{"distinct", "buyer", "seller", "ops", true},
+		{"no buyer", "", "seller", "ops", false},
+		{"no arbiter", "buyer", "seller", "", false},
+		{"buyer is seller", "same", "same", "ops", false},
+		{"buyer is arbiter", "ops", "seller", "ops", false},
+		{"seller is arbiter", "buyer", "ops", "ops", false},
+		{"too long", strings.Repeat("x", MaxPartyLength+1), "seller", "ops", false},
+	}
+
+	for _, tc := range cases {
+		e := &Escrow{Buyer: tc.buyer, Seller: tc.seller, Arbiter: tc.arbiter}
+		if got := e.ValidParties(); got != tc.want {
+			t.Errorf("%s: ValidParties = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestEscrowCanDispute(t *testing.T) {
+	e := &Escrow{Buyer: "buyer", Seller: "seller", Arbiter: "ops", Status: EscrowFunded}
+	cases := []struct {
+		party   string
+		wantErr error
+	}{
+		{"buyer", nil},
+		{"seller", nil},
+		{"ops", ErrNotEscrowParty},
+		{"someone", ErrNotEscrowParty},
+		{"", ErrNotEscrowParty},
+	}
+
+	for _, tc := range cases {
+		if err := e.CanDispute(tc.party); !errors.Is(err, tc.wantErr) {
+			t.Errorf("CanDispute(%q) = %v, want %v", tc.party, err, tc.wantErr)
+		}
+	}
+}
+
+func TestEscrowCanSettle(t *testing.T) {
+	cases := []struct {
+		name    string
+		status  EscrowStatus
+		party   string
+		refund  bool
+		wantErr error
+	}{
+		{"buyer releases", EscrowFunded, "buyer", false, nil},
+		{"buyer refunds itself", EscrowFunded, "buyer", true, ErrNotEscrowParty},
+		{"seller refunds", EscrowFunded, "seller", true, nil},
+		{"seller releases to itself", EscrowFunded, "seller", false, ErrNotEscrowParty},
+		{"arbiter releases", EscrowFunded, "ops", false, nil},
+		{"arbiter refunds", EscrowFunded, "ops", true, nil},
+		{"stranger", EscrowFunded, "someone", false, ErrNotEscrowParty},
+		{"no party", EscrowFunded, "", true, ErrNotEscrowParty},
+		{"disputed buyer releases", EscrowDisputed, "buyer", false, ErrNotEscrowParty},
+		{"disputed seller refunds", EscrowDisputed, "seller", true, ErrNotEscrowParty},
+		{"disputed arbiter releases", EscrowDisputed, "ops", false, nil},
+		{"disputed arbiter refunds", EscrowDisputed, "ops", true, nil},
+	}
+
+	for _, tc := range cases {
+		e := &Escrow{Buyer: "buyer", Seller: "seller", Arbiter: "ops", Status: tc.status}
+		if err := e.CanSettle(tc.party, tc.refund); !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: CanSettle = %v, want %v", tc.name, err, tc.wantErr)
+		}
+	}
+}
+
+// an escrow stored before parties were recorded can't be acted on with an empty party id
+func TestEscrowWithoutPartiesRefusesEmptyCaller(t *testing.T) {
+	e := &Escrow{Status: EscrowFunded}
+	if err := e.CanDispute(""); !errors.Is(err, ErrNotEscrowParty) {
+		t.Errorf("CanDispute = %v, want %v", err, ErrNotEscrowParty)
+	}
+	if err := e.CanSettle("", false); !errors.Is(err, ErrNotEscrowParty) {
+		t.Errorf("CanSettle = %v, want %v", err, ErrNotEscrowParty)
+	}
+}
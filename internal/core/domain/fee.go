@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FeeType string
+
+const (
+	// FeeFlat charges a fixed amount per transfer
+	FeeFlat FeeType = "flat"
+	// FeePercentage charges a share of the amount, bounded by MinFee and MaxFee
+	FeePercentage FeeType = "percentage"
+	// FeeTiered picks the first tier the amount falls into
+	FeeTiered FeeType = "tiered"
+)
+
+// FeeTier applies to amounts up to and including UpTo; zero means no upper bound.
+// The fee is Flat plus RateBps basis points of the amount.
+type FeeTier struct {
+	UpTo    int64
+	Flat    int64
+	RateBps int64
+}
+
+// FeeRule charges a fee on transfers out of accounts in Currency, optionally only
+// for one account type. The fee is paid by the source, in its currency, to the
+// rule's revenue account. Amounts are minor units of Currency; rates are basis points.
+type FeeRule struct {
+	ID               uuid.UUID
+	Name             string
+	Type             FeeType
+	Currency         string
+	AccountType      AccountType
+	Flat             int64
+	RateBps          int64
+	MinFee           int64
+	MaxFee           int64
+	Tiers            []FeeTier
+	RevenueAccountID int64
+	// Priority orders the rules, the lowest matching one applies
+	Priority  int
+	Active    bool
+	CreatedAt time.Time
+}
+
+// Matches reports whether the rule applies to transfers out of the account
+func (r *FeeRule) Matches(from *Account) bool {
+	return r.Active &&
+		r.Currency == from.Currency &&
+		(r.AccountType == "" || r.AccountType == from.Type) &&
+		r.RevenueAccountID != from.AccountID
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// FXRate is the price of one unit of Base expressed in Quote.
+// Rate is scaled by currency.RateScale to keep conversions in integer arithmetic.
+type FXRate struct {
+	Base      string
+	Quote     string
+	Rate      int64
+	UpdatedAt time.Time
+}
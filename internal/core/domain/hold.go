@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type HoldStatus string
+
+const (
+	HoldStatusActive   HoldStatus = "active"
+	HoldStatusCaptured HoldStatus = "captured"
+	HoldStatusVoided   HoldStatus = "voided"
+	HoldStatusExpired  HoldStatus = "expired"
+)
+
+// Hold reserves funds on an account without moving them. While active, the held
+// amount is excluded from the account's available balance. A hold ends by being
+// captured into a transfer to DestinationAccountID, voided, or expiring.
+type Hold struct {
+	ID                   uuid.UUID
+	AccountID            int64
+	DestinationAccountID int64
+	Amount               int64
+	Currency             string
+	CapturedAmount       int64
+	TransactionID        uuid.UUID
+	Status               HoldStatus
+	ExpiresAt            time.Time
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+}
+
+// IsExpired reports whether the hold has passed its expiry time
+func (h *Hold) IsExpired(now time.Time) bool {
+	return !now.Before(h.ExpiresAt)
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHoldIsExpired(t *testing.T) {
+	expiresAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name string
+		now  time.Time
+		want bool
+	}{
+		{"before expiry", expiresAt.Add(-time.Second), false},
+		{"at expiry", expiresAt, true},
+		{"after expiry", expiresAt.Add(time.Minute), true},
+		{"same instant in another zone", expiresAt.In(time.FixedZone("IST", 5*3600+1800)), true},
+	}
+
+	for _, tc := range cases {
+		h := &Hold{ExpiresAt: expiresAt}
+		if got := h.IsExpired(tc.now); got != tc.want {
+			t.Errorf("%s: IsExpired = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
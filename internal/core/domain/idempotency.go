@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+type IdempotencyStatus string
+
+const (
+	IdempotencyInProgress IdempotencyStatus = "in_progress"
+	IdempotencyCompleted  IdempotencyStatus = "completed"
+)
+
+// IdempotencyKey records a request made with an Idempotency-Key header. Fingerprint
+// identifies the request it was first used with; once completed, the response is kept
+// so a replay gets the same answer until the key expires. An in-progress key is only
+// held until LockedUntil, so a request that never finished can't block its key.
+type IdempotencyKey struct {
+	Key            string
+	Fingerprint    string
+	Status         IdempotencyStatus
+	ResponseStatus int
+	ResponseBody   []byte
+	CreatedAt      time.Time
+	LockedUntil    time.Time
+	ExpiresAt      time.Time
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Interest uses the actual/365 fixed day-count convention: every calendar day accrues
+// 1/365 of the annual rate, leap years included. Rates are in basis points.
+const (
+	InterestDayBasis = 365
+	// InterestUnit is how many accrual units make one minor unit: a day's accrual is
+	// balance × rate in bps, which is exact, and only the monthly total is divided down
+	InterestUnit = 10_000 * InterestDayBasis
+)
+
+// InterestAccrual is the interest one account earned on one UTC day, on its ledger
+// balance at the end of that day. Accrued is in InterestUnit fractions of a minor unit.
+type InterestAccrual struct {
+	AccountID int64
+	Date      time.Time
+	Balance   int64
+	RateBps   int64
+	Accrued   int64
+	CreatedAt time.Time
+}
+
+// InterestPosting pays out the interest of one account for one month (Period, as YYYY-MM).
+// TransactionID is nil when there was nothing to pay.
+type InterestPosting struct {
+	AccountID     int64
+	Period        string
+	Amount        int64
+	TransactionID uuid.UUID
+	CreatedAt     time.Time
+}
+
+// DailyInterest is the accrual, in interest units, of a balance for one day.
+// Nothing accrues on a balance of zero or less.
+func DailyInterest(balance, rateBps int64) (int64, error) {
+	if rateBps < 0 {
+		return 0, ErrInvalidAmount
+	}
+	if balance <= 0 || rateBps == 0 {
+		return 0, nil
+	}
+	if balance > math.MaxInt64/rateBps {
+		return 0, ErrInvalidAmount
+	}
+	return balance * rateBps, nil
+}
+
+// InterestDue is what to pay now, in minor units, given the total accrued so far in
+// interest units and the total already paid. Fractions of a minor unit are never lost:
+// they stay in the accrued total and are paid once they add up to a whole unit.
+func InterestDue(accrued, posted int64) int64 {
+	due := accrued/InterestUnit - posted
+	if due < 0 {
+		return 0
+	}
+	return due
+}
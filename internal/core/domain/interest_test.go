@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDailyInterest(t *testing.T) {
+	cases := []struct {
+		balance, rateBps int64
+		want             int64
+	}{
+		{100_000, 350, 35_000_000},
+		{1, 1, 1},
+		{0, 350, 0},
+		{-5000, 350, 0},
+		{100_000, 0, 0},
+	}
+
+	for _, tc := range cases {
+		got, err := DailyInterest(tc.balance, tc.rateBps)
+		if err != nil {
+			t.Errorf("DailyInterest(%d, %d): %v", tc.balance, tc.rateBps, err)
+		}
+		if got != tc.want {
+			t.Errorf("DailyInterest(%d, %d) = %d, want %d", tc.balance, tc.rateBps, got, tc.want)
+		}
+	}
+}
+
+func TestDailyInterest_Invalid(t *testing.T) {
+	if _, err := DailyInterest(100, -1); err == nil {
+		t.Error("DailyInterest with a negative rate should fail")
+	}
+	if _, err := DailyInterest(math.MaxInt64, 2); err == nil {
+		t.Error("DailyInterest should fail on overflow")
+	}
+}
+
+func TestInterestDue(t *testing.T) {
+	cases := []struct {
+		accrued, posted int64
+		want            int64
+	}{
+		{0, 0, 0},
+		{InterestUnit - 1, 0, 0},
+		{InterestUnit, 0, 1},
+		{5*InterestUnit + 7, 2, 3},
+		// fractions carry over: 2.5 units accrued, 2 already paid
+		{5 * InterestUnit / 2, 2, 0},
+		{InterestUnit, 3, 0},
+	}
+
+	for _, tc := range cases {
+		if got := InterestDue(tc.accrued, tc.posted); got != tc.want {
+			t.Errorf("InterestDue(%d, %d) = %d, want %d", tc.accrued, tc.posted, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EntryDirection is the side of a posting from the account holder's point of view.
+// A debit takes money out of the account, a credit puts money into it.
+type EntryDirection string
+
+const (
+	EntryDebit  EntryDirection = "debit"
+	EntryCredit EntryDirection = "credit"
+)
+
+// FXPositionAccountID is the reserved ledger account that takes the other side of
+// currency conversions. It has no row in accounts; its entries per currency show
+// the system's open FX position.
+const FXPositionAccountID int64 = 0
+
+// LedgerEntry is one side of a double-entry posting against an account
+type LedgerEntry struct {
+	ID            uuid.UUID
+	TransactionID uuid.UUID
+	AccountID     int64
+	Direction     EntryDirection
+	Amount        int64
+	Currency      string
+	CreatedAt     time.Time
+}
+
+// TransferPostings returns the balanced postings for a transaction.
+// A same-currency transfer is a single debit/credit pair. A cross-currency transfer
+// is booked as two pairs through the FX position account, so each currency nets to zero.
+// A fee adds one more pair from the source to the fee account.
+func TransferPostings(txn *Transaction) []*LedgerEntry {
+	var entries []*LedgerEntry
+	if !txn.IsCrossCurrency() {
+		entries = []*LedgerEntry{
+			newEntry(txn, txn.SourceAccountID, EntryDebit, txn.Amount, txn.Currency),
+			newEntry(txn, txn.DestinationAccountID, EntryCredit, txn.DestinationAmount, txn.DestinationCurrency),
+		}
+	} else {
+		entries = []*LedgerEntry{
+			newEntry(txn, txn.SourceAccountID, EntryDebit, txn.Amount, txn.Currency),
+			newEntry(txn, FXPositionAccountID, EntryCredit, txn.Amount, txn.Currency),
+			newEntry(txn, FXPositionAccountID, EntryDebit, txn.DestinationAmount, txn.DestinationCurrency),
+			newEntry(txn, txn.DestinationAccountID, EntryCredit, txn.DestinationAmount, txn.DestinationCurrency),
+		}
+	}
+	if txn.Fee > 0 {
+		entries = append(entries,
+			newEntry(txn, txn.SourceAccountID, EntryDebit, txn.Fee, txn.Currency),
+			newEntry(txn, txn.FeeAccountID, EntryCredit, txn.Fee, txn.Currency),
+		)
+	}
+	return entries
+}
+
+func newEntry(txn *Transaction, accountID int64, dir EntryDirection, amount int64, currency string) *LedgerEntry {
+	return &LedgerEntry{
+		ID:            uuid.New(),
+		TransactionID: txn.ID,
+		AccountID:     accountID,
+		Direction:     dir,
+		Amount:        amount,
+		Currency:      currency,
+		CreatedAt:     txn.CreatedAt,
+	}
+}
+
+// Balanced reports whether the debits and credits of the entries net to zero in every currency
+func Balanced(entries []*LedgerEntry) bool {
+	net := make(map[string]int64)
+	for _, e := range entries {
+		if e.Amount <= 0 {
+			return false
+		}
+		switch e.Direction {
+		case EntryDebit:
+			net[e.Currency] -= e.Amount
+		case EntryCredit:
+			net[e.Currency] += e.Amount
+		default:
+			return false
+		}
+	}
+	for _, n := range net {
+		if n != 0 {
+			return false
+		}
+	}
+	return len(entries) > 0
+}
@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// posting is the part of a ledger entry TransferPostings decides
+type posting struct {
+	account   int64
+	direction EntryDirection
+	amount    int64
+	currency  string
+}
+
+func TestTransferPostings(t *testing.T) {
+	cases := []struct {
+		name string
+		txn  Transaction
+		want []posting
+	}{
+		{
+			"same currency",
+			Transaction{SourceAccountID: 1, DestinationAccountID: 2, Amount: 500, Currency: "USD", DestinationAmount: 500, DestinationCurrency: "USD"},
+			[]posting{{1, EntryDebit, 500, "USD"}, {2, EntryCredit, 500, "USD"}},
+		},
+		{
+			"cross currency through the fx position",
+			Transaction{SourceAccountID: 1, DestinationAccountID: 2, Amount: 1000, Currency: "USD", DestinationAmount: 83250, DestinationCurrency: "INR"},
+			[]posting{
+				{1, EntryDebit, 1000, "USD"},
+				{FXPositionAccountID, EntryCredit, 1000, "USD"},
+				{FXPositionAccountID, EntryDebit, 83250, "INR"},
+				{2, EntryCredit, 83250, "INR"},
+			},
+		},
+		{
+			"same currency with a fee",
+			Transaction{SourceAccountID: 1, DestinationAccountID: 2, Amount: 500, Currency: "USD", DestinationAmount: 500, DestinationCurrency: "USD", Fee: 25, FeeAccountID: 9},
+			[]posting{{1, EntryDebit, 500, "USD"}, {2, EntryCredit, 500, "USD"}, {1, EntryDebit, 25, "USD"}, {9, EntryCredit, 25, "USD"}},
+		},
+		{
+			"cross currency fee in the source currency",
+			Transaction{SourceAccountID: 1, DestinationAccountID: 2, Amount: 1000, Currency: "USD", DestinationAmount: 920, DestinationCurrency: "EUR", Fee: 10, FeeAccountID: 9},
+			[]posting{
+				{1, EntryDebit, 1000, "USD"},
+				{FXPositionAccountID, EntryCredit, 1000, "USD"},
+				{FXPositionAccountID, EntryDebit, 920, "EUR"},
+				{2, EntryCredit, 920, "EUR"},
+				{1, EntryDebit, 10, "USD"},
+				{9, EntryCredit, 10, "USD"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		tc.txn.ID = uuid.New()
+		entries := TransferPostings(&tc.txn)
+		if len(entries) != len(tc.want) {
+			t.Errorf("%s: %d postings, want %d", tc.name, len(entries), len(tc.want))
+			continue
+		}
+		for i, e := range entries {
+			got := posting{e.AccountID, e.Direction, e.Amount, e.Currency}
+			if got != tc.want[i] {
+				t.Errorf("%s: posting %d = %+v, want %+v", tc.name, i, got, tc.want[i])
+			}
+			if e.TransactionID != tc.txn.ID {
+				t.Errorf("%s: posting %d is for transaction %s, want %s", tc.name, i, e.TransactionID, tc.txn.ID)
+			}
+		}
+		if !Balanced(entries) {
+			t.Errorf("%s: postings are not balanced", tc.name)
+		}
+	}
+}
+
+func TestBalanced(t *testing.T) {
+	entry := func(dir EntryDirection, amount int64, currency string) *LedgerEntry {
+		return &LedgerEntry{Direction: dir, Amount: amount, Currency: currency}
+	}
+	cases := []struct {
+		name    string
+		entries []*LedgerEntry
+		want    bool
+	}{
+		{"pair", []*LedgerEntry{entry(EntryDebit, 100, "USD"), entry(EntryCredit, 100, "USD")}, true},
+		{"two currencies each netting to zero", []*LedgerEntry{
+			entry(EntryDebit, 100, "USD"), entry(EntryCredit, 100, "USD"),
+			entry(EntryDebit, 9200, "INR"), entry(EntryCredit, 9200, "INR"),
+		}, true},
+		{"credits exceed debits", []*LedgerEntry{entry(EntryDebit, 100, "USD"), entry(EntryCredit, 101, "USD")}, false},
+		{"debit only", []*LedgerEntry{entry(EntryDebit, 100, "USD")}, false},
+		{"balanced across currencies only", []*LedgerEntry{entry(EntryDebit, 100, "USD"), entry(EntryCredit, 100, "EUR")}, false},
+		{"zero amounts", []*LedgerEntry{entry(EntryDebit, 0, "USD"), entry(EntryCredit, 0, "USD")}, false},
+		{"negative amounts", []*LedgerEntry{entry(EntryDebit, -100, "USD"), entry(EntryCredit, -100, "USD")}, false},
+		{"unknown direction", []*LedgerEntry{entry("sideways", 100, "USD"), entry(EntryCredit, 100, "USD")}, false},
+		{"no entries", nil, false},
+	}
+
+	for _, tc := range cases {
+		if got := Balanced(tc.entries); got != tc.want {
+			t.Errorf("%s: Balanced = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Names of the limits, as reported when one is exceeded
+const (
+	LimitPerTransaction  = "per_transaction"
+	LimitDailyOutgoing   = "daily_outgoing"
+	LimitMonthlyOutgoing = "monthly_outgoing"
+	LimitHourlyCount     = "hourly_transfers"
+)
+
+// TransferLimit caps what can leave an account. It is set either on one account
+// (AccountID) or on a tier, an account type in one currency (AccountType); an account's
+// own limit replaces its tier's. Amounts are minor units of Currency, which is always
+// the account's currency, and zero leaves that limit off.
+type TransferLimit struct {
+	AccountID         int64
+	AccountType       AccountType
+	Currency          string
+	MaxPerTransaction int64
+	DailyOutgoing     int64
+	MonthlyOutgoing   int64
+	HourlyCount       int64
+	UpdatedAt         time.Time
+}
+
+// LimitUsage is what an account has already sent in each limit window:
+// the current UTC day and month, and the last hour
+type LimitUsage struct {
+	Daily    int64
+	Monthly  int64
+	LastHour int64
+}
+
+// LimitWindows are the starts of the limit windows that contain now: the UTC day, the
+// UTC month and the last hour
+type LimitWindows struct {
+	Day   time.Time
+	Month time.Time
+	Hour  time.Time
+}
+
+func LimitWindowsAt(now time.Time) LimitWindows {
+	now = now.UTC()
+	return LimitWindows{
+		Day:   time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+		Month: time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC),
+		Hour:  now.Add(-time.Hour),
+	}
+}
+
+// Check reports whether a transfer of amount fits within the limits, given the usage so far
+func (l *TransferLimit) Check(amount int64, usage LimitUsage) error {
+	if l.MaxPerTransaction > 0 && amount > l.MaxPerTransaction {
+		return &LimitError{Limit: LimitPerTransaction, Remaining: l.MaxPerTransaction, Currency: l.Currency}
+	}
+	if l.DailyOutgoing > 0 && amount > l.DailyOutgoing-usage.Daily {
+		return &LimitError{Limit: LimitDailyOutgoing, Remaining: max(l.DailyOutgoing-usage.Daily, 0), Currency: l.Currency}
+	}
+	if l.MonthlyOutgoing > 0 && amount > l.MonthlyOutgoing-usage.Monthly {
+		return &LimitError{Limit: LimitMonthlyOutgoing, Remaining: max(l.MonthlyOutgoing-usage.Monthly, 0), Currency: l.Currency}
+	}
+	if l.HourlyCount > 0 && usage.LastHour >= l.HourlyCount {
+		return &LimitError{Limit: LimitHourlyCount, Remaining: 0}
+	}
+	return nil
+}
+
+// LimitError is returned when a transfer would exceed a limit. Remaining is the headroom
+// left under that limit: an amount, or a number of transfers for the hourly count.
+type LimitError struct {
+	Limit     string
+	Remaining int64
+	Currency  string
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrLimitExceeded, e.Limit)
+}
+
+func (e *LimitError) Is(target error) bool {
+	return target == ErrLimitExceeded
+}
@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTransferLimitCheck(t *testing.T) {
+	limit := &TransferLimit{
+		Currency:          "USD",
+		MaxPerTransaction: 1000,
+		DailyOutgoing:     2500,
+		MonthlyOutgoing:   10000,
+		HourlyCount:       3,
+	}
+	cases := []struct {
+		name          string
+		amount        int64
+		usage         LimitUsage
+		wantLimit     string
+		wantRemaining int64
+	}{
+		{"within all limits", 1000, LimitUsage{Daily: 1500, Monthly: 9000, LastHour: 2}, "", 0},
+		{"per transaction", 1001, LimitUsage{}, LimitPerTransaction, 1000},
+		{"daily", 600, LimitUsage{Daily: 2000, Monthly: 2000}, LimitDailyOutgoing, 500},
+		{"daily already over", 1, LimitUsage{Daily: 3000, Monthly: 3000}, LimitDailyOutgoing, 0},
+		{"monthly", 600, LimitUsage{Daily: 0, Monthly: 9500}, LimitMonthlyOutgoing, 500},
+		{"hourly count", 1, LimitUsage{LastHour: 3}, LimitHourlyCount, 0},
+	}
+
+	for _, tc := range cases {
+		err := limit.Check(tc.amount, tc.usage)
+		if tc.wantLimit == "" {
+			if err != nil {
+				t.Errorf("%s: Check = %v, want nil", tc.name, err)
+			}
+			continue
+		}
+		var limitErr *LimitError
+		if !errors.As(err, &limitErr) {
+			t.Errorf("%s: Check = %v, want a LimitError", tc.name, err)
+			continue
+		}
+		if limitErr.Limit != tc.wantLimit || limitErr.Remaining != tc.wantRemaining {
+			t.Errorf("%s: Check = %s with %d remaining, want %s with %d", tc.name, limitErr.Limit, limitErr.Remaining, tc.wantLimit, tc.wantRemaining)
+		}
+		if !errors.Is(err, ErrLimitExceeded) {
+			t.Errorf("%s: LimitError should match ErrLimitExceeded", tc.name)
+		}
+	}
+}
+
+func TestTransferLimitCheck_Off(t *testing.T) {
+	limit := &TransferLimit{Currency: "USD"}
+	if err := limit.Check(1_000_000, LimitUsage{Daily: 1_000_000, Monthly: 1_000_000, LastHour: 100}); err != nil {
+		t.Errorf("zero limits should be off, got %v", err)
+	}
+}
+
+func TestLimitWindowsAt(t *testing.T) {
+	ist := time.FixedZone("IST", 5*3600+1800)
+	cases := []struct {
+		now       time.Time
+		wantDay   time.Time
+		wantMonth time.Time
+	}{
+		{
+			time.Date(2026, 3, 15, 13, 45, 0, 0, time.UTC),
+			time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC),
+			time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+		// 02:00 on April 1st in IST is still March 31st in UTC
+		{
+			time.Date(2026, 4, 1, 2, 0, 0, 0, ist),
+			time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+			time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range cases {
+		got := LimitWindowsAt(tc.now)
+		if !got.Day.Equal(tc.wantDay) {
+			t.Errorf("LimitWindowsAt(%s).Day = %s, want %s", tc.now, got.Day, tc.wantDay)
+		}
+		if !got.Month.Equal(tc.wantMonth) {
+			t.Errorf("LimitWindowsAt(%s).Month = %s, want %s", tc.now, got.Month, tc.wantMonth)
+		}
+		if want := tc.now.Add(-time.Hour); !got.Hour.Equal(want) {
+			t.Errorf("LimitWindowsAt(%s).Hour = %s, want %s", tc.now, got.Hour, want)
+		}
+	}
+}
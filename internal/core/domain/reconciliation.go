@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReconciliationCheck is one of the invariants a reconciliation run verifies
+type ReconciliationCheck string
+
+const (
+	// CheckTotalFunds: per currency, the balances of all accounts plus the FX position
+	// add up to the funds put in as opening balances, since transfers only move money
+	CheckTotalFunds ReconciliationCheck = "total_funds"
+	// CheckAccountFlows: an account's balance is its opening balance plus the net of its transactions
+	CheckAccountFlows ReconciliationCheck = "account_flows"
+	// CheckNegativeBalance: only account types that allow it may be below zero, other
+	// accounts no further than their overdraft limit
+	CheckNegativeBalance ReconciliationCheck = "negative_balance"
+)
+
+type ReconciliationStatus string
+
+const (
+	ReconciliationBalanced      ReconciliationStatus = "balanced"
+	ReconciliationDiscrepancies ReconciliationStatus = "discrepancies"
+)
+
+// Discrepancy is a failed check. AccountID is zero for CheckTotalFunds, which is per
+// currency, and Expected is the lowest allowed balance for CheckNegativeBalance.
+type Discrepancy struct {
+	Check     ReconciliationCheck
+	AccountID int64
+	Currency  string
+	Expected  int64
+	Actual    int64
+}
+
+// Difference is how far the actual amount is off the expected one
+func (d Discrepancy) Difference() int64 {
+	return d.Actual - d.Expected
+}
+
+// ReconciliationRun is the outcome of one pass of all checks over the whole ledger
+type ReconciliationRun struct {
+	ID              uuid.UUID
+	Status          ReconciliationStatus
+	AccountsChecked int64
+	Discrepancies   []Discrepancy
+	StartedAt       time.Time
+	FinishedAt      time.Time
+}
@@ -0,0 +1,22 @@
+package domain
+
+import "testing"
+
+func TestDiscrepancyDifference(t *testing.T) {
+	cases := []struct {
+		name string
+		d    Discrepancy
+		want int64
+	}{
+		{"balance too high", Discrepancy{Check: CheckAccountFlows, Expected: 1000, Actual: 1250}, 250},
+		{"balance too low", Discrepancy{Check: CheckAccountFlows, Expected: 1000, Actual: 900}, -100},
+		{"beyond the overdraft", Discrepancy{Check: CheckNegativeBalance, Expected: -500, Actual: -700}, -200},
+		{"funds missing in a currency", Discrepancy{Check: CheckTotalFunds, Currency: "USD", Expected: 0, Actual: -1}, -1},
+	}
+
+	for _, tc := range cases {
+		if got := tc.d.Difference(); got != tc.want {
+			t.Errorf("%s: Difference = %d, want %d", tc.name, got, tc.want)
+		}
+	}
+}
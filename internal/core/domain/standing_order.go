@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Frequency string
+
+const (
+	FrequencyDaily   Frequency = "daily"
+	FrequencyWeekly  Frequency = "weekly"
+	FrequencyMonthly Frequency = "monthly"
+	FrequencyCron    Frequency = "cron"
+)
+
+type StandingOrderStatus string
+
+const (
+	StandingOrderActive    StandingOrderStatus = "active"
+	StandingOrderSuspended StandingOrderStatus = "suspended"
+	StandingOrderCompleted StandingOrderStatus = "completed"
+	StandingOrderCancelled StandingOrderStatus = "cancelled"
+)
+
+// InsufficientFundsPolicy decides what a run does when the source cannot cover the amount
+type InsufficientFundsPolicy string
+
+const (
+	// OnInsufficientSkip gives up on this occurrence and waits for the next one
+	OnInsufficientSkip InsufficientFundsPolicy = "skip"
+	// OnInsufficientRetry tries the same occurrence again later, until the next one is due
+	OnInsufficientRetry InsufficientFundsPolicy = "retry"
+	// OnInsufficientSuspend suspends the order until it is resumed
+	OnInsufficientSuspend InsufficientFundsPolicy = "suspend"
+)
+
+// StandingOrder is a recurring transfer. ScheduledFor is the occurrence currently due;
+// NextRunAt is when it will be attempted, which is later than ScheduledFor while a
+// run is waiting to be retried.
+type StandingOrder struct {
+	ID                  uuid.UUID
+	FromAccount         int64
+	ToAccount           int64
+	Amount              int64
+	Currency            string
+	Frequency           Frequency
+	CronExpr            string
+	StartAt             time.Time
+	EndAt               time.Time
+	MaxRuns             int
+	RunCount            int
+	ScheduledFor        time.Time
+	NextRunAt           time.Time
+	OnInsufficientFunds InsufficientFundsPolicy
+	Status              StandingOrderStatus
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+// Advance moves the order to its next occurrence, completing it when the run limit
+// or end date is reached
+func (o *StandingOrder) Advance(next time.Time) {
+	o.ScheduledFor = next
+	o.NextRunAt = next
+	if (o.MaxRuns > 0 && o.RunCount >= o.MaxRuns) ||
+		next.IsZero() ||
+		(!o.EndAt.IsZero() && next.After(o.EndAt)) {
+		o.Status = StandingOrderCompleted
+	}
+}
+
+type StandingOrderRunStatus string
+
+const (
+	RunStatusCompleted StandingOrderRunStatus = "completed"
+	RunStatusSkipped   StandingOrderRunStatus = "skipped"
+	RunStatusRetrying  StandingOrderRunStatus = "retrying"
+	RunStatusFailed    StandingOrderRunStatus = "failed"
+)
+
+// StandingOrderRun records one attempt at executing an occurrence of a standing order
+type StandingOrderRun struct {
+	ID            uuid.UUID
+	OrderID       uuid.UUID
+	ScheduledFor  time.Time
+	Status        StandingOrderRunStatus
+	TransactionID uuid.UUID
+	Error         string
+	CreatedAt     time.Time
+}
@@ -6,11 +6,117 @@ import (
 	"github.com/google/uuid"
 )
 
-// Transaction represents a money transfer in the domain
+type TxKind string
+
+const (
+	TxKindTransfer TxKind = "transfer"
+	TxKindReversal TxKind = "reversal"
+	// TxKindSplit is the parent of a split payment. It carries the total debited
+	// and has no postings of its own; its legs are transfers pointing to it through ParentID.
+	TxKindSplit TxKind = "split"
+	// TxKindInterest pays accrued interest from the interest-expense account to a savings account
+	TxKindInterest TxKind = "interest"
+	// TxKindDeposit brings money into the system, from the treasury to an account
+	TxKindDeposit TxKind = "deposit"
+	// TxKindWithdrawal takes money out of the system, from an account to the treasury
+	TxKindWithdrawal TxKind = "withdrawal"
+)
+
+// Transaction represents a money transfer in the domain.
+// Amount is debited in Currency and DestinationAmount credited in DestinationCurrency;
+// both are equal for same-currency transfers, where FXRate is zero.
 type Transaction struct {
 	ID                   uuid.UUID
+	Kind                 TxKind
+	ReversalOf           uuid.UUID
+	ParentID             uuid.UUID
 	SourceAccountID      int64
 	DestinationAccountID int64
 	Amount               int64
-	CreatedAt            time.Time
+	Currency             string
+	DestinationAmount    int64
+	DestinationCurrency  string
+	FXRate               int64
+	FXRateAt             time.Time
+	// Fee is charged to the source on top of Amount, in Currency, and credited to FeeAccountID
+	Fee          int64
+	FeeAccountID int64
+	TransferDetails
+	CreatedAt time.Time
+}
+
+// Bounds on what a client can attach to a transfer
+const (
+	MaxDescriptionLength   = 500
+	MaxExternalRefLength   = 128
+	MaxMetadataKeys        = 50
+	MaxMetadataKeyLength   = 64
+	MaxMetadataValueLength = 500
+)
+
+// TransferDetails is what a client attaches to a transfer. ExternalRef is the client's
+// own reference, e.g. a merchant order id, and is unique among the transfers sent from
+// one account. All of it is optional.
+type TransferDetails struct {
+	Description string
+	ExternalRef string
+	Metadata    map[string]string
+}
+
+// Validate checks the details against their bounds
+func (d TransferDetails) Validate() error {
+	if len(d.Description) > MaxDescriptionLength || len(d.ExternalRef) > MaxExternalRefLength ||
+		len(d.Metadata) > MaxMetadataKeys {
+		return ErrInvalidDetails
+	}
+	for k, v := range d.Metadata {
+		if k == "" || len(k) > MaxMetadataKeyLength || len(v) > MaxMetadataValueLength {
+			return ErrInvalidDetails
+		}
+	}
+	return nil
+}
+
+// IsReversal reports whether the transaction compensates an earlier one
+func (t *Transaction) IsReversal() bool {
+	return t.ReversalOf != uuid.Nil
+}
+
+// IsSplit reports whether the transaction is the parent of a split payment
+func (t *Transaction) IsSplit() bool {
+	return t.Kind == TxKindSplit
+}
+
+// IsCrossCurrency reports whether the transaction converts between currencies
+func (t *Transaction) IsCrossCurrency() bool {
+	return t.Currency != t.DestinationCurrency
+}
+
+// TransactionFilter narrows a transaction listing; zero values leave a filter off.
+// With AccountID set only transactions that moved money on that account are listed,
+// split parents left out. Direction then picks the account's side, Counterparty is the
+// account on the other side, and amounts are compared on the account's side, in its
+// currency. Without it, Counterparty matches either side and amounts are source amounts.
+type TransactionFilter struct {
+	AccountID    int64
+	Direction    EntryDirection
+	Counterparty int64
+	Kind         TxKind
+	Currency     string
+	MinAmount    int64
+	MaxAmount    int64
+	From         time.Time
+	To           time.Time
+	ExternalRef  string
+	// Description matches transactions whose description contains it, ignoring case
+	Description string
+	// Metadata matches transactions whose metadata holds all of these pairs
+	Metadata map[string]string
+}
+
+// TxCursor is the position of the last transaction of a page. Listings run newest
+// first in (CreatedAt, ID) order, so a page never shifts under concurrent inserts.
+type TxCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
 }
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestTransferDetailsValidate(t *testing.T) {
+	tooManyKeys := make(map[string]string, MaxMetadataKeys+1)
+	for i := 0; i <= MaxMetadataKeys; i++ {
+		tooManyKeys["k"+strconv.Itoa(i)] = "v"
+	}
+
+	cases := []struct {
+		name    string
+		details TransferDetails
+		valid   bool
+	}{
+		{"empty", TransferDetails{}, true},
+		{"all set", TransferDetails{Description: "Order #1001", ExternalRef: "order-1001", Metadata: map[string]string{"channel": "web"}}, true},
+		{"description at the limit", TransferDetails{Description: strings.Repeat("a", MaxDescriptionLength)}, true},
+		{"description too long", TransferDetails{Description: strings.Repeat("a", MaxDescriptionLength+1)}, false},
+		{"external_ref at the limit", TransferDetails{ExternalRef: strings.Repeat("r", MaxExternalRefLength)}, true},
+		{"external_ref too long", TransferDetails{ExternalRef: strings.Repeat("r", MaxExternalRefLength+1)}, false},
+		{"too many metadata keys", TransferDetails{Metadata: tooManyKeys}, false},
+		{"empty metadata key", TransferDetails{Metadata: map[string]string{"": "v"}}, false},
+		{"metadata key too long", TransferDetails{Metadata: map[string]string{strings.Repeat("k", MaxMetadataKeyLength+1): "v"}}, false},
+		{"metadata value too long", TransferDetails{Metadata: map[string]string{"k": strings.Repeat("v", MaxMetadataValueLength+1)}}, false},
+	}
+
+	for _, tc := range cases {
+		err := tc.details.Validate()
+		if tc.valid && err != nil {
+			t.Errorf("%s: Validate = %v, want nil", tc.name, err)
+		}
+		if !tc.valid && !errors.Is(err, ErrInvalidDetails) {
+			t.Errorf("%s: Validate = %v, want ErrInvalidDetails", tc.name, err)
+		}
+	}
+}
@@ -4,7 +4,20 @@ import "github.com/maneeshsagar/tps/internal/core/domain"
 
 type AccountRepository interface {
 	GetByID(id int64) (*domain.Account, error)
+	ListByType(accountType domain.AccountType) ([]*domain.Account, error)
+	// GetTreasury returns the treasury account of a currency
+	GetTreasury(currency string) (*domain.Account, error)
+	// List returns up to limit accounts with an id above afterID, in id order
+	List(afterID int64, limit int) ([]*domain.Account, error)
 	Update(account *domain.Account) error
+	// AddToBalance credits an account with an atomic increment, for accounts such as
+	// fee revenue that are credited too often to be locked by every transfer
+	AddToBalance(accountID, amount int64) error
 	Create(account *domain.Account) error
+	// UpdateStatus writes the status fields of an account and records the change
+	UpdateStatus(account *domain.Account, change *domain.AccountStatusChange) error
+	// UpdateBalancePolicy writes the overdraft, minimum and maximum balance of an account
+	UpdateBalancePolicy(account *domain.Account) error
+	ListStatusChanges(accountID int64) ([]*domain.AccountStatusChange, error)
 	WithTx(tx Transaction) AccountRepository
 }
@@ -1,6 +1,8 @@
 package ports
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/maneeshsagar/tps/internal/core/domain"
 )
@@ -8,5 +10,20 @@ import (
 type AsyncTransactionRepository interface {
 	Create(tx *domain.AsyncTransaction) error
 	GetByID(id uuid.UUID) (*domain.AsyncTransaction, error)
+	// GetByTransactionID returns the submission a completed transaction came from
+	GetByTransactionID(transactionID uuid.UUID) (*domain.AsyncTransaction, error)
 	UpdateStatus(id uuid.UUID, status domain.TxStatus, errMsg string) error
+	// Complete marks a submission completed and links the transaction it produced
+	Complete(id, transactionID uuid.UUID) error
+	// TransitionStatus moves a transaction to status `to` only if it is currently in `from`.
+	// It reports whether the transition happened.
+	TransitionStatus(id uuid.UUID, from, to domain.TxStatus) (bool, error)
+	// RecordDecision writes the status and approval decision of a transaction only if it
+	// is still pending approval. It reports whether the decision was recorded.
+	RecordDecision(tx *domain.AsyncTransaction) (bool, error)
+	// ListByStatus returns up to limit transactions in status, oldest first
+	ListByStatus(status domain.TxStatus, limit int) ([]*domain.AsyncTransaction, error)
+	// ClaimDue atomically moves scheduled transactions due at or before now to executing
+	// and returns them, so each one is claimed by a single server replica.
+	ClaimDue(now time.Time, limit int) ([]*domain.AsyncTransaction, error)
 }
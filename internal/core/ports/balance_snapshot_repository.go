@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/maneeshsagar/tps/internal/core/domain"
+)
+
+type BalanceSnapshotRepository interface {
+	// Create stores a snapshot; a snapshot already taken at the same time is kept
+	Create(snapshot *domain.BalanceSnapshot) error
+	// Latest returns the most recent snapshot of an account taken at or before at
+	Latest(accountID int64, at time.Time) (*domain.BalanceSnapshot, error)
+}
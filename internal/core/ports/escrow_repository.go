@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+)
+
+type EscrowRepository interface {
+	Create(escrow *domain.Escrow) error
+	GetByID(id uuid.UUID) (*domain.Escrow, error)
+	Update(escrow *domain.Escrow) error
+	// ListExpired returns funded escrows whose deadline is at or before now
+	ListExpired(now time.Time, limit int) ([]*domain.Escrow, error)
+	WithTx(tx Transaction) EscrowRepository
+}
@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"github.com/google/uuid"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+)
+
+type FeeRuleRepository interface {
+	Create(rule *domain.FeeRule) error
+	GetByID(id uuid.UUID) (*domain.FeeRule, error)
+	// List returns all rules, active ones first, in priority order
+	List() ([]*domain.FeeRule, error)
+	// ListActive returns the active rules in priority order
+	ListActive() ([]*domain.FeeRule, error)
+	Deactivate(id uuid.UUID) error
+}
@@ -0,0 +1,9 @@
+package ports
+
+import "github.com/maneeshsagar/tps/internal/core/domain"
+
+type FXRateRepository interface {
+	Get(base, quote string) (*domain.FXRate, error)
+	Upsert(rate *domain.FXRate) error
+	List() ([]*domain.FXRate, error)
+}
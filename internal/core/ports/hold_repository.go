@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+)
+
+type HoldRepository interface {
+	Create(hold *domain.Hold) error
+	GetByID(id uuid.UUID) (*domain.Hold, error)
+	Update(hold *domain.Hold) error
+	// ListExpired returns active holds whose expiry is at or before now
+	ListExpired(now time.Time, limit int) ([]*domain.Hold, error)
+	// SumActive returns the total amount and number of active holds on an account
+	// created since a time
+	SumActive(accountID int64, since time.Time) (amount, count int64, err error)
+	WithTx(tx Transaction) HoldRepository
+}
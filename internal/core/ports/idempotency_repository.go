@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/maneeshsagar/tps/internal/core/domain"
+)
+
+type IdempotencyRepository interface {
+	// Acquire stores a new in-progress key, or takes over one that has expired or whose
+	// in-progress lease has run out. It reports false if the key is live, so exactly one
+	// request across all replicas gets it.
+	Acquire(key *domain.IdempotencyKey) (bool, error)
+	Get(key string) (*domain.IdempotencyKey, error)
+	// Complete stores the response of an in-progress key
+	Complete(key string, status int, body []byte) error
+	// Release drops an in-progress key so the request can be tried again
+	Release(key string) error
+	DeleteExpired(now time.Time) (int64, error)
+}
@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/maneeshsagar/tps/internal/core/domain"
+)
+
+type InterestRepository interface {
+	// LastAccrualDate returns the latest day accrued for an account, or the zero time if none
+	LastAccrualDate(accountID int64) (time.Time, error)
+	// CreateAccrual records a day's accrual; it reports false if that day was already accrued
+	CreateAccrual(accrual *domain.InterestAccrual) (bool, error)
+	// SumAccrued totals an account's accruals dated before a day, in interest units
+	SumAccrued(accountID int64, before time.Time) (int64, error)
+	HasPosting(accountID int64, period string) (bool, error)
+	// CreatePosting records a month's payout; it reports false if that month was already paid
+	CreatePosting(posting *domain.InterestPosting) (bool, error)
+	// SumPosted totals the interest ever paid to an account
+	SumPosted(accountID int64) (int64, error)
+	WithTx(tx Transaction) InterestRepository
+}
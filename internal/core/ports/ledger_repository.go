@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/maneeshsagar/tps/internal/core/domain"
+)
+
+type LedgerRepository interface {
+	Create(entries []*domain.LedgerEntry) error
+	// NetBefore is the net of an account's entries posted before a point in time,
+	// credits counted positive and debits negative
+	NetBefore(accountID int64, before time.Time) (int64, error)
+	// NetBetween is the net of an account's entries posted in [from, to)
+	NetBetween(accountID int64, from, to time.Time) (int64, error)
+	WithTx(tx Transaction) LedgerRepository
+}
@@ -0,0 +1,13 @@
+package ports
+
+import "github.com/maneeshsagar/tps/internal/core/domain"
+
+type LimitRepository interface {
+	// GetForAccount returns the limit set on one account
+	GetForAccount(accountID int64) (*domain.TransferLimit, error)
+	// GetForTier returns the limit set on an account type in a currency
+	GetForTier(accountType domain.AccountType, currency string) (*domain.TransferLimit, error)
+	Upsert(limit *domain.TransferLimit) error
+	Delete(limit *domain.TransferLimit) error
+	List() ([]*domain.TransferLimit, error)
+}
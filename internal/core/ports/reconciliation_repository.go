@@ -0,0 +1,19 @@
+package ports
+
+import "github.com/maneeshsagar/tps/internal/core/domain"
+
+// ReconciliationRepository runs the reconciliation checks over the whole ledger and
+// stores their outcome. Each check is one statement, so it sees a consistent snapshot.
+type ReconciliationRepository interface {
+	CountAccounts() (int64, error)
+	// FundMismatches returns the currencies whose balances and FX position do not add up to the opening balances
+	FundMismatches() ([]domain.Discrepancy, error)
+	// FlowMismatches returns up to limit accounts whose balance is not their opening balance plus the net of their transactions
+	FlowMismatches(limit int) ([]domain.Discrepancy, error)
+	// NegativeBalances returns up to limit accounts below zero beyond their overdraft limit,
+	// other than those of the allowed types
+	NegativeBalances(allowed []domain.AccountType, limit int) ([]domain.Discrepancy, error)
+	CreateRun(run *domain.ReconciliationRun) error
+	// ListRuns returns the most recent runs first
+	ListRuns(limit int) ([]*domain.ReconciliationRun, error)
+}
@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+)
+
+type StandingOrderRepository interface {
+	Create(order *domain.StandingOrder) error
+	GetByID(id uuid.UUID) (*domain.StandingOrder, error)
+	Update(order *domain.StandingOrder) error
+	ListByAccount(accountID int64) ([]*domain.StandingOrder, error)
+	// ListDue returns active orders whose next run is at or before now
+	ListDue(now time.Time, limit int) ([]*domain.StandingOrder, error)
+	CreateRun(run *domain.StandingOrderRun) error
+	ListRuns(orderID uuid.UUID) ([]*domain.StandingOrderRun, error)
+	WithTx(tx Transaction) StandingOrderRepository
+}
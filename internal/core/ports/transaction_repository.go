@@ -1,8 +1,29 @@
 package ports
 
-import "github.com/maneeshsagar/tps/internal/core/domain"
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maneeshsagar/tps/internal/core/domain"
+)
 
 type TransactionRepository interface {
 	Create(tx *domain.Transaction) error
+	GetByID(id uuid.UUID) (*domain.Transaction, error)
+	// GetByExternalRef returns the transaction an account sent with a client reference
+	GetByExternalRef(sourceAccountID int64, externalRef string) (*domain.Transaction, error)
+	// SumReversals returns the total already reversed from a transaction, as the source
+	// and destination amounts of its reversals
+	SumReversals(originalID uuid.UUID) (amount, destinationAmount int64, err error)
+	ListReversals(originalID uuid.UUID) ([]*domain.Transaction, error)
+	// SumOutgoing returns the total amount and number of transfers and withdrawals sent by
+	// an account since a time
+	SumOutgoing(accountID int64, since time.Time) (amount, count int64, err error)
+	// StreamForAccount calls fn, in posting order, for every transaction created in [from, to)
+	// that moved money in or out of an account. Split parents are left out, their legs are not.
+	StreamForAccount(accountID int64, from, to time.Time, fn func(*domain.Transaction) error) error
+	// List returns up to limit transactions matching filter, newest first, starting after
+	// the cursor if one is given
+	List(filter domain.TransactionFilter, after *domain.TxCursor, limit int) ([]*domain.Transaction, error)
 	WithTx(tx Transaction) TransactionRepository
 }
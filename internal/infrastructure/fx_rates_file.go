@@ -0,0 +1,73 @@
+package infrastructure
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/maneeshsagar/tps/internal/core/domain"
+	"github.com/maneeshsagar/tps/pkg/currency"
+)
+
+type fxRateRecord struct {
+	Base  string `json:"base"`
+	Quote string `json:"quote"`
+	Rate  string `json:"rate"`
+}
+
+// LoadFXRatesFile reads rates from a local file so the rate table can be seeded offline.
+// A .json file holds an array of {"base","quote","rate"} objects; any other file is
+// read as CSV with base,quote,rate columns and an optional header row.
+func LoadFXRatesFile(path string) ([]*domain.FXRate, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open fx rates file: %w", err)
+	}
+	defer f.Close()
+
+	var records []fxRateRecord
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		if err := json.NewDecoder(f).Decode(&records); err != nil {
+			return nil, fmt.Errorf("decode fx rates file: %w", err)
+		}
+	} else {
+		records, err = readFXRatesCSV(f)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rates := make([]*domain.FXRate, 0, len(records))
+	for i, rec := range records {
+		rate, err := currency.ParseRate(rec.Rate)
+		if err != nil {
+			return nil, fmt.Errorf("fx rates file entry %d: %w", i+1, err)
+		}
+		rates = append(rates, &domain.FXRate{Base: rec.Base, Quote: rec.Quote, Rate: rate})
+	}
+	return rates, nil
+}
+
+func readFXRatesCSV(r io.Reader) ([]fxRateRecord, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 3
+	cr.TrimLeadingSpace = true
+
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("read fx rates file: %w", err)
+	}
+
+	var records []fxRateRecord
+	for i, row := range rows {
+		if i == 0 && strings.EqualFold(row[0], "base") {
+			continue
+		}
+		records = append(records, fxRateRecord{Base: row[0], Quote: row[1], Rate: row[2]})
+	}
+	return records, nil
+}
@@ -0,0 +1,33 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+
+	"github.com/maneeshsagar/tps/logger"
+)
+
+// RunPeriodic calls fn every interval until ctx is cancelled.
+// A failed run is logged and the job carries on with the next tick. A job without a
+// positive interval is logged and not run.
+func RunPeriodic(ctx context.Context, name string, interval time.Duration, log logger.Logger, fn func(ctx context.Context) error) {
+	if interval <= 0 {
+		log.Error("job not started, interval must be positive", "job", name, "interval", interval.String())
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	log.Info("job started", "job", name, "interval", interval.String())
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("job stopped", "job", name)
+			return
+		case <-ticker.C:
+			if err := fn(ctx); err != nil {
+				log.Error("job run failed", "job", name, "err", err)
+			}
+		}
+	}
+}
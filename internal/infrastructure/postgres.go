@@ -51,12 +51,34 @@ func NewGormPostgres(cfg config.PostgresConfig, log logger.Logger) (*gorm.DB, er
 func RunMigrations(db *gorm.DB, log logger.Logger) error {
 	err := db.AutoMigrate(
 		&repository.AccountModel{},
+		&repository.AccountStatusChangeModel{},
 		&repository.TransactionModel{},
 		&repository.AsyncTransactionStatusModel{},
+		&repository.LedgerEntryModel{},
+		&repository.FXRateModel{},
+		&repository.HoldModel{},
+		&repository.FeeRuleModel{},
+		&repository.TransferLimitModel{},
+		&repository.StandingOrderModel{},
+		&repository.StandingOrderRunModel{},
+		&repository.InterestAccrualModel{},
+		&repository.InterestPostingModel{},
+		&repository.BalanceSnapshotModel{},
+		&repository.IdempotencyKeyModel{},
+		&repository.ReconciliationRunModel{},
+		&repository.EscrowModel{},
 	)
 	if err != nil {
 		return fmt.Errorf("migration failed: %w", err)
 	}
+
+	// superseded by idx_async_status_from_live_external_ref, which leaves out failed,
+	// rejected and cancelled submissions so their external_ref can be retried
+	if m := db.Migrator(); m.HasIndex(&repository.AsyncTransactionStatusModel{}, "idx_async_status_from_external_ref") {
+		if err := m.DropIndex(&repository.AsyncTransactionStatusModel{}, "idx_async_status_from_external_ref"); err != nil {
+			return fmt.Errorf("migration failed: %w", err)
+		}
+	}
 	log.Info("database schema migrated")
 	return nil
 }
@@ -0,0 +1,80 @@
+package currency
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// PercentScale is the number of basis points in 100%
+const PercentScale int64 = 10_000
+
+var percentUnit = Currency{Code: "%", MinorUnits: 2}
+
+// ParsePercent converts a percentage such as "12.5" into basis points (1250)
+func ParsePercent(s string) (int64, error) {
+	p, err := percentUnit.Parse(s)
+	if err != nil {
+		return 0, err
+	}
+	if p <= 0 || p > PercentScale {
+		return 0, fmt.Errorf("percentage must be between 0 and 100")
+	}
+	return p, nil
+}
+
+// FormatPercent renders basis points as a percentage without trailing zeros, e.g. 1250 as "12.5"
+func FormatPercent(p int64) string {
+	s := percentUnit.Format(p)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// Allocate splits total minor units into parts proportional to ratios.
+// Each part is rounded down and the units left over are handed out one at a time
+// to the parts with the largest remainders, the earliest part first on ties.
+// The parts always add up to total and the same input always gives the same split.
+func Allocate(total int64, ratios []int64) ([]int64, error) {
+	if total < 0 {
+		return nil, fmt.Errorf("total must not be negative")
+	}
+	if len(ratios) == 0 {
+		return nil, fmt.Errorf("no ratios")
+	}
+
+	sum := new(big.Int)
+	for _, r := range ratios {
+		if r < 0 {
+			return nil, fmt.Errorf("ratios must not be negative")
+		}
+		sum.Add(sum, big.NewInt(r))
+	}
+	if sum.Sign() == 0 {
+		return nil, fmt.Errorf("ratios must not all be zero")
+	}
+
+	parts := make([]int64, len(ratios))
+	rems := make([]*big.Int, len(ratios))
+	left := total
+	for i, r := range ratios {
+		q, rem := new(big.Int).QuoRem(new(big.Int).Mul(big.NewInt(total), big.NewInt(r)), sum, new(big.Int))
+		parts[i] = q.Int64()
+		rems[i] = rem
+		left -= parts[i]
+	}
+
+	order := make([]int, len(ratios))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return rems[order[a]].Cmp(rems[order[b]]) > 0
+	})
+
+	// left is smaller than the number of parts, since each part lost less than one unit
+	for i := int64(0); i < left; i++ {
+		parts[order[i]]++
+	}
+	return parts, nil
+}
@@ -0,0 +1,103 @@
+package currency
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAllocate(t *testing.T) {
+	cases := []struct {
+		total  int64
+		ratios []int64
+		want   []int64
+	}{
+		{100, []int64{1, 1, 1}, []int64{34, 33, 33}},
+		{10000, []int64{5000, 3000, 2000}, []int64{5000, 3000, 2000}},
+		{5, []int64{3333, 3333, 3334}, []int64{2, 1, 2}},
+		{1, []int64{1, 1}, []int64{1, 0}},
+		{0, []int64{1, 2}, []int64{0, 0}},
+		{999, []int64{0, 1}, []int64{0, 999}},
+		{9_000_000_000_000_000_000, []int64{1, 2}, []int64{3_000_000_000_000_000_000, 6_000_000_000_000_000_000}},
+	}
+
+	for _, tc := range cases {
+		got, err := Allocate(tc.total, tc.ratios)
+		if err != nil {
+			t.Errorf("Allocate(%d, %v): %v", tc.total, tc.ratios, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Allocate(%d, %v) = %v, want %v", tc.total, tc.ratios, got, tc.want)
+		}
+	}
+}
+
+func TestAllocate_SumsToTotal(t *testing.T) {
+	ratios := []int64{1250, 3333, 17, 5400}
+	for total := int64(0); total < 1000; total++ {
+		parts, err := Allocate(total, ratios)
+		if err != nil {
+			t.Fatalf("Allocate(%d): %v", total, err)
+		}
+		var sum int64
+		for _, p := range parts {
+			sum += p
+		}
+		if sum != total {
+			t.Fatalf("Allocate(%d) = %v, sums to %d", total, parts, sum)
+		}
+	}
+}
+
+func TestAllocate_Invalid(t *testing.T) {
+	bad := []struct {
+		total  int64
+		ratios []int64
+	}{
+		{-1, []int64{1}},
+		{10, nil},
+		{10, []int64{0, 0}},
+		{10, []int64{1, -1}},
+	}
+	for _, tc := range bad {
+		if _, err := Allocate(tc.total, tc.ratios); err == nil {
+			t.Errorf("Allocate(%d, %v) should fail", tc.total, tc.ratios)
+		}
+	}
+}
+
+func TestParsePercent(t *testing.T) {
+	cases := []struct {
+		in   string
+		want int64
+	}{
+		{"100", 10000},
+		{"12.5", 1250},
+		{"0.01", 1},
+		{"33.33", 3333},
+	}
+	for _, tc := range cases {
+		got, err := ParsePercent(tc.in)
+		if err != nil {
+			t.Errorf("ParsePercent(%q): %v", tc.in, err)
+		}
+		if got != tc.want {
+			t.Errorf("ParsePercent(%q) = %d, want %d", tc.in, got, tc.want)
+		}
+	}
+
+	for _, s := range []string{"0", "100.01", "-5", "1.234", "abc"} {
+		if _, err := ParsePercent(s); err == nil {
+			t.Errorf("ParsePercent(%q) should fail", s)
+		}
+	}
+}
+
+func TestFormatPercent(t *testing.T) {
+	cases := map[int64]string{10000: "100", 1250: "12.5", 1: "0.01", 3333: "33.33"}
+	for in, want := range cases {
+		if got := FormatPercent(in); got != want {
+			t.Errorf("FormatPercent(%d) = %q, want %q", in, got, want)
+		}
+	}
+}
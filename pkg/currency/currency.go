@@ -2,52 +2,149 @@ package currency
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 )
 
-// RupeesToPaise converts "100.50" to 10050
-func RupeesToPaise(s string) (int64, error) {
+// DefaultCode is the currency used for accounts created without an explicit currency
+const DefaultCode = "INR"
+
+// Currency is an ISO 4217 currency together with the number of minor units
+// (decimal places) it is quoted in, e.g. 2 for USD, 0 for JPY and 3 for KWD.
+type Currency struct {
+	Code       string
+	MinorUnits int
+}
+
+// supported lists the ISO 4217 currencies the system accepts
+var supported = map[string]Currency{
+	"AED": {"AED", 2},
+	"AUD": {"AUD", 2},
+	"BHD": {"BHD", 3},
+	"CAD": {"CAD", 2},
+	"CHF": {"CHF", 2},
+	"CNY": {"CNY", 2},
+	"EUR": {"EUR", 2},
+	"GBP": {"GBP", 2},
+	"HKD": {"HKD", 2},
+	"INR": {"INR", 2},
+	"JOD": {"JOD", 3},
+	"JPY": {"JPY", 0},
+	"KRW": {"KRW", 0},
+	"KWD": {"KWD", 3},
+	"OMR": {"OMR", 3},
+	"SGD": {"SGD", 2},
+	"USD": {"USD", 2},
+}
+
+// Lookup returns the currency for an ISO 4217 code, case-insensitively
+func Lookup(code string) (Currency, error) {
+	c, ok := supported[strings.ToUpper(strings.TrimSpace(code))]
+	if !ok {
+		return Currency{}, fmt.Errorf("unsupported currency %q", code)
+	}
+	return c, nil
+}
+
+// Parse converts a decimal string into minor units, e.g. "100.50" USD to 10050
+// and "5" KWD to 5000. It rejects more decimal places than the currency allows.
+func (c Currency) Parse(s string) (int64, error) {
 	s = strings.TrimSpace(s)
 	if s == "" {
 		return 0, fmt.Errorf("empty amount")
 	}
 
-	parts := strings.Split(s, ".")
-	if len(parts) > 2 {
+	neg := strings.HasPrefix(s, "-")
+	if neg {
+		s = s[1:]
+	}
+
+	whole, frac, hasDot := strings.Cut(s, ".")
+	if !isDigits(whole) || (hasDot && !isDigits(frac)) {
 		return 0, fmt.Errorf("invalid format")
 	}
+	if len(frac) > c.MinorUnits {
+		return 0, fmt.Errorf("%s allows at most %d decimal places", c.Code, c.MinorUnits)
+	}
 
-	whole, err := strconv.ParseInt(parts[0], 10, 64)
+	w, err := strconv.ParseInt(whole, 10, 64)
 	if err != nil {
 		return 0, fmt.Errorf("invalid format")
 	}
-	paise := whole * 100
 
-	if len(parts) == 2 {
-		dec := parts[1]
-		if len(dec) == 1 {
-			dec += "0"
-		}
-		p, err := strconv.ParseInt(dec, 10, 64)
+	var f int64
+	if c.MinorUnits > 0 {
+		f, err = strconv.ParseInt(frac+strings.Repeat("0", c.MinorUnits-len(frac)), 10, 64)
 		if err != nil {
 			return 0, fmt.Errorf("invalid format")
 		}
-		if whole >= 0 && !strings.HasPrefix(s, "-") {
-			paise += p
-		} else {
-			paise -= p
-		}
 	}
-	return paise, nil
+
+	scale := c.scale()
+	if w > (math.MaxInt64-f)/scale {
+		return 0, fmt.Errorf("amount out of range")
+	}
+	v := w*scale + f
+	if neg {
+		v = -v
+	}
+	return v, nil
+}
+
+// Format converts minor units into a decimal string, e.g. 10050 USD to "100.50"
+func (c Currency) Format(minor int64) string {
+	sign := ""
+	abs := uint64(minor)
+	if minor < 0 {
+		sign = "-"
+		abs = uint64(-(minor + 1)) + 1
+	}
+	if c.MinorUnits == 0 {
+		return sign + strconv.FormatUint(abs, 10)
+	}
+	scale := uint64(c.scale())
+	return fmt.Sprintf("%s%d.%0*d", sign, abs/scale, c.MinorUnits, abs%scale)
+}
+
+// scale returns 10^MinorUnits, the number of minor units in one major unit
+func (c Currency) scale() int64 {
+	s := int64(1)
+	for i := 0; i < c.MinorUnits; i++ {
+		s *= 10
+	}
+	return s
+}
+
+// Money is an amount in minor units of a specific currency
+type Money struct {
+	Amount   int64
+	Currency Currency
+}
+
+// String renders the amount with its currency code, e.g. "100.50 USD"
+func (m Money) String() string {
+	return m.Currency.Format(m.Amount) + " " + m.Currency.Code
+}
+
+// RupeesToPaise converts "100.50" to 10050
+func RupeesToPaise(s string) (int64, error) {
+	return supported["INR"].Parse(s)
 }
 
 // PaiseToRupees converts 10050 to "100.50"
 func PaiseToRupees(p int64) string {
-	r := p / 100
-	rem := p % 100
-	if rem < 0 {
-		rem = -rem
+	return supported["INR"].Format(p)
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
 	}
-	return fmt.Sprintf("%d.%02d", r, rem)
+	return true
 }
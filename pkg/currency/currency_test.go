@@ -53,3 +53,90 @@ func TestPaiseToRupees(t *testing.T) {
 		}
 	}
 }
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		code string
+		in   string
+		want int64
+	}{
+		{"USD", "100.50", 10050},
+		{"usd", "1", 100},
+		{"JPY", "1500", 1500},
+		{"KWD", "5", 5000},
+		{"KWD", "1.234", 1234},
+		{"KWD", "0.5", 500},
+		{"EUR", "-2.05", -205},
+	}
+
+	for _, tc := range cases {
+		cur, err := Lookup(tc.code)
+		if err != nil {
+			t.Fatalf("Lookup(%q): %v", tc.code, err)
+		}
+		got, err := cur.Parse(tc.in)
+		if err != nil {
+			t.Errorf("Parse(%q, %s): %v", tc.in, tc.code, err)
+		}
+		if got != tc.want {
+			t.Errorf("Parse(%q, %s) = %d, want %d", tc.in, tc.code, got, tc.want)
+		}
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	cases := []struct {
+		code string
+		in   string
+	}{
+		{"JPY", "1.5"},
+		{"USD", "1.234"},
+		{"KWD", "1.2345"},
+		{"USD", "1."},
+		{"USD", ".5"},
+		{"USD", "+1"},
+		{"USD", "1e3"},
+		{"USD", "99999999999999999999"},
+	}
+
+	for _, tc := range cases {
+		cur, _ := Lookup(tc.code)
+		if _, err := cur.Parse(tc.in); err == nil {
+			t.Errorf("Parse(%q, %s) should fail", tc.in, tc.code)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	cases := []struct {
+		code string
+		in   int64
+		want string
+	}{
+		{"USD", 10050, "100.50"},
+		{"USD", -5, "-0.05"},
+		{"JPY", 1500, "1500"},
+		{"KWD", 5000, "5.000"},
+		{"KWD", 1234, "1.234"},
+	}
+
+	for _, tc := range cases {
+		cur, _ := Lookup(tc.code)
+		if got := cur.Format(tc.in); got != tc.want {
+			t.Errorf("Format(%d, %s) = %q, want %q", tc.in, tc.code, got, tc.want)
+		}
+	}
+}
+
+func TestLookup_Unsupported(t *testing.T) {
+	if _, err := Lookup("XYZ"); err == nil {
+		t.Error("Lookup(XYZ) should fail")
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	cur, _ := Lookup("KWD")
+	if got := (Money{Amount: 1500, Currency: cur}).String(); got != "1.500 KWD" {
+		t.Errorf("Money.String() = %q", got)
+	}
+}
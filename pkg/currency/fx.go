@@ -0,0 +1,84 @@
+package currency
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// RateDecimals is the precision FX rates are stored with. A rate is kept as an
+// integer number of 1e-8 units, so 83.25 is stored as 8325000000.
+const RateDecimals = 8
+
+// RateScale is 10^RateDecimals
+const RateScale int64 = 100_000_000
+
+var rateUnit = Currency{Code: "RATE", MinorUnits: RateDecimals}
+
+// ParseRate converts a decimal rate such as "83.25" into its scaled integer form
+func ParseRate(s string) (int64, error) {
+	r, err := rateUnit.Parse(s)
+	if err != nil {
+		return 0, err
+	}
+	if r <= 0 {
+		return 0, fmt.Errorf("rate must be positive")
+	}
+	return r, nil
+}
+
+// FormatRate renders a scaled rate without trailing zeros, e.g. 8325000000 as "83.25"
+func FormatRate(r int64) string {
+	s := rateUnit.Format(r)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// Convert converts amount minor units of from into minor units of to, where
+// rate is the scaled price of one unit of from expressed in to.
+// The result is rounded half up to the nearest minor unit of the target currency.
+func Convert(amount int64, from, to Currency, rate int64) (int64, error) {
+	if amount < 0 || rate <= 0 {
+		return 0, fmt.Errorf("amount and rate must be positive")
+	}
+
+	num := new(big.Int).Mul(big.NewInt(amount), big.NewInt(rate))
+	num.Mul(num, big.NewInt(to.scale()))
+	den := new(big.Int).Mul(big.NewInt(RateScale), big.NewInt(from.scale()))
+
+	return divRound(num, den)
+}
+
+// MulDiv returns a*b/c rounded half up, without overflowing on the intermediate product.
+// It is meant for prorating non-negative amounts, e.g. MulDiv(part, total, whole).
+func MulDiv(a, b, c int64) (int64, error) {
+	if a < 0 || b < 0 || c <= 0 {
+		return 0, fmt.Errorf("operands must be positive")
+	}
+	return divRound(new(big.Int).Mul(big.NewInt(a), big.NewInt(b)), big.NewInt(c))
+}
+
+// MulDivDown is MulDiv rounded down. Prorating a running total with it never reaches
+// the whole before the part does, e.g. MulDivDown(part, total, whole) < total for part < whole.
+func MulDivDown(a, b, c int64) (int64, error) {
+	if a < 0 || b < 0 || c <= 0 {
+		return 0, fmt.Errorf("operands must be positive")
+	}
+	q := new(big.Int).Quo(new(big.Int).Mul(big.NewInt(a), big.NewInt(b)), big.NewInt(c))
+	if !q.IsInt64() {
+		return 0, fmt.Errorf("result out of range")
+	}
+	return q.Int64(), nil
+}
+
+// divRound divides num by den rounding half up
+func divRound(num, den *big.Int) (int64, error) {
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Mul(r, big.NewInt(2)).Cmp(den) >= 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	if !q.IsInt64() {
+		return 0, fmt.Errorf("result out of range")
+	}
+	return q.Int64(), nil
+}
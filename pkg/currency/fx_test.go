@@ -0,0 +1,124 @@
+package currency
+
+import "testing"
+
+func TestParseRate(t *testing.T) {
+	cases := []struct {
+		in   string
+		want int64
+	}{
+		{"83.25", 8325000000},
+		{"1", 100000000},
+		{"0.00000001", 1},
+	}
+
+	for _, tc := range cases {
+		got, err := ParseRate(tc.in)
+		if err != nil {
+			t.Errorf("ParseRate(%q): %v", tc.in, err)
+		}
+		if got != tc.want {
+			t.Errorf("ParseRate(%q) = %d, want %d", tc.in, got, tc.want)
+		}
+		if s := FormatRate(got); s != tc.in {
+			t.Errorf("FormatRate(%d) = %q, want %q", got, s, tc.in)
+		}
+	}
+
+	for _, s := range []string{"0", "-1", "1.123456789", "abc"} {
+		if _, err := ParseRate(s); err == nil {
+			t.Errorf("ParseRate(%q) should fail", s)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	usd, _ := Lookup("USD")
+	inr, _ := Lookup("INR")
+	jpy, _ := Lookup("JPY")
+	kwd, _ := Lookup("KWD")
+
+	cases := []struct {
+		name     string
+		amount   int64
+		from, to Currency
+		rate     string
+		want     int64
+	}{
+		{"usd to inr", 10050, usd, inr, "83.25", 836663}, // 100.50 * 83.25 = 8366.625 -> 8366.63
+		{"inr to usd", 100000, inr, usd, "0.012", 1200},
+		{"usd to jpy rounds half up", 150, usd, jpy, "149.5", 224}, // 1.50 * 149.5 = 224.25
+		{"jpy to usd", 1000, jpy, usd, "0.00667", 667},
+		{"usd to kwd", 100000, usd, kwd, "0.30712", 307120},
+		{"kwd to usd", 1, kwd, usd, "3.25", 0}, // 0.001 * 3.25 = 0.00325
+		{"half rounds up", 1, usd, usd, "0.5", 1},
+	}
+
+	for _, tc := range cases {
+		rate, err := ParseRate(tc.rate)
+		if err != nil {
+			t.Fatalf("%s: ParseRate: %v", tc.name, err)
+		}
+		got, err := Convert(tc.amount, tc.from, tc.to, rate)
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+		}
+		if got != tc.want {
+			t.Errorf("%s: Convert = %d, want %d", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestMulDiv(t *testing.T) {
+	cases := []struct {
+		a, b, c int64
+		want    int64
+	}{
+		{50, 100, 200, 25},
+		{1, 1, 3, 0},
+		{2, 1, 3, 1},
+		{1, 1, 2, 1},
+		{9_000_000_000_000_000_000, 3, 9, 3_000_000_000_000_000_000},
+	}
+
+	for _, tc := range cases {
+		got, err := MulDiv(tc.a, tc.b, tc.c)
+		if err != nil {
+			t.Errorf("MulDiv(%d, %d, %d): %v", tc.a, tc.b, tc.c, err)
+		}
+		if got != tc.want {
+			t.Errorf("MulDiv(%d, %d, %d) = %d, want %d", tc.a, tc.b, tc.c, got, tc.want)
+		}
+	}
+
+	if _, err := MulDiv(1, 1, 0); err == nil {
+		t.Error("MulDiv by zero should fail")
+	}
+}
+
+func TestMulDivDown(t *testing.T) {
+	cases := []struct {
+		a, b, c int64
+		want    int64
+	}{
+		{50, 100, 200, 25},
+		{2, 1, 3, 0},
+		{1, 1, 2, 0},
+		{299, 100, 300, 99},
+		{9_000_000_000_000_000_000, 3, 9, 3_000_000_000_000_000_000},
+	}
+
+	for _, tc := range cases {
+		got, err := MulDivDown(tc.a, tc.b, tc.c)
+		if err != nil {
+			t.Errorf("MulDivDown(%d, %d, %d): %v", tc.a, tc.b, tc.c, err)
+		}
+		if got != tc.want {
+			t.Errorf("MulDivDown(%d, %d, %d) = %d, want %d", tc.a, tc.b, tc.c, got, tc.want)
+		}
+	}
+
+	if _, err := MulDivDown(1, 1, 0); err == nil {
+		t.Error("MulDivDown by zero should fail")
+	}
+}
@@ -0,0 +1,59 @@
+// Package schedule computes the occurrences of recurring events such as standing orders.
+package schedule
+
+import "time"
+
+// Schedule yields the occurrences of a recurring event
+type Schedule interface {
+	// Next returns the first occurrence strictly after t
+	Next(t time.Time) time.Time
+}
+
+// Daily occurs every day at the time of day of start, beginning at start
+func Daily(start time.Time) Schedule {
+	return interval{start: start, period: 24 * time.Hour, step: func(t time.Time, n int) time.Time { return t.AddDate(0, 0, n) }}
+}
+
+// Weekly occurs every week on the weekday and time of start, beginning at start
+func Weekly(start time.Time) Schedule {
+	return interval{start: start, period: 7 * 24 * time.Hour, step: func(t time.Time, n int) time.Time { return t.AddDate(0, 0, 7*n) }}
+}
+
+// Monthly occurs every month on the day of month of start. In shorter months the
+// occurrence falls on the last day, so a schedule starting on the 31st runs on 30 April.
+func Monthly(start time.Time) Schedule {
+	return interval{start: start, period: 31 * 24 * time.Hour, step: addMonths}
+}
+
+type interval struct {
+	start time.Time
+	// period is the longest a single step can take, used to skip ahead in Next
+	period time.Duration
+	step   func(start time.Time, n int) time.Time
+}
+
+func (s interval) Next(t time.Time) time.Time {
+	if t.Before(s.start) {
+		return s.start
+	}
+	// skip the steps that have certainly passed, then walk forward to the next occurrence
+	n := max(int(t.Sub(s.start)/s.period)-1, 0)
+	for {
+		next := s.step(s.start, n)
+		if next.After(t) {
+			return next
+		}
+		n++
+	}
+}
+
+// addMonths adds n calendar months to t, clamping the day to the end of the target month
+func addMonths(t time.Time, n int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(n), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	lastDay := first.AddDate(0, 1, -1).Day()
+	day := t.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return first.AddDate(0, 0, day-1)
+}
@@ -0,0 +1,90 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestIntervalSchedules(t *testing.T) {
+	start := date("2024-01-31 09:00")
+
+	cases := []struct {
+		name  string
+		sched Schedule
+		after string
+		want  string
+	}{
+		{"daily before start", Daily(start), "2024-01-01 00:00", "2024-01-31 09:00"},
+		{"daily at start", Daily(start), "2024-01-31 09:00", "2024-02-01 09:00"},
+		{"daily later", Daily(start), "2024-03-10 10:00", "2024-03-11 09:00"},
+		{"daily years later", Daily(start), "2034-03-10 08:00", "2034-03-10 09:00"},
+		{"weekly", Weekly(start), "2024-02-01 00:00", "2024-02-07 09:00"},
+		{"monthly clamps to month end", Monthly(start), "2024-01-31 09:00", "2024-02-29 09:00"},
+		{"monthly keeps start day", Monthly(start), "2024-02-29 09:00", "2024-03-31 09:00"},
+		{"monthly april", Monthly(start), "2024-04-01 00:00", "2024-04-30 09:00"},
+		{"monthly next year", Monthly(start), "2024-12-31 09:00", "2025-01-31 09:00"},
+	}
+
+	for _, tc := range cases {
+		if got := tc.sched.Next(date(tc.after)); !got.Equal(date(tc.want)) {
+			t.Errorf("%s: Next(%s) = %s, want %s", tc.name, tc.after, got, tc.want)
+		}
+	}
+}
+
+func TestCron(t *testing.T) {
+	cases := []struct {
+		expr  string
+		after string
+		want  string
+	}{
+		{"*/15 * * * *", "2024-01-01 10:07", "2024-01-01 10:15"},
+		{"0 9 * * *", "2024-01-01 09:00", "2024-01-02 09:00"},
+		{"0 9 1 * *", "2024-01-15 00:00", "2024-02-01 09:00"},
+		{"30 18 * * 5", "2024-01-01 00:00", "2024-01-05 18:30"}, // first friday
+		{"0 0 * * 7", "2024-01-01 00:00", "2024-01-07 00:00"},   // 7 is sunday
+		{"0 12 1-7 * 1-5", "2024-01-01 12:00", "2024-01-02 12:00"},
+		{"0 0 29 2 *", "2024-03-01 00:00", "2028-02-29 00:00"},
+		{"0 8,20 * * *", "2024-01-01 08:00", "2024-01-01 20:00"},
+		{"0 0 15 * 1", "2024-01-02 00:00", "2024-01-08 00:00"}, // monday or the 15th
+		{"0 0 * * 5-7", "2024-01-01 00:00", "2024-01-05 00:00"},
+		{"0 9 * * *", "2034-06-01 10:00", "2034-06-02 09:00"},
+	}
+
+	for _, tc := range cases {
+		sched, err := ParseCron(tc.expr, time.UTC)
+		if err != nil {
+			t.Fatalf("ParseCron(%q): %v", tc.expr, err)
+		}
+		if got := sched.Next(date(tc.after)); !got.Equal(date(tc.want)) {
+			t.Errorf("%q: Next(%s) = %s, want %s", tc.expr, tc.after, got, tc.want)
+		}
+	}
+}
+
+func TestCron_Invalid(t *testing.T) {
+	bad := []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "*/0 * * * *", "5-1 * * * *", "a * * * *"}
+	for _, expr := range bad {
+		if _, err := ParseCron(expr, time.UTC); err == nil {
+			t.Errorf("ParseCron(%q) should fail", expr)
+		}
+	}
+}
+
+func TestCron_NeverMatches(t *testing.T) {
+	sched, err := ParseCron("0 0 30 2 *", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sched.Next(date("2024-01-01 00:00")); !got.IsZero() {
+		t.Errorf("Next = %s, want zero time", got)
+	}
+}